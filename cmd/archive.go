@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive [--older-than=DURATION]",
+	Short: "Move closed tickets to the archive",
+	Long: `Move closed tickets into .tickets/.archive/, hiding them from all commands
+while keeping the files around.
+
+Only closed tickets that are safe to remove are archived (the same rules as
+clean: no dependants, no non-closed children, no links).
+
+Use --older-than to only archive tickets closed longer ago than the duration
+(e.g. 30d, 2w, 36h), keeping recently closed tickets visible. The closure time
+is approximated by the file's modification time.`,
+	Args: cobra.NoArgs,
+	RunE: runArchive,
+}
+
+var (
+	archiveOlderThan string
+	archiveDryRun    bool
+)
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.Flags().StringVar(&archiveOlderThan, "older-than", "", "Only archive tickets closed longer ago than this (e.g. 30d)")
+	archiveCmd.Flags().BoolVar(&archiveDryRun, "dry-run", false, "Show what would be archived without moving anything")
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	var cutoff time.Time
+	if archiveOlderThan != "" {
+		age, err := parseAge(archiveOlderThan)
+		if err != nil {
+			return err
+		}
+		cutoff = time.Now().Add(-age)
+	}
+
+	graph, err := loadGraph()
+	if err != nil {
+		return err
+	}
+
+	modTimes, err := store.ModTimes()
+	if err != nil {
+		return err
+	}
+
+	var archivable []*ticket.Ticket
+	for _, t := range graph.Tickets() {
+		if t.Status != ticket.StatusClosed {
+			continue
+		}
+		if cleanBlockReason(t, graph) != "" {
+			continue
+		}
+		if !cutoff.IsZero() && !modTimes[t.ID].Before(cutoff) {
+			continue
+		}
+		archivable = append(archivable, t)
+	}
+
+	if len(archivable) == 0 {
+		fmt.Println("No tickets to archive.")
+		return nil
+	}
+
+	sort.Slice(archivable, func(i, j int) bool {
+		return archivable[i].ID < archivable[j].ID
+	})
+
+	if archiveDryRun {
+		fmt.Printf("Would archive %d ticket(s):\n", len(archivable))
+		for _, t := range archivable {
+			fmt.Printf("  %s [%s] %s\n", t.ID, t.Status, t.Title)
+		}
+		return nil
+	}
+
+	archiver, ok := store.(ticket.Archiver)
+	if !ok {
+		return fmt.Errorf("the ticket store does not support archiving")
+	}
+
+	archived := 0
+	for _, t := range archivable {
+		if _, err := archiver.Archive(t.ID); err != nil {
+			fmt.Fprintf(cmd.OutOrStderr(), "Warning: failed to archive %s: %v\n", t.ID, err)
+			continue
+		}
+		fmt.Printf("Archived: %s\n", t.ID)
+		archived++
+	}
+
+	fmt.Printf("\nArchived %d ticket(s).\n", archived)
+	return nil
+}
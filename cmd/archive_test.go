@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestArchiveOlderThan(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	oldID, _ := ctx.exec("new", "Closed long ago")
+	oldID = strings.TrimSpace(oldID)
+	ctx.exec("close", oldID)
+
+	recentID, _ := ctx.exec("new", "Closed recently")
+	recentID = strings.TrimSpace(recentID)
+	ctx.exec("close", recentID)
+
+	openID, _ := ctx.exec("new", "Still open")
+	openID = strings.TrimSpace(openID)
+
+	past := time.Now().Add(-60 * 24 * time.Hour)
+	if err := ctx.store().SetModTime(oldID, past); err != nil {
+		t.Fatalf("SetModTime: %v", err)
+	}
+	oldPath := filepath.Join(ctx.ticketsDir, oldID+".md")
+
+	output, err := ctx.exec("archive", "--older-than", "30d")
+	if err != nil {
+		t.Fatalf("archive error: %v", err)
+	}
+	if !strings.Contains(output, "Archived: "+oldID) {
+		t.Errorf("expected %s to be archived, got: %s", oldID, output)
+	}
+	if strings.Contains(output, recentID) || strings.Contains(output, openID) {
+		t.Errorf("expected only %s to be archived, got: %s", oldID, output)
+	}
+
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be moved out of the tickets directory", oldPath)
+	}
+	if _, err := os.Stat(filepath.Join(ctx.ticketsDir, ticket.ArchiveDir, oldID+".md")); err != nil {
+		t.Errorf("expected archived file: %v", err)
+	}
+
+	tickets, err := ctx.store().List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(tickets) != 2 {
+		t.Errorf("expected 2 remaining tickets, got %d", len(tickets))
+	}
+}
+
+func TestArchiveDryRun(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Closed")
+	id = strings.TrimSpace(id)
+	ctx.exec("close", id)
+
+	output, err := ctx.exec("archive", "--dry-run")
+	if err != nil {
+		t.Fatalf("archive error: %v", err)
+	}
+	if !strings.Contains(output, "Would archive 1 ticket(s)") {
+		t.Errorf("expected dry-run summary, got: %s", output)
+	}
+	if _, err := os.Stat(filepath.Join(ctx.ticketsDir, id+".md")); err != nil {
+		t.Errorf("dry run should not move tickets: %v", err)
+	}
+}
+
+func TestArchiveInvalidDuration(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	if _, err := ctx.exec("archive", "--older-than", "soon"); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
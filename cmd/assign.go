@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var assignCmd = &cobra.Command{
+	Use:   "assign <id> <user>",
+	Short: "Set a ticket's assignee",
+	Long: `Set the assignee of a ticket. "me" is the current user
+($TK_USER, else git user.name, else $USER):
+  tk assign abc alice
+  tk assign abc me`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAssign,
+}
+
+var unassignCmd = &cobra.Command{
+	Use:   "unassign <id>",
+	Short: "Clear a ticket's assignee",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUnassign,
+}
+
+func init() {
+	rootCmd.AddCommand(assignCmd)
+	rootCmd.AddCommand(unassignCmd)
+}
+
+func runAssign(cmd *cobra.Command, args []string) error {
+	user, err := resolveUser(strings.TrimSpace(args[1]))
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return fmt.Errorf("user cannot be empty; use unassign to clear the assignee")
+	}
+
+	id, err := store.UpdateField(args[0], "assignee", user)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Assigned %s to %s\n", id, user)
+	return nil
+}
+
+func runUnassign(cmd *cobra.Command, args []string) error {
+	// An empty value removes the field
+	id, err := store.UpdateFields(args[0], map[string]string{"assignee": ""})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Unassigned %s\n", id)
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssignCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Assign me", "--assignee", "bob")
+	id = strings.TrimSpace(id)
+
+	// Partial IDs resolve as for other commands
+	output, err := ctx.exec("assign", id[len(id)-4:], "alice")
+	if err != nil {
+		t.Fatalf("assign error: %v", err)
+	}
+	if strings.TrimSpace(output) != "Assigned "+id+" to alice" {
+		t.Errorf("unexpected output: %q", output)
+	}
+	tk, _ := ctx.store().Get(id)
+	if tk.Assignee != "alice" {
+		t.Errorf("Assignee = %q, want alice", tk.Assignee)
+	}
+
+	origCurrentUser := currentUser
+	currentUser = func() string { return "carol" }
+	defer func() { currentUser = origCurrentUser }()
+	if _, err := ctx.exec("assign", id, "me"); err != nil {
+		t.Fatalf("assign me error: %v", err)
+	}
+	tk, _ = ctx.store().Get(id)
+	if tk.Assignee != "carol" {
+		t.Errorf("Assignee = %q, want carol for me", tk.Assignee)
+	}
+
+	output, err = ctx.exec("unassign", id)
+	if err != nil {
+		t.Fatalf("unassign error: %v", err)
+	}
+	if strings.TrimSpace(output) != "Unassigned "+id {
+		t.Errorf("unexpected output: %q", output)
+	}
+	_, content, _ := ctx.store().ReadRaw(id)
+	if strings.Contains(content, "assignee:") {
+		t.Errorf("unassign should remove the assignee field:\n%s", content)
+	}
+
+	for _, args := range [][]string{{"assign", "nonexistent", "alice"}, {"unassign", "nonexistent"}} {
+		if _, err := ctx.exec(args...); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("%s on a missing ticket: err = %v, want not found", args[0], err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// batchResult reports the outcome of one --batch-json operation
+type batchResult struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// batchOp validates and applies a single decoded operation, returning the
+// resolved ID of the ticket it changed
+type batchOp func(item json.RawMessage) (string, error)
+
+// orBatchJSON uses the given positional args validator unless a --batch-json
+// input was supplied, in which case positional args are rejected
+func orBatchJSON(path *string, args cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, a []string) error {
+		if *path != "" {
+			return cobra.NoArgs(cmd, a)
+		}
+		return args(cmd, a)
+	}
+}
+
+// readBatchJSON reads a JSON array of operations from a file, or stdin for "-"
+func readBatchJSON(cmd *cobra.Command, path string) ([]json.RawMessage, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading batch input: %w", err)
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("parsing batch input: expected a JSON array of operations: %w", err)
+	}
+	return items, nil
+}
+
+// decodeBatchItem strictly decodes a single operation, rejecting unknown fields
+func decodeBatchItem(item json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(item))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid operation: %w", err)
+	}
+	return nil
+}
+
+// runBatchJSON applies each operation in order, continuing past failures, and
+// prints the per-item results as a JSON array. The command fails if any
+// operation failed.
+func runBatchJSON(cmd *cobra.Command, path string, op batchOp) error {
+	items, err := readBatchJSON(cmd, path)
+	if err != nil {
+		return err
+	}
+
+	results := make([]batchResult, 0, len(items))
+	failed := false
+	for i, item := range items {
+		result := batchResult{Index: i}
+		id, err := op(item)
+		if err != nil {
+			result.Error = err.Error()
+			failed = true
+		} else {
+			result.ID = id
+			result.OK = true
+		}
+		results = append(results, result)
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+
+	if failed {
+		return failSilently(cmd, exitError)
+	}
+	return nil
+}
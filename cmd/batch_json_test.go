@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// writeBatchFile writes batch JSON input to a temp file and returns its path
+func writeBatchFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "batch.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write batch file: %v", err)
+	}
+	return path
+}
+
+// parseBatchResults decodes the JSON results line from command output
+func parseBatchResults(t *testing.T, output string) []batchResult {
+	t.Helper()
+	var results []batchResult
+	line := strings.TrimSpace(strings.Split(strings.TrimSpace(output), "\n")[0])
+	if err := json.Unmarshal([]byte(line), &results); err != nil {
+		t.Fatalf("failed to parse batch results %q: %v", output, err)
+	}
+	return results
+}
+
+func TestStatusBatchJSON(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Batch target")
+	id = strings.TrimSpace(id)
+
+	path := writeBatchFile(t, `[{"id":"`+id+`","status":"closed"},{"id":"`+id+`","status":"bogus"},{"id":"nope-0000","status":"open"}]`)
+
+	output, err := ctx.exec("status", "--batch-json", path)
+	var exit silentExit
+	if !errors.As(err, &exit) || exit.code != exitError {
+		t.Fatalf("expected silent exit %d, got %v", exitError, err)
+	}
+
+	results := parseBatchResults(t, output)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d: %s", len(results), output)
+	}
+	if !results[0].OK || results[0].ID != id {
+		t.Errorf("expected first op to succeed for %s, got %+v", id, results[0])
+	}
+	if results[1].OK || !strings.Contains(results[1].Error, "invalid status") {
+		t.Errorf("expected invalid status error, got %+v", results[1])
+	}
+	if results[2].OK || results[2].Index != 2 || results[2].Error == "" {
+		t.Errorf("expected not found error, got %+v", results[2])
+	}
+
+	tk, err := ctx.store().Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if tk.Status != ticket.StatusClosed {
+		t.Errorf("expected valid op to be applied, status = %s", tk.Status)
+	}
+}
+
+func TestStatusBatchJSONRejectsArgs(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	path := writeBatchFile(t, `[]`)
+	if _, err := ctx.exec("status", "--batch-json", path, "abc", "closed"); err == nil {
+		t.Error("expected error when combining --batch-json with positional args")
+	}
+}
+
+func TestDepAndLinkBatchJSON(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	a, _ := ctx.exec("new", "A")
+	a = strings.TrimSpace(a)
+	b, _ := ctx.exec("new", "B")
+	b = strings.TrimSpace(b)
+
+	depPath := writeBatchFile(t, `[{"id":"`+a+`","dep":"`+b+`"},{"id":"`+a+`","extra":"x"}]`)
+	output, err := ctx.exec("dep", "--batch-json", depPath)
+	if err == nil {
+		t.Fatal("expected failure for invalid dep op")
+	}
+	results := parseBatchResults(t, output)
+	if !results[0].OK || results[1].OK {
+		t.Errorf("unexpected dep results: %+v", results)
+	}
+
+	linkPath := writeBatchFile(t, `[{"id":"`+a+`","target":"`+b+`"}]`)
+	output, err = ctx.exec("link", "--batch-json", linkPath)
+	if err != nil {
+		t.Fatalf("link --batch-json error: %v", err)
+	}
+	if results := parseBatchResults(t, output); !results[0].OK {
+		t.Errorf("expected link op to succeed, got %+v", results[0])
+	}
+
+	ta, _ := ctx.store().Get(a)
+	if len(ta.Deps) != 1 || ta.Deps[0] != b {
+		t.Errorf("expected %s to depend on %s, got %v", a, b, ta.Deps)
+	}
+	if len(ta.Links) != 1 || ta.Links[0] != b {
+		t.Errorf("expected %s to link to %s, got %v", a, b, ta.Links)
+	}
+}
@@ -14,12 +14,33 @@ var blockedCmd = &cobra.Command{
 	Short: "List blocked tickets",
 	Long: `List open/in-progress tickets with unresolved dependencies.
 Shows only the unclosed blockers for each ticket.
-Sorted by priority (ascending, 0=highest), then by ID.`,
+Sorted by priority (ascending, 0=highest), then by ID.
+
+Use --sort blockers to order by the number of open blockers (fewest first),
+and --reverse to invert the order.
+
+Use --assignee to only show tickets assigned to someone; "me" is the
+current user ($TK_USER, else git user.name, else $USER):
+  tk blocked --assignee me
+
+Use --assume-closed X,Y to see what would still be blocked if those tickets
+were closed. Nothing is changed.`,
 	RunE: runBlocked,
 }
 
+var (
+	blockedSort    string
+	blockedReverse bool
+	blockedAssume  []string
+	blockedAssign  string
+)
+
 func init() {
 	rootCmd.AddCommand(blockedCmd)
+	blockedCmd.Flags().StringVar(&blockedSort, "sort", "priority", "Sort order (priority|blockers)")
+	blockedCmd.Flags().BoolVar(&blockedReverse, "reverse", false, "Reverse the sort order")
+	blockedCmd.Flags().StringVarP(&blockedAssign, "assignee", "a", "", "Only show tickets assigned to this user (me for the current user)")
+	blockedCmd.Flags().StringSliceVar(&blockedAssume, "assume-closed", nil, "Treat these tickets as closed (what-if, nothing is changed)")
 }
 
 type blockedTicket struct {
@@ -28,55 +49,49 @@ type blockedTicket struct {
 }
 
 func runBlocked(cmd *cobra.Command, args []string) error {
-	tickets, err := store.List()
+	if blockedSort != "priority" && blockedSort != "blockers" {
+		return fmt.Errorf("invalid sort '%s'. Must be one of: priority, blockers", blockedSort)
+	}
+	assignee, err := resolveUser(blockedAssign)
 	if err != nil {
 		return err
 	}
 
-	// Build status map
-	statusMap := make(map[string]ticket.Status)
-	for _, t := range tickets {
-		statusMap[t.ID] = t.Status
+	graph, err := loadGraph()
+	if err != nil {
+		return err
+	}
+	if err := assumeClosed(cmd, graph, blockedAssume); err != nil {
+		return err
 	}
 
 	// Filter blocked tickets
 	var blocked []blockedTicket
-	for _, t := range tickets {
-		// Must be open or in_progress
-		if t.Status != ticket.StatusOpen && t.Status != ticket.StatusInProgress {
+	for _, t := range graph.Tickets() {
+		if !graph.IsBlocked(t.ID) {
 			continue
 		}
-
-		// Must have deps
-		if len(t.Deps) == 0 {
+		if assignee != "" && t.Assignee != assignee {
 			continue
 		}
-
-		// Find unclosed blockers
-		var blockers []string
-		for _, dep := range t.Deps {
-			if statusMap[dep] != ticket.StatusClosed {
-				blockers = append(blockers, dep)
-			}
-		}
-
-		if len(blockers) > 0 {
-			blocked = append(blocked, blockedTicket{ticket: t, blockers: blockers})
-		}
+		blocked = append(blocked, blockedTicket{ticket: t, blockers: graph.Blockers(t.ID)})
 	}
 
-	// Sort by priority, then by ID
+	// Sort by priority (or blocker count), then by ID
 	sort.Slice(blocked, func(i, j int) bool {
-		if blocked[i].ticket.Priority != blocked[j].ticket.Priority {
-			return blocked[i].ticket.Priority < blocked[j].ticket.Priority
+		if blockedReverse {
+			i, j = j, i
+		}
+		if blockedSort == "blockers" && len(blocked[i].blockers) != len(blocked[j].blockers) {
+			return len(blocked[i].blockers) < len(blocked[j].blockers)
 		}
-		return blocked[i].ticket.ID < blocked[j].ticket.ID
+		return priorityLess(blocked[i].ticket, blocked[j].ticket)
 	})
 
 	// Print
 	for _, b := range blocked {
-		blockersStr := "[" + strings.Join(b.blockers, ", ") + "]"
-		fmt.Printf("%-8s [P%d][%s] - %s <- %s\n", b.ticket.ID, b.ticket.Priority, b.ticket.Status, b.ticket.Title, blockersStr)
+		blockersStr := "[" + strings.Join(displayIDs(b.blockers), ", ") + "]"
+		fmt.Printf("%-8s [P%d][%s] - %s <- %s\n", displayID(b.ticket.ID), b.ticket.Priority, b.ticket.Status, b.ticket.Title, blockersStr)
 	}
 
 	return nil
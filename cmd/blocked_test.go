@@ -551,3 +551,99 @@ func TestBlockedDefaultPriority(t *testing.T) {
 		}
 	})
 }
+
+// TestBlockedSortByBlockers tests ordering by open blocker count
+func TestBlockedSortByBlockers(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	var blockers []string
+	for _, title := range []string{"B1", "B2", "B3"} {
+		id, _ := ctx.exec("new", title)
+		blockers = append(blockers, strings.TrimSpace(id))
+	}
+
+	x, _ := ctx.exec("new", "X", "--priority", "0")
+	x = strings.TrimSpace(x)
+	y, _ := ctx.exec("new", "Y", "--priority", "1")
+	y = strings.TrimSpace(y)
+	z, _ := ctx.exec("new", "Z", "--priority", "2")
+	z = strings.TrimSpace(z)
+
+	for _, b := range blockers {
+		ctx.exec("dep", x, b)
+	}
+	ctx.exec("dep", y, blockers[0])
+	ctx.exec("dep", z, blockers[0])
+	ctx.exec("dep", z, blockers[1])
+
+	order := func(args ...string) []string {
+		output, err := ctx.exec(append([]string{"blocked"}, args...)...)
+		if err != nil {
+			t.Fatalf("blocked %v error: %v", args, err)
+		}
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			ids = append(ids, strings.Fields(line)[0])
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"priority by default", nil, []string{x, y, z}},
+		{"fewest blockers first", []string{"--sort", "blockers"}, []string{y, z, x}},
+		{"most blockers first", []string{"--sort", "blockers", "--reverse"}, []string{x, z, y}},
+	}
+
+	for _, tt := range tests {
+		blockedSort = "priority"
+		blockedReverse = false
+		got := order(tt.args...)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	blockedSort = "priority"
+	if _, err := ctx.exec("blocked", "--sort", "bogus"); err == nil {
+		t.Error("expected error for unknown sort")
+	}
+}
+
+func TestBlockedAssignee(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	origCurrentUser := currentUser
+	currentUser = func() string { return "alice" }
+	defer func() { currentUser = origCurrentUser }()
+
+	dep, _ := ctx.exec("new", "Shared dependency")
+	dep = strings.TrimSpace(dep)
+	// Assignee persists across exec calls, so set it on every ticket
+	mine, _ := ctx.exec("new", "Alice is stuck", "--assignee", "alice")
+	mine = strings.TrimSpace(mine)
+	theirs, _ := ctx.exec("new", "Bob is stuck", "--assignee", "bob")
+	theirs = strings.TrimSpace(theirs)
+	ctx.exec("dep", mine, dep)
+	ctx.exec("dep", theirs, dep)
+
+	for _, who := range []string{"me", "alice"} {
+		output, err := ctx.exec("blocked", "--assignee", who)
+		if err != nil {
+			t.Fatalf("blocked --assignee %s error: %v", who, err)
+		}
+		if !strings.Contains(output, mine) || strings.Contains(output, theirs) {
+			t.Errorf("blocked --assignee %s output:\n%s", who, output)
+		}
+	}
+
+	output, _ := ctx.exec("blocked", "--assignee", "bob")
+	if !strings.Contains(output, theirs) || strings.Contains(output, mine) {
+		t.Errorf("blocked --assignee bob output:\n%s", output)
+	}
+}
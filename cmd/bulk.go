@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk <file>",
+	Short: "Run tk commands from a script file",
+	Long: `Run tk subcommands listed in a file, one invocation per line.
+
+Each line is a tk command without the leading "tk", e.g.:
+
+  new "Set up CI" -p 1
+  dep nw-5c46 nw-8a21
+  link nw-5c46 nw-9f03
+
+Blank lines and lines starting with # are ignored. Arguments may be quoted
+with single or double quotes. Global flags given to bulk, such as
+--json-errors, apply to every line. Execution stops at the first failing
+line unless --continue is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBulk,
+}
+
+var (
+	bulkContinue bool
+
+	// inBulk is set while bulk runs script lines, whose errors are reported
+	// without usage text
+	inBulk bool
+)
+
+func init() {
+	rootCmd.AddCommand(bulkCmd)
+	bulkCmd.Flags().BoolVar(&bulkContinue, "continue", false, "Keep going after a failing line")
+}
+
+func runBulk(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening script: %w", err)
+	}
+	defer f.Close()
+
+	// Capture settings before flags are reset for each line, passing the
+	// global flags on to every line
+	keepGoing := bulkContinue
+	root := cmd.Root()
+	global := []string{"--dir", ticketsDir}
+	root.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed && f.Name != "dir" {
+			global = append(global, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+
+	inBulk = true
+	defer func() { inBulk = false }()
+
+	failed := 0
+	lineNum := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lineArgs, err := splitArgs(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if lineArgs[0] == "tk" {
+			lineArgs = lineArgs[1:]
+		}
+		if len(lineArgs) == 0 {
+			continue
+		}
+		lineArgs = append(append([]string{}, global...), lineArgs...)
+		if sub, _, err := root.Find(lineArgs); err == nil && sub == cmd {
+			return fmt.Errorf("line %d: bulk scripts cannot invoke bulk", lineNum)
+		}
+
+		resetFlags(root)
+		root.SilenceUsage = true
+		root.SetArgs(lineArgs)
+		if err := root.Execute(); err != nil {
+			if !keepGoing {
+				return fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			failed++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading script: %w", err)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d line(s) failed", failed)
+	}
+	return nil
+}
+
+// resetFlags restores every flag of c and its subcommands to its default,
+// so values from one dispatched line do not leak into the next
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			var def []string
+			if trimmed := strings.Trim(f.DefValue, "[]"); trimmed != "" {
+				def = strings.Split(trimmed, ",")
+			}
+			sv.Replace(def)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// splitArgs splits a command line into arguments, honoring single and
+// double quotes and backslash escapes outside single quotes
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeScript writes a bulk script to a temp file and returns its path
+func writeScript(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.tk")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestBulkCommand tests running commands from a script file
+func TestBulkCommand(t *testing.T) {
+	t.Run("creates and links tickets", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "Ticket A")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "Ticket B")
+		b = strings.TrimSpace(b)
+
+		script := writeScript(t,
+			"# seed the backlog",
+			`new "Ticket C" -p 1 --type bug`,
+			"",
+			"dep "+a+" "+b,
+			"tk link "+a+" "+b,
+		)
+
+		if _, err := ctx.exec("bulk", script); err != nil {
+			t.Fatalf("bulk error: %v", err)
+		}
+
+		tickets, _ := ctx.store().List()
+		if len(tickets) != 3 {
+			t.Fatalf("expected 3 tickets, got %d", len(tickets))
+		}
+		for _, tk := range tickets {
+			switch tk.ID {
+			case a:
+				if !reflect.DeepEqual(tk.Deps, []string{b}) || !reflect.DeepEqual(tk.Links, []string{b}) {
+					t.Errorf("A deps=%v links=%v, want deps and links [%s]", tk.Deps, tk.Links, b)
+				}
+			case b:
+				if !reflect.DeepEqual(tk.Links, []string{a}) {
+					t.Errorf("B links=%v, want [%s]", tk.Links, a)
+				}
+			default:
+				if tk.Title != "Ticket C" || tk.Priority != 1 || tk.Type != "bug" {
+					t.Errorf("unexpected created ticket: %+v", tk)
+				}
+			}
+		}
+	})
+
+	t.Run("flags do not leak between lines", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		script := writeScript(t,
+			`new "Urgent" -p 0`,
+			`new "Normal"`,
+		)
+		if _, err := ctx.exec("bulk", script); err != nil {
+			t.Fatalf("bulk error: %v", err)
+		}
+
+		tickets, _ := ctx.store().List()
+		for _, tk := range tickets {
+			if tk.Title == "Normal" && tk.Priority != 2 {
+				t.Errorf("second line should use default priority, got %d", tk.Priority)
+			}
+		}
+	})
+
+	t.Run("stops on first error", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		script := writeScript(t,
+			"show missing-ticket",
+			`new "Never created"`,
+		)
+		_, err := ctx.exec("bulk", script)
+		if err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Fatalf("expected line 1 error, got: %v", err)
+		}
+
+		tickets, _ := ctx.store().List()
+		if len(tickets) != 0 {
+			t.Errorf("should stop before later lines, got %d tickets", len(tickets))
+		}
+	})
+
+	t.Run("continue keeps going", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		script := writeScript(t,
+			"show missing-ticket",
+			`new "Created anyway"`,
+		)
+		_, err := ctx.exec("bulk", "--continue", script)
+		if err == nil || !strings.Contains(err.Error(), "1 line(s) failed") {
+			t.Fatalf("expected failure summary, got: %v", err)
+		}
+
+		tickets, _ := ctx.store().List()
+		if len(tickets) != 1 {
+			t.Errorf("later lines should run with --continue, got %d tickets", len(tickets))
+		}
+	})
+}
+
+// TestBulkScriptLines tests lines that dispatch nothing or should be refused
+func TestBulkScriptLines(t *testing.T) {
+	t.Run("a bare tk line is skipped", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		output, err := ctx.exec("bulk", writeScript(t, "tk", `new "After"`))
+		if err != nil {
+			t.Fatalf("bulk error: %v\n%s", err, output)
+		}
+		if strings.Contains(output, "Usage:") || strings.Contains(output, "Available Commands") {
+			t.Errorf("a bare tk line should not run the root command:\n%s", output)
+		}
+		if tickets, _ := ctx.store().List(); len(tickets) != 1 {
+			t.Errorf("expected 1 ticket, got %d", len(tickets))
+		}
+	})
+
+	t.Run("bulk behind flags is refused", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		script := writeScript(t, "--dir "+ctx.ticketsDir+" bulk other.tk")
+		_, err := ctx.exec("bulk", script)
+		if err == nil || !strings.Contains(err.Error(), "cannot invoke bulk") {
+			t.Errorf("expected the recursion guard, got: %v", err)
+		}
+	})
+
+	t.Run("global flags apply to every line", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Abbreviated")
+		id = strings.TrimSpace(id)
+
+		output, err := ctx.exec("--short-ids", "bulk", writeScript(t, "ls", "ls"))
+		if err != nil {
+			t.Fatalf("bulk error: %v", err)
+		}
+		if strings.Contains(output, id) {
+			t.Errorf("--short-ids should abbreviate IDs on every line:\n%s", output)
+		}
+	})
+
+	t.Run("failing lines print no usage", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		output, err := ctx.exec("bulk", "--continue", writeScript(t, "show", "show a b"))
+		if err == nil {
+			t.Fatal("expected failing lines")
+		}
+		if strings.Contains(output, "Usage:") {
+			t.Errorf("failing lines should not print usage:\n%s", output)
+		}
+	})
+}
+
+// TestSplitArgs tests command line splitting for bulk scripts
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`new title`, []string{"new", "title"}},
+		{`new "two words" -p 1`, []string{"new", "two words", "-p", "1"}},
+		{`note a 'it''s'`, []string{"note", "a", "its"}},
+		{`note a "say \"hi\""`, []string{"note", "a", `say "hi"`}},
+		{`new  spaced   out `, []string{"new", "spaced", "out"}},
+		{`new ""`, []string{"new", ""}},
+	}
+
+	for _, tt := range tests {
+		got, err := splitArgs(tt.line)
+		if err != nil {
+			t.Errorf("splitArgs(%q) error: %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := splitArgs(`new "unterminated`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lo5/tk/internal/deptree"
+	"github.com/spf13/cobra"
+)
+
+var candidatesCmd = &cobra.Command{
+	Use:   "candidates <id>",
+	Short: "List tickets that could be added as dependencies",
+	Long: `List, one ID per line, the tickets that could be added as dependencies of a
+ticket without creating a cycle: every ticket except the ticket itself, its
+direct and transitive dependants, and its existing dependencies.
+
+Intended for scripts and shell completion of 'tk dep <id> <dep>'.`,
+	Args:   cobra.ExactArgs(1),
+	Hidden: true,
+	RunE:   runCandidates,
+}
+
+func init() {
+	rootCmd.AddCommand(candidatesCmd)
+}
+
+func runCandidates(cmd *cobra.Command, args []string) error {
+	target, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	tickets, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range deptree.NewGraph(tickets).DepCandidates(target.ID) {
+		fmt.Println(t.ID)
+	}
+	return nil
+}
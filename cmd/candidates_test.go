@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCandidates(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	base, _ := ctx.exec("new", "Base")
+	base = strings.TrimSpace(base)
+	mid, _ := ctx.exec("new", "Mid")
+	mid = strings.TrimSpace(mid)
+	top, _ := ctx.exec("new", "Top")
+	top = strings.TrimSpace(top)
+	other, _ := ctx.exec("new", "Other")
+	other = strings.TrimSpace(other)
+
+	ctx.exec("dep", mid, base)
+	ctx.exec("dep", top, mid)
+
+	output, err := ctx.exec("candidates", base)
+	if err != nil {
+		t.Fatalf("candidates error: %v", err)
+	}
+	if got := strings.TrimSpace(output); got != other {
+		t.Errorf("candidates %s = %q, want only %s", base, got, other)
+	}
+
+	output, _ = ctx.exec("candidates", other)
+	got := strings.Fields(output)
+	if len(got) != 3 {
+		t.Errorf("candidates %s = %v, want the other three tickets", other, got)
+	}
+	for _, id := range got {
+		if id == other {
+			t.Errorf("candidates %s includes the ticket itself", other)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/lo5/tk/internal/config"
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -20,7 +23,10 @@ Refuses deletion if a closed ticket:
   - Has non-closed children (other tickets have it as parent and are open/in_progress)
   - Has bidirectional links
 
-This ensures that only truly obsolete closed tickets are removed.`,
+This ensures that only truly obsolete closed tickets are removed.
+
+With the trash enabled (tk config set trash true) the tickets are moved to
+.tickets/.trash/ instead, and 'tk undo' restores them.`,
 	Args: cobra.NoArgs,
 	RunE: runClean,
 }
@@ -41,53 +47,23 @@ type cleanableTicket struct {
 
 func runClean(cmd *cobra.Command, args []string) error {
 	// 1. Load all tickets
-	allTickets, err := store.List()
+	graph, err := loadGraph()
 	if err != nil {
 		return err
 	}
 
 	// 2. Filter for closed tickets and check if they're safe to delete
 	var cleanable []cleanableTicket
-	for _, t := range allTickets {
+	for _, t := range graph.Tickets() {
 		if t.Status != ticket.StatusClosed {
 			continue
 		}
 
 		ct := cleanableTicket{ticket: t}
-
-		// Check for dependants
-		dependants := findDependants(allTickets, t.ID)
-		if len(dependants) > 0 {
-			ct.blocked = true
-			ct.reason = "has dependants"
-			cleanable = append(cleanable, ct)
-			continue
-		}
-
-		// Check for children (only non-closed children block deletion)
-		children := findChildren(allTickets, t.ID)
-		var nonClosedChildren []*ticket.Ticket
-		for _, child := range children {
-			if child.Status != ticket.StatusClosed {
-				nonClosedChildren = append(nonClosedChildren, child)
-			}
-		}
-		if len(nonClosedChildren) > 0 {
+		if reason := cleanBlockReason(t, graph); reason != "" {
 			ct.blocked = true
-			ct.reason = "has non-closed children"
-			cleanable = append(cleanable, ct)
-			continue
+			ct.reason = reason
 		}
-
-		// Check for links
-		if len(t.Links) > 0 {
-			ct.blocked = true
-			ct.reason = "has links"
-			cleanable = append(cleanable, ct)
-			continue
-		}
-
-		// If we get here, ticket is safe to delete
 		cleanable = append(cleanable, ct)
 	}
 
@@ -141,14 +117,20 @@ func runClean(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	cfg, err := config.Load(store.Dir())
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("Deleting closed tickets...")
 	fmt.Println()
 
 	successCount := 0
 	errorCount := 0
 
+	now := time.Now()
 	for _, ct := range deletable {
-		if err := store.Delete(ct.ticket.ID); err != nil {
+		if err := removeTicket(cfg, ct.ticket.ID, now); err != nil {
 			fmt.Printf("Warning: failed to delete %s: %v\n", ct.ticket.ID, err)
 			errorCount++
 			continue
@@ -165,6 +147,32 @@ func runClean(cmd *cobra.Command, args []string) error {
 		fmt.Printf(", %d error(s)", errorCount)
 	}
 	fmt.Println(".")
+	if cfg.Trash && successCount > 0 {
+		fmt.Println("Moved to trash; run 'tk undo' to restore.")
+	}
 
 	return nil
 }
+
+// cleanBlockReason explains why a closed ticket cannot be removed,
+// or returns an empty string if it is safe to remove
+func cleanBlockReason(t *ticket.Ticket, graph *deptree.DependencyGraph) string {
+	// Check for dependants
+	if len(graph.Dependants(t.ID)) > 0 {
+		return "has dependants"
+	}
+
+	// Check for children (only non-closed children block deletion)
+	for _, child := range graph.Children(t.ID) {
+		if child.Status != ticket.StatusClosed {
+			return "has non-closed children"
+		}
+	}
+
+	// Check for links
+	if len(t.Links) > 0 {
+		return "has links"
+	}
+
+	return ""
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardWriter copies text to a clipboard
+type clipboardWriter interface {
+	Copy(text string) error
+}
+
+// errNoClipboard is returned when no clipboard tool is installed
+var errNoClipboard = errors.New("no clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+
+// systemClipboard copies text with the first clipboard tool found on PATH
+type systemClipboard struct{}
+
+func (systemClipboard) Copy(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"})
+	}
+
+	for _, argv := range candidates {
+		path, err := exec.LookPath(argv[0])
+		if err != nil {
+			continue
+		}
+		c := exec.Command(path, argv[1:]...)
+		c.Stdin = strings.NewReader(text)
+		return c.Run()
+	}
+	return errNoClipboard
+}
+
+// clipboard is replaced in tests to avoid touching the system clipboard
+var clipboard clipboardWriter = systemClipboard{}
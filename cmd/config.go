@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lo5/tk/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get or set configuration values",
+	Long: fmt.Sprintf(`Read and write settings in the tickets directory's %s file.
+
+Known keys:
+%s`, config.FileName, configKeyHelp()),
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all configuration values",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+}
+
+func configKeyHelp() string {
+	var lines []string
+	for _, k := range config.Keys {
+		lines = append(lines, fmt.Sprintf("  %-18s %s", k.Name, k.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	value, ok, err := config.Get(store.Dir(), args[0])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("config key '%s' is not set", args[0])
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+
+	if _, known := config.LookupKey(key); !known {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: unknown config key '%s'\n", key)
+	}
+
+	if err := config.Set(store.Dir(), key, value); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set %s = %s\n", key, value)
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	values, err := config.Values(store.Dir())
+	if err != nil {
+		return err
+	}
+
+	for _, name := range config.SortedNames(values) {
+		fmt.Printf("%s=%s\n", name, config.FormatValue(values[name]))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/config"
+)
+
+func TestConfigCommand(t *testing.T) {
+	t.Run("set then get round-trips", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		if _, err := ctx.exec("config", "set", "default_priority", "1"); err != nil {
+			t.Fatalf("config set error: %v", err)
+		}
+
+		output, err := ctx.exec("config", "get", "default_priority")
+		if err != nil {
+			t.Fatalf("config get error: %v", err)
+		}
+		if strings.TrimSpace(output) != "1" {
+			t.Errorf("config get = %q, want 1", output)
+		}
+
+		output, _ = ctx.exec("config", "list")
+		if !strings.Contains(output, "default_priority=1") {
+			t.Errorf("config list should include default_priority, got: %s", output)
+		}
+	})
+
+	t.Run("rejects invalid value", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		output, err := ctx.exec("config", "set", "default_priority", "7")
+		if err == nil {
+			t.Fatal("expected error for out-of-range priority")
+		}
+		if !strings.Contains(output, "must be 0-4") {
+			t.Errorf("expected validation message, got: %s", output)
+		}
+
+		if _, err := ctx.exec("config", "get", "default_priority"); err == nil {
+			t.Error("invalid value should not have been stored")
+		}
+	})
+
+	t.Run("unknown key warns", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		output, err := ctx.exec("config", "set", "colour", "blue")
+		if err != nil {
+			t.Fatalf("config set error: %v", err)
+		}
+		if !strings.Contains(output, "Warning: unknown config key 'colour'") {
+			t.Errorf("expected unknown key warning, got: %s", output)
+		}
+	})
+
+	t.Run("unknown key in the file warns on every command", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		os.MkdirAll(ctx.ticketsDir, 0755)
+		os.WriteFile(config.Path(ctx.ticketsDir), []byte("shardded: true\n"), 0644)
+
+		output, err := ctx.exec("ls")
+		if err != nil {
+			t.Fatalf("ls error: %v", err)
+		}
+		if !strings.Contains(output, "Warning: unknown config key 'shardded'") {
+			t.Errorf("expected unknown key warning, got: %s", output)
+		}
+	})
+
+	t.Run("default_priority applies to new tickets", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("config", "set", "default_priority", "0")
+
+		id, err := ctx.exec("new", "Urgent by default")
+		if err != nil {
+			t.Fatalf("new error: %v", err)
+		}
+		tk, _ := ctx.store().Get(strings.TrimSpace(id))
+		if tk.Priority != 0 {
+			t.Errorf("Priority = %d, want 0 from config", tk.Priority)
+		}
+
+		id, _ = ctx.exec("new", "Explicit", "--priority", "3")
+		tk, _ = ctx.store().Get(strings.TrimSpace(id))
+		if tk.Priority != 3 {
+			t.Errorf("Priority = %d, want explicit 3", tk.Priority)
+		}
+	})
+
+	t.Run("priority_keywords infer priority from the title", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		cfg := "default_priority: 3\npriority_keywords:\n  - keyword: critical\n    priority: 0\n  - keyword: bug\n    priority: 1\n"
+		if err := os.MkdirAll(ctx.ticketsDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(config.Path(ctx.ticketsDir), []byte(cfg), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		for title, want := range map[string]int{
+			"Fix CRITICAL login bug": 0, // First match wins, ignoring case
+			"Fix login bug":          1,
+			"Tidy docs":              3, // Falls back to default_priority
+		} {
+			id, err := ctx.exec("new", title)
+			if err != nil {
+				t.Fatalf("new error: %v", err)
+			}
+			tk, _ := ctx.store().Get(strings.TrimSpace(id))
+			if tk.Priority != want {
+				t.Errorf("%q: Priority = %d, want %d", title, tk.Priority, want)
+			}
+		}
+
+		id, _ := ctx.exec("new", "Critical but explicit", "--priority", "4")
+		tk, _ := ctx.store().Get(strings.TrimSpace(id))
+		if tk.Priority != 4 {
+			t.Errorf("Priority = %d, want explicit 4", tk.Priority)
+		}
+	})
+}
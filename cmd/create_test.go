@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
@@ -34,11 +36,102 @@ func setupTestCmd(t *testing.T) (*testContext, func()) {
 		newAssignee = ""
 		newExternalRef = ""
 		newParent = ""
+		newCreatedBy = ""
 		listStatus = ""
+		listCreatedBy = ""
 		closedLimit = 20
 		rmForce = false
 		pruneFix = false
 		cleanFix = false
+		showFollowLinks = false
+		jsonErrors = false
+		depTreeFull = false
+		depTreeASCII = false
+		queryTitleMatch = ""
+		queryOlderThan = ""
+		queryChangedSince = ""
+		bulkContinue = false
+		queryFailIfAny = false
+		listFailIfAny = false
+		blockedSort = "priority"
+		blockedReverse = false
+		startReady = false
+		startMine = false
+		listPorcelain = false
+		archiveOlderThan = ""
+		archiveDryRun = false
+		statusBatchJSON = ""
+		depBatchJSON = ""
+		linkBatchJSON = ""
+		showHistory = false
+		newEdit = false
+		depTreeFilter = ""
+		queryWarnMissing = nil
+		searchIncludeBody = false
+		statsBurndown = false
+		statsSince = ""
+		linkRel = ""
+		queryRawOutput = false
+		listTopo = false
+		rmImpact = false
+		rmWithDependants = false
+		newBatch = false
+		newAssignees = nil
+		shortIDs = false
+		queryHistogram = ""
+		graphOutput = ""
+		validateFixFormat = false
+		queryLinkedTo = ""
+		queryDependsOn = ""
+		queryChildOf = ""
+		showCopyID = false
+		showRelsOnly = false
+		depTreeCrit = false
+		queryDistinct = ""
+		listAfter = ""
+		listLimit = 0
+		newAllowDup = false
+		queryTemplate = ""
+		blockedAssume = nil
+		readyAssume = nil
+		exportFormat = "markdown"
+		exportOutput = ""
+		queryViews = nil
+		queryAnyView = false
+		querySaveView = ""
+		queryAsserts = nil
+		queryFormat = "ndjson"
+		queryCount = false
+		queryGroupBy = ""
+		querySort = ""
+		querySlurp = false
+		importOverwrite = false
+		reidAuto = false
+		reidKeepSuffix = false
+		depRTreeFull = false
+		depRTreeASCII = false
+		watchExecInterval = 500 * time.Millisecond
+		watchExecDebounce = time.Second
+		listNoIndic = false
+		listASCII = false
+		showNoIndic = false
+		showASCII = false
+		searchCaseSensitive = false
+		searchRegex = false
+		queryFlatten = ""
+		queryKeepEmpty = false
+		depTreeTotals = false
+		statsJSON = false
+		statsFields = nil
+		newDue = ""
+		listFormat = ""
+		blockedAssign = ""
+		startStrict = false
+		closeStrict = false
+		reopenStrict = false
+
+		// Clear Changed so flags set in one test don't leak into the next
+		resetFlags(rootCmd)
 	}
 
 	ctx := &testContext{
@@ -56,6 +149,13 @@ func (ctx *testContext) exec(args ...string) (string, error) {
 	return executeCommand(rootCmd, fullArgs...)
 }
 
+// execWithStdin executes a command with the given text as its stdin
+func (ctx *testContext) execWithStdin(input string, args ...string) (string, error) {
+	rootCmd.SetIn(strings.NewReader(input))
+	defer rootCmd.SetIn(nil)
+	return ctx.exec(args...)
+}
+
 // store returns a FileStore for the test context
 func (ctx *testContext) store() *ticket.FileStore {
 	return ticket.NewFileStore(ctx.ticketsDir)
@@ -396,3 +496,214 @@ func TestNewCommand_WithParent(t *testing.T) {
 		}
 	})
 }
+
+// TestNewCommand_CreatedBy tests the created-by provenance field
+func TestNewCommand_CreatedBy(t *testing.T) {
+	t.Run("stamps current identity", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		const user = "alice"
+		orig := currentUser
+		currentUser = func() string { return user }
+		defer func() { currentUser = orig }()
+
+		id, err := ctx.exec("new", "Filed Ticket", "--assignee", "someone-else")
+		if err != nil {
+			t.Fatalf("new error: %v", err)
+		}
+
+		tk, err := ctx.store().Get(strings.TrimSpace(id))
+		if err != nil {
+			t.Fatalf("failed to retrieve ticket: %v", err)
+		}
+		if tk.CreatedBy != user {
+			t.Errorf("CreatedBy = %q, want %q", tk.CreatedBy, user)
+		}
+	})
+
+	t.Run("explicit override wins", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, err := ctx.exec("new", "Filed Ticket", "--created-by", "carol")
+		if err != nil {
+			t.Fatalf("new error: %v", err)
+		}
+		id = strings.TrimSpace(id)
+
+		tk, _ := ctx.store().Get(id)
+		if tk.CreatedBy != "carol" {
+			t.Errorf("CreatedBy = %q, want %q", tk.CreatedBy, "carol")
+		}
+
+		output, _ := ctx.exec("show", id)
+		if !strings.Contains(output, "created-by: carol") {
+			t.Errorf("show should display created-by, got: %s", output)
+		}
+
+		output, _ = ctx.exec("query")
+		if !strings.Contains(output, `"created-by":"carol"`) {
+			t.Errorf("query should expose created-by, got: %s", output)
+		}
+	})
+}
+
+// withStubEditor replaces launchEditor for the duration of a test
+func withStubEditor(t *testing.T, edit func(path string) error) {
+	t.Helper()
+	orig := launchEditor
+	launchEditor = edit
+	t.Cleanup(func() { launchEditor = orig })
+}
+
+func TestNewCommand_Edit(t *testing.T) {
+	t.Run("persists drafted body", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		var draft string
+		withStubEditor(t, func(path string) error {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			draft = string(content)
+			return os.WriteFile(path, []byte("# Edited Title\n\nDrafted in the editor.\n\n## Design\n\nDetails.\n"), 0644)
+		})
+
+		id, err := ctx.exec("new", "Draft Ticket", "-e")
+		if err != nil {
+			t.Fatalf("new -e error: %v", err)
+		}
+
+		if !strings.HasPrefix(draft, "# Draft Ticket\n") {
+			t.Errorf("expected draft pre-filled with title heading, got %q", draft)
+		}
+
+		tk, err := ctx.store().Get(strings.TrimSpace(id))
+		if err != nil {
+			t.Fatalf("failed to retrieve ticket: %v", err)
+		}
+		if tk.Title != "Edited Title" {
+			t.Errorf("Title = %q, want %q", tk.Title, "Edited Title")
+		}
+		if tk.Body != "Drafted in the editor.\n\n## Design\n\nDetails." {
+			t.Errorf("unexpected body: %q", tk.Body)
+		}
+	})
+
+	t.Run("unchanged draft still creates ticket", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		withStubEditor(t, func(string) error { return nil })
+
+		id, err := ctx.exec("new", "Quick Ticket", "--edit")
+		if err != nil {
+			t.Fatalf("new --edit error: %v", err)
+		}
+
+		tk, err := ctx.store().Get(strings.TrimSpace(id))
+		if err != nil {
+			t.Fatalf("failed to retrieve ticket: %v", err)
+		}
+		if tk.Title != "Quick Ticket" || tk.Body != "" {
+			t.Errorf("got title %q body %q, want title only", tk.Title, tk.Body)
+		}
+	})
+}
+
+func TestNewCommand_BatchAssignees(t *testing.T) {
+	t.Run("round-robin across assignees", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		var input strings.Builder
+		for i := 1; i <= 9; i++ {
+			input.WriteString(fmt.Sprintf("Seed ticket %d\n", i))
+		}
+		input.WriteString("\n# comment lines are skipped\n")
+
+		output, err := ctx.execWithStdin(input.String(), "new", "--batch", "--assignees", "alice,bob,carol", "-t", "chore")
+		if err != nil {
+			t.Fatalf("new --batch error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) != 9 {
+			t.Fatalf("expected 9 assignments, got:\n%s", output)
+		}
+
+		want := []string{"alice", "bob", "carol"}
+		counts := make(map[string]int)
+		for i, line := range lines {
+			id, assignee, ok := strings.Cut(line, " -> ")
+			if !ok {
+				t.Fatalf("unexpected line %q", line)
+			}
+			if assignee != want[i%3] {
+				t.Errorf("ticket %d assigned to %q, want %q", i, assignee, want[i%3])
+			}
+			counts[assignee]++
+
+			tk, err := ctx.store().Get(id)
+			if err != nil {
+				t.Fatalf("failed to retrieve %s: %v", id, err)
+			}
+			if tk.Assignee != assignee || tk.Title != fmt.Sprintf("Seed ticket %d", i+1) || tk.Type != ticket.TypeChore {
+				t.Errorf("ticket %s = %q/%q/%s, want %q/Seed ticket %d/chore", id, tk.Assignee, tk.Title, tk.Type, assignee, i+1)
+			}
+		}
+		for _, name := range want {
+			if counts[name] != 3 {
+				t.Errorf("%s got %d tickets, want 3", name, counts[name])
+			}
+		}
+	})
+
+	t.Run("assignees requires batch", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		if _, err := ctx.exec("new", "Single", "--assignees", "alice"); err == nil {
+			t.Error("expected error for --assignees without --batch")
+		}
+	})
+}
+
+func TestNewDuplicateTitle(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	existing, _ := ctx.exec("new", "Fix login bug")
+	existing = strings.TrimSpace(existing)
+
+	output, err := ctx.exec("new", "fix  Login bug!")
+	if err == nil {
+		t.Fatal("expected duplicate title to be refused without a terminal")
+	}
+	if !strings.Contains(output, "Warning: 1 unclosed ticket(s) with the same title") || !strings.Contains(output, existing) {
+		t.Errorf("expected warning listing %s, got: %s", existing, output)
+	}
+	if tickets, _ := ctx.store().List(); len(tickets) != 1 {
+		t.Errorf("duplicate should not be created, have %d tickets", len(tickets))
+	}
+
+	if _, err := ctx.exec("new", "Fix login bug", "--allow-duplicate"); err != nil {
+		t.Fatalf("--allow-duplicate should bypass the check: %v", err)
+	}
+	if tickets, _ := ctx.store().List(); len(tickets) != 2 {
+		t.Errorf("expected 2 tickets after --allow-duplicate, have %d", len(tickets))
+	}
+
+	t.Run("closed tickets are ignored", func(t *testing.T) {
+		newAllowDup = false
+		ctx.exec("close", existing)
+		other, _ := ctx.exec("new", "Other title")
+		ctx.exec("close", strings.TrimSpace(other))
+		if _, err := ctx.exec("new", "Other title"); err != nil {
+			t.Errorf("closed ticket should not count as a duplicate: %v", err)
+		}
+	})
+}
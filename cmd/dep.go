@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/lo5/tk/internal/deptree"
+	"github.com/lo5/tk/internal/query"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +17,14 @@ var depCmd = &cobra.Command{
 	Long: `Add a dependency to a ticket.
 The first ticket will depend on the second ticket.
 
-Also supports: dep tree [--full] <id> - show dependency tree`,
+Use --batch-json <file> (or - for stdin) to add a JSON array of dependencies,
+e.g. [{"id":"a","dep":"b"}]. Per-item results are printed as JSON.
+
+Also supports:
+  dep tree [--full] <id>   show dependency tree
+  dep rtree [--full] <id>  show the tickets that depend on a ticket
+  dep check                report dependency cycles`,
+	Args: orBatchJSON(&depBatchJSON, cobra.ArbitraryArgs),
 	RunE: runDep,
 }
 
@@ -27,49 +36,138 @@ var undepCmd = &cobra.Command{
 }
 
 var depTreeCmd = &cobra.Command{
-	Use:   "tree [--full] <id>",
+	Use:   "tree [--full] [--ascii] [--filter=EXPR] [--critical-path] [--totals] <id>",
 	Short: "Show dependency tree",
 	Long: `Show the dependency tree for a ticket.
-Use --full to show all occurrences (disable deduplication).`,
+Use --full to show all occurrences (disable deduplication).
+Use --ascii to draw branches with ASCII characters for non-UTF terminals.
+Use --filter with a jq expression to only show branches containing at least
+one matching ticket, e.g. --filter '.type == "bug"'.
+Use --critical-path to mark the longest dependency chain from the ticket to a
+leaf with a "* " prefix.
+Use --totals to append the summed estimate (the estimate frontmatter field)
+of each ticket's subtree, e.g. "(Σ13)". A ticket shared by several branches
+counts once.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDepTree,
 }
 
-var depTreeFull bool
+var depRTreeCmd = &cobra.Command{
+	Use:   "rtree [--full] [--ascii] <id>",
+	Short: "Show reverse dependency tree",
+	Long: `Show the tree of tickets that depend on a ticket, directly or
+transitively: the reverse of dep tree. Closing the ticket unblocks its
+children in this tree.
+Use --full to show all occurrences (disable deduplication).
+Use --ascii to draw branches with ASCII characters for non-UTF terminals.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDepRTree,
+}
+
+var depCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report dependency cycles",
+	Long: `Scan all tickets for dependency cycles and print each one as
+"a -> b -> c -> a". Tickets listing themselves as a dependency are reported
+too. The exit status is non-zero when any cycle exists, for use as a CI gate.`,
+	Args: cobra.NoArgs,
+	RunE: runDepCheck,
+}
+
+var depDotCmd = &cobra.Command{
+	Use:   "dot [id]",
+	Short: "Print the dependency graph as Graphviz DOT",
+	Long: `Print the dependency graph as a Graphviz DOT digraph: one node per
+ticket labeled with its ID, status and title and filled by status (open
+white, in progress blue, closed gray), and one edge from each ticket to
+each of its dependencies. Give an ID to print only that ticket and
+everything it depends on, directly or indirectly.
+  tk dep dot | dot -Tpng > deps.png
+  tk dep dot abc | dot -Tsvg > abc.svg
+
+tk graph writes the same graph and can render it to a file.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDepDot,
+}
+
+var (
+	depBatchJSON  string
+	depTreeFull   bool
+	depTreeASCII  bool
+	depTreeFilter string
+	depTreeCrit   bool
+	depTreeTotals bool
+	depRTreeFull  bool
+	depRTreeASCII bool
+)
+
+// depOp is a single --batch-json dependency addition
+type depOp struct {
+	ID  string `json:"id"`
+	Dep string `json:"dep"`
+}
 
 func init() {
 	rootCmd.AddCommand(depCmd)
 	rootCmd.AddCommand(undepCmd)
 
+	depCmd.Flags().StringVar(&depBatchJSON, "batch-json", "", "Add a JSON array of dependencies from a file (- for stdin)")
+
 	depCmd.AddCommand(depTreeCmd)
 	depTreeCmd.Flags().BoolVar(&depTreeFull, "full", false, "Show all occurrences (disable deduplication)")
+	depTreeCmd.Flags().BoolVar(&depTreeASCII, "ascii", false, "Use ASCII connectors instead of box-drawing characters")
+	depTreeCmd.Flags().BoolVar(&depTreeCrit, "critical-path", false, "Mark the longest dependency chain with a * prefix")
+	depTreeCmd.Flags().BoolVar(&depTreeTotals, "totals", false, "Append the summed estimate of each subtree")
+	depTreeCmd.Flags().StringVar(&depTreeFilter, "filter", "", "Only show branches containing a ticket matching this jq expression")
+
+	depCmd.AddCommand(depRTreeCmd)
+	depCmd.AddCommand(depCheckCmd)
+	depCmd.AddCommand(depDotCmd)
+	depRTreeCmd.Flags().BoolVar(&depRTreeFull, "full", false, "Show all occurrences (disable deduplication)")
+	depRTreeCmd.Flags().BoolVar(&depRTreeASCII, "ascii", false, "Use ASCII connectors instead of box-drawing characters")
 }
 
 func runDep(cmd *cobra.Command, args []string) error {
+	if depBatchJSON != "" {
+		return runBatchJSON(cmd, depBatchJSON, applyDepOp)
+	}
+
 	if len(args) < 2 {
 		return fmt.Errorf("usage: tk dep <id> <dependency-id>")
 	}
 
-	ticketID := args[0]
-	depID := args[1]
+	id, depID, added, err := addDependency(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if !added {
+		fmt.Println("Dependency already exists")
+		return nil
+	}
 
+	fmt.Printf("Added dependency: %s -> %s\n", id, depID)
+	return nil
+}
+
+// addDependency makes ticketID depend on depID, reporting the resolved IDs
+// and whether the dependency was newly added
+func addDependency(ticketID, depID string) (string, string, bool, error) {
 	// Get the ticket
 	t, err := store.Get(ticketID)
 	if err != nil {
-		return err
+		return "", "", false, err
 	}
 
 	// Verify dependency exists
 	dep, err := store.Get(depID)
 	if err != nil {
-		return err
+		return "", "", false, err
 	}
 
 	// Check if dep already exists
 	for _, d := range t.Deps {
 		if d == dep.ID {
-			fmt.Println("Dependency already exists")
-			return nil
+			return t.ID, dep.ID, false, nil
 		}
 	}
 
@@ -78,13 +176,26 @@ func runDep(cmd *cobra.Command, args []string) error {
 
 	// Update the field directly to preserve formatting
 	newDeps := formatDepsArray(t.Deps)
-	id, err := store.UpdateField(ticketID, "deps", newDeps)
+	id, err := store.UpdateField(t.ID, "deps", newDeps)
 	if err != nil {
-		return err
+		return "", "", false, err
 	}
 
-	fmt.Printf("Added dependency: %s -> %s\n", id, dep.ID)
-	return nil
+	return id, dep.ID, true, nil
+}
+
+// applyDepOp validates and applies a --batch-json dependency addition
+func applyDepOp(item json.RawMessage) (string, error) {
+	var op depOp
+	if err := decodeBatchItem(item, &op); err != nil {
+		return "", err
+	}
+	if op.ID == "" || op.Dep == "" {
+		return "", fmt.Errorf("missing 'id' or 'dep'")
+	}
+
+	id, _, _, err := addDependency(op.ID, op.Dep)
+	return id, err
 }
 
 func runUndep(cmd *cobra.Command, args []string) error {
@@ -98,17 +209,17 @@ func runUndep(cmd *cobra.Command, args []string) error {
 	}
 
 	// Find and remove the dependency
-	found := false
+	var removed []string
 	var newDeps []string
 	for _, d := range t.Deps {
 		if d == depID || strings.Contains(d, depID) {
-			found = true
+			removed = append(removed, d)
 			continue
 		}
 		newDeps = append(newDeps, d)
 	}
 
-	if !found {
+	if len(removed) == 0 {
 		return fmt.Errorf("dependency not found")
 	}
 
@@ -123,39 +234,154 @@ func runUndep(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Removed dependency: %s -/-> %s\n", id, depID)
+
+	// Dependants live in other files, so count them from the written state
+	tickets, err := store.List()
+	if err != nil {
+		return err
+	}
+	graph := deptree.NewGraph(tickets)
+	counts := []string{fmt.Sprintf("%s now has %s", id, countNoun(len(newDeps), "dependency"))}
+	for _, r := range removed {
+		counts = append(counts, fmt.Sprintf("%s now has %s", r, countNoun(len(graph.Dependants(r)), "dependant")))
+	}
+	fmt.Println(strings.Join(counts, ", "))
 	return nil
 }
 
 func runDepTree(cmd *cobra.Command, args []string) error {
-	rootID := args[0]
+	graph, resolvedID, err := treeGraph(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Build and render tree
+	tree := deptree.BuildFrom(graph, resolvedID, deptree.TreeOptions{Full: depTreeFull})
+	if depTreeASCII {
+		tree.SetConnectors(deptree.ASCIIConnectors)
+	}
+	if depTreeFilter != "" {
+		matches, err := matchTickets(graph.Tickets(), depTreeFilter)
+		if err != nil {
+			return err
+		}
+		tree.SetFilter(func(id string) bool { return matches[id] })
+	}
+	if depTreeCrit {
+		tree.MarkCriticalPath()
+	}
+	if depTreeTotals {
+		tree.ShowTotals()
+	}
+	tree.Render()
+
+	return nil
+}
+
+func runDepRTree(cmd *cobra.Command, args []string) error {
+	graph, resolvedID, err := treeGraph(args[0])
+	if err != nil {
+		return err
+	}
+
+	tree := deptree.BuildFrom(graph, resolvedID, deptree.TreeOptions{Full: depRTreeFull, Reverse: true})
+	if depRTreeASCII {
+		tree.SetConnectors(deptree.ASCIIConnectors)
+	}
+	tree.Render()
+
+	return nil
+}
 
-	// Get all tickets
+func runDepCheck(cmd *cobra.Command, args []string) error {
 	tickets, err := store.List()
 	if err != nil {
 		return err
 	}
 
-	if len(tickets) == 0 {
-		return fmt.Errorf("no tickets found")
+	cycles := deptree.FindCycles(tickets)
+	if len(cycles) == 0 {
+		fmt.Println("No dependency cycles")
+		return nil
 	}
+	return reportCycles(cmd, cycles)
+}
 
-	// Build ticket map
-	ticketMap := make(map[string]*ticket.Ticket)
-	for _, t := range tickets {
-		ticketMap[t.ID] = t
+func runDepDot(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		graph, err := loadGraph()
+		if err != nil {
+			return err
+		}
+		fmt.Print(deptree.DOT(graph.Tickets()))
+		return nil
 	}
 
-	// Resolve root ID
-	resolvedID, err := ticket.ResolveID(store.Dir(), rootID)
+	graph, rootID, err := treeGraph(args[0])
 	if err != nil {
 		return err
 	}
+	root, _ := graph.Ticket(rootID)
+	fmt.Print(deptree.DOT(append([]*ticket.Ticket{root}, graph.TransitiveDeps(rootID)...)))
+	return nil
+}
 
-	// Build and render tree
-	tree := deptree.Build(ticketMap, resolvedID, depTreeFull)
-	tree.Render()
+// reportCycles prints each cycle, with a count on stderr, and fails the
+// command
+func reportCycles(cmd *cobra.Command, cycles []deptree.Cycle) error {
+	for _, c := range cycles {
+		if c.SelfDependency() {
+			fmt.Printf("%s (self-dependency)\n", c)
+		} else {
+			fmt.Println(c)
+		}
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%d dependency cycle(s) found\n", len(cycles))
+	return failSilently(cmd, exitError)
+}
 
-	return nil
+// treeGraph loads the dependency graph of all tickets and resolves the root
+// of a tree
+func treeGraph(rootID string) (*deptree.DependencyGraph, string, error) {
+	tickets, err := store.ListParsed()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(tickets) == 0 {
+		return nil, "", fmt.Errorf("no tickets found")
+	}
+
+	// Resolve root ID against the loaded tickets rather than the directory
+	resolvedID, err := ticket.ResolveIn(tickets, rootID)
+	if err != nil {
+		return nil, "", err
+	}
+	return deptree.NewGraphFromMap(tickets), resolvedID, nil
+}
+
+// matchTickets returns the IDs of tickets matching a jq filter expression
+func matchTickets(tickets []*ticket.Ticket, filterExpr string) (map[string]bool, error) {
+	filter, err := query.Compile(filterExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make(map[string]bool)
+	for _, t := range tickets {
+		line, err := query.ToJSON(t)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := filter.Eval(line)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches[t.ID] = true
+		}
+	}
+	return matches, nil
 }
 
 func formatDepsArray(deps []string) string {
@@ -480,6 +480,64 @@ func TestDepTreeCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("ascii connectors", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "Ticket A")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "Ticket B")
+		b = strings.TrimSpace(b)
+		c, _ := ctx.exec("new", "Ticket C")
+		c = strings.TrimSpace(c)
+		ctx.exec("dep", c, a)
+		ctx.exec("dep", c, b)
+
+		output, err := ctx.exec("dep", "tree", "--ascii", c)
+		if err != nil {
+			t.Fatalf("dep tree --ascii error: %v", err)
+		}
+
+		if strings.ContainsAny(output, "├└│") {
+			t.Errorf("--ascii output should not contain box-drawing characters, got:\n%s", output)
+		}
+		if !strings.Contains(output, "|-- ") || !strings.Contains(output, "`-- ") {
+			t.Errorf("--ascii output should contain ASCII connectors, got:\n%s", output)
+		}
+	})
+
+	t.Run("filter keeps branches with a match", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		root, _ := ctx.exec("new", "Root Ticket")
+		root = strings.TrimSpace(root)
+		mid, _ := ctx.exec("new", "Middle Task")
+		mid = strings.TrimSpace(mid)
+		other, _ := ctx.exec("new", "Unrelated Task")
+		other = strings.TrimSpace(other)
+		// Created last: flag values persist across exec calls within a test
+		bug, _ := ctx.exec("new", "Nested Bug", "--type", "bug")
+		bug = strings.TrimSpace(bug)
+		ctx.exec("dep", root, mid)
+		ctx.exec("dep", mid, bug)
+		ctx.exec("dep", root, other)
+
+		output, err := ctx.exec("dep", "tree", "--filter", `.type == "bug"`, root)
+		if err != nil {
+			t.Fatalf("dep tree --filter error: %v", err)
+		}
+
+		for _, want := range []string{"Root Ticket", "Middle Task", "Nested Bug"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected %q in filtered tree, got:\n%s", want, output)
+			}
+		}
+		if strings.Contains(output, "Unrelated Task") {
+			t.Errorf("expected branch without a bug to be pruned, got:\n%s", output)
+		}
+	})
+
 	t.Run("partial ID resolution", func(t *testing.T) {
 		ctx, cleanup := setupTestCmd(t)
 		defer cleanup()
@@ -581,6 +639,111 @@ func TestDepTreeCommandFull(t *testing.T) {
 	})
 }
 
+// TestDepRTreeCommand tests the reverse dependency tree
+func TestDepRTreeCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	a, _ := ctx.exec("new", "Ticket A")
+	a = strings.TrimSpace(a)
+	b, _ := ctx.exec("new", "Ticket B")
+	b = strings.TrimSpace(b)
+	c, _ := ctx.exec("new", "Ticket C")
+	c = strings.TrimSpace(c)
+
+	// C depends on B, which depends on A
+	ctx.exec("dep", b, a)
+	ctx.exec("dep", c, b)
+
+	output, err := ctx.exec("dep", "rtree", a)
+	if err != nil {
+		t.Fatalf("dep rtree error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d:\n%s", len(lines), output)
+	}
+	if !strings.HasPrefix(lines[0], a) || !strings.Contains(lines[1], b) || !strings.Contains(lines[2], c) {
+		t.Errorf("expected %s -> %s -> %s, got:\n%s", a, b, c, output)
+	}
+
+	output, err = ctx.exec("dep", "rtree", "--ascii", c)
+	if err != nil {
+		t.Fatalf("dep rtree error: %v", err)
+	}
+	if strings.TrimSpace(output) != c+" [open] Ticket C" {
+		t.Errorf("ticket nothing depends on should print alone, got:\n%s", output)
+	}
+
+	if _, err := ctx.exec("dep", "rtree", "nonexistent"); err == nil {
+		t.Error("expected error for unknown ticket")
+	}
+}
+
+// TestDepCheckCommand tests cycle reporting
+func TestDepCheckCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, tk := range []*ticket.Ticket{
+		{ID: "a-1", Deps: []string{"b-1"}},
+		{ID: "b-1", Deps: []string{"c-1"}},
+		{ID: "c-1"},
+	} {
+		tk.Status, tk.Type, tk.Title = ticket.StatusOpen, ticket.TypeTask, "Ticket "+tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	output, err := ctx.exec("dep", "check")
+	if err != nil {
+		t.Fatalf("dep check error on acyclic deps: %v", err)
+	}
+	if !strings.Contains(output, "No dependency cycles") {
+		t.Errorf("expected no cycles, got: %s", output)
+	}
+
+	// Close the loop and add a self-dependency
+	if _, err := ctx.store().UpdateField("c-1", "deps", "[a-1, c-1]"); err != nil {
+		t.Fatalf("update deps: %v", err)
+	}
+
+	output, err = ctx.exec("dep", "check")
+	if err == nil {
+		t.Error("expected non-zero exit when cycles exist")
+	}
+	for _, want := range []string{"a-1 -> b-1 -> c-1 -> a-1", "c-1 -> c-1 (self-dependency)", "2 dependency cycle(s) found"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestDepTreeTotals tests the --totals estimate rollup
+func TestDepTreeTotals(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, tk := range []*ticket.Ticket{
+		{ID: "t-a", Estimate: 5},
+		{ID: "t-b", Deps: []string{"t-a"}, Estimate: 8},
+	} {
+		tk.Status, tk.Type, tk.Title = ticket.StatusOpen, ticket.TypeTask, "Ticket "+tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	output, err := ctx.exec("dep", "tree", "--totals", "t-b")
+	if err != nil {
+		t.Fatalf("dep tree --totals error: %v", err)
+	}
+	if !strings.Contains(output, "t-b [open] Ticket t-b (Σ13)") || !strings.Contains(output, "t-a [open] Ticket t-a (Σ5)") {
+		t.Errorf("expected subtree totals, got:\n%s", output)
+	}
+}
+
 // TestDepCommandOutput tests the output format of dep commands
 func TestDepCommandOutput(t *testing.T) {
 	t.Run("dep output format", func(t *testing.T) {
@@ -624,3 +787,88 @@ func TestDepCommandOutput(t *testing.T) {
 		}
 	})
 }
+
+func TestUndepReportsCounts(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	a, _ := ctx.exec("new", "A")
+	a = strings.TrimSpace(a)
+	b, _ := ctx.exec("new", "B")
+	b = strings.TrimSpace(b)
+	c, _ := ctx.exec("new", "C")
+	c = strings.TrimSpace(c)
+	ctx.exec("dep", a, b)
+	ctx.exec("dep", a, c)
+	ctx.exec("dep", c, b)
+
+	output, err := ctx.exec("undep", a, b)
+	if err != nil {
+		t.Fatalf("undep error: %v", err)
+	}
+
+	ta, _ := ctx.store().Get(a)
+	if len(ta.Deps) != 1 {
+		t.Fatalf("persisted deps of %s = %v, want 1", a, ta.Deps)
+	}
+	want := a + " now has 1 dependency, " + b + " now has 1 dependant"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+}
+
+// TestDepDotCommand tests DOT export of the whole graph and of a subtree
+func TestDepDotCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, tk := range []*ticket.Ticket{
+		{ID: "a", Deps: []string{"b", "c"}, Status: ticket.StatusOpen},
+		{ID: "b", Deps: []string{"c"}, Status: ticket.StatusInProgress},
+		{ID: "c", Status: ticket.StatusClosed},
+		{ID: "other", Status: ticket.StatusOpen},
+	} {
+		tk.Type, tk.Title = ticket.TypeTask, "Ticket "+tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	output, err := ctx.exec("dep", "dot")
+	if err != nil {
+		t.Fatalf("dep dot error: %v", err)
+	}
+	if !strings.HasPrefix(output, "digraph tickets {") || !strings.HasSuffix(output, "}\n") {
+		t.Errorf("expected a digraph, got:\n%s", output)
+	}
+	for _, want := range []string{
+		`"a" -> "b";`,
+		`"a" -> "c";`,
+		`"b" -> "c";`,
+		`"b" [label="b [in_progress]\nTicket b", fillcolor=lightblue];`,
+		`"c" [label="c [closed]\nTicket c", fillcolor=lightgray];`,
+		`"other" [label=`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("dep dot missing %s, got:\n%s", want, output)
+		}
+	}
+
+	// A subtree holds the root and everything it depends on
+	output, err = ctx.exec("dep", "dot", "b")
+	if err != nil {
+		t.Fatalf("dep dot b error: %v", err)
+	}
+	if !strings.Contains(output, `"b" -> "c";`) {
+		t.Errorf("subtree missing b -> c, got:\n%s", output)
+	}
+	for _, unwanted := range []string{`"a"`, `"other"`} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("subtree of b should not include %s, got:\n%s", unwanted, output)
+		}
+	}
+
+	if _, err := ctx.exec("dep", "dot", "missing"); err == nil {
+		t.Error("expected an error for an unknown root")
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var dueCmd = &cobra.Command{
+	Use:   "due <id> <date>",
+	Short: "Set a ticket's due date",
+	Long: `Set a ticket's due date. The date is given as YYYY-MM-DD (midnight UTC)
+or as a full RFC3339 timestamp, and is stored in the due frontmatter field.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDue,
+}
+
+var overdueCmd = &cobra.Command{
+	Use:   "overdue",
+	Short: "List open tickets past their due date",
+	Long: `List open and in_progress tickets whose due date is before now, most
+overdue first.`,
+	Args: cobra.NoArgs,
+	RunE: runOverdue,
+}
+
+func init() {
+	rootCmd.AddCommand(dueCmd)
+	rootCmd.AddCommand(overdueCmd)
+}
+
+func runDue(cmd *cobra.Command, args []string) error {
+	due, err := ticket.ParseDue(args[1])
+	if err != nil {
+		return err
+	}
+
+	id, err := store.UpdateField(args[0], "due", due.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Set due date of %s to %s\n", id, due.Format(ticket.DueLayout))
+	return nil
+}
+
+func runOverdue(cmd *cobra.Command, args []string) error {
+	tickets, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, t := range overdueTickets(tickets, now) {
+		fmt.Printf("%-8s [%s] - %s (due %s, %s overdue)\n",
+			displayID(t.ID), t.Status, t.Title, t.Due.Format(ticket.DueLayout), formatOverdue(now.Sub(t.Due)))
+	}
+	return nil
+}
+
+// overdueTickets returns the unclosed tickets due before now, ordered by due
+// date (most overdue first), then priority, then ID
+func overdueTickets(tickets []*ticket.Ticket, now time.Time) []*ticket.Ticket {
+	var overdue []*ticket.Ticket
+	for _, t := range tickets {
+		if t.Overdue(now) {
+			overdue = append(overdue, t)
+		}
+	}
+
+	sort.Slice(overdue, func(i, j int) bool {
+		if !overdue[i].Due.Equal(overdue[j].Due) {
+			return overdue[i].Due.Before(overdue[j].Due)
+		}
+		if overdue[i].Priority != overdue[j].Priority {
+			return overdue[i].Priority < overdue[j].Priority
+		}
+		return overdue[i].ID < overdue[j].ID
+	})
+	return overdue
+}
+
+// formatOverdue renders how long a ticket is overdue in whole days, or whole
+// hours when less than a day
+func formatOverdue(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return "<1h"
+}
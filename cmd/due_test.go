@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// TestDueCommand tests setting a due date with due
+func TestDueCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Deadline")
+	id = strings.TrimSpace(id)
+
+	output, err := ctx.exec("due", id, "2025-03-01")
+	if err != nil {
+		t.Fatalf("due failed: %v", err)
+	}
+	if !strings.Contains(output, "2025-03-01") {
+		t.Errorf("should confirm the date, got: %s", output)
+	}
+
+	tk, _ := ctx.store().Get(id)
+	if want := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC); !tk.Due.Equal(want) {
+		t.Errorf("Due = %v, want %v", tk.Due, want)
+	}
+
+	if _, err := ctx.exec("due", id, "next week"); err == nil {
+		t.Error("due with an invalid date should fail")
+	}
+}
+
+// TestOverdueCommand tests listing tickets past their due date
+func TestOverdueCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	idOld, _ := ctx.exec("new", "Long overdue")
+	idOld = strings.TrimSpace(idOld)
+	idRecent, _ := ctx.exec("new", "Recently overdue")
+	idRecent = strings.TrimSpace(idRecent)
+	idClosed, _ := ctx.exec("new", "Closed overdue")
+	idClosed = strings.TrimSpace(idClosed)
+	idFuture, _ := ctx.exec("new", "Not yet due")
+	idFuture = strings.TrimSpace(idFuture)
+
+	ctx.exec("due", idOld, "2001-01-01")
+	ctx.exec("due", idRecent, "2002-01-01")
+	ctx.exec("due", idClosed, "2001-01-01")
+	ctx.exec("close", idClosed)
+	ctx.exec("due", idFuture, time.Now().AddDate(1, 0, 0).Format(ticket.DueLayout))
+
+	output, err := ctx.exec("overdue")
+	if err != nil {
+		t.Fatalf("overdue failed: %v", err)
+	}
+
+	posOld := strings.Index(output, idOld)
+	posRecent := strings.Index(output, idRecent)
+	if posOld == -1 || posRecent == -1 {
+		t.Fatalf("should list both overdue tickets, got:\n%s", output)
+	}
+	if posOld > posRecent {
+		t.Errorf("most overdue ticket should come first, got:\n%s", output)
+	}
+	if strings.Contains(output, idClosed) || strings.Contains(output, idFuture) {
+		t.Errorf("should skip closed and future tickets, got:\n%s", output)
+	}
+}
+
+func TestOverdueTickets(t *testing.T) {
+	now := time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+	tickets := []*ticket.Ticket{
+		{ID: "b", Status: ticket.StatusOpen, Priority: 2, Due: now.AddDate(0, 0, -1)},
+		{ID: "a", Status: ticket.StatusInProgress, Priority: 2, Due: now.AddDate(0, 0, -1)},
+		{ID: "c", Status: ticket.StatusOpen, Priority: 0, Due: now.AddDate(0, 0, -1)},
+		{ID: "old", Status: ticket.StatusOpen, Priority: 4, Due: now.AddDate(0, 0, -5)},
+		{ID: "done", Status: ticket.StatusClosed, Due: now.AddDate(0, 0, -5)},
+		{ID: "later", Status: ticket.StatusOpen, Due: now.AddDate(0, 0, 1)},
+		{ID: "undated", Status: ticket.StatusOpen},
+	}
+
+	var got []string
+	for _, tk := range overdueTickets(tickets, now) {
+		got = append(got, tk.ID)
+	}
+	if want := "old c a b"; strings.Join(got, " ") != want {
+		t.Errorf("overdueTickets() = %v, want %s", got, want)
+	}
+
+	if s := formatOverdue(50 * time.Hour); s != "2d" {
+		t.Errorf("formatOverdue(50h) = %q, want 2d", s)
+	}
+	if s := formatOverdue(5 * time.Hour); s != "5h" {
+		t.Errorf("formatOverdue(5h) = %q, want 5h", s)
+	}
+}
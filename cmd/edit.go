@@ -4,16 +4,24 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
+	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
 
 var editCmd = &cobra.Command{
 	Use:   "edit <id>",
 	Short: "Open ticket in $EDITOR",
-	Long:  "Open the ticket file in the editor specified by the $EDITOR environment variable.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runEdit,
+	Long: `Open a copy of the ticket file in $EDITOR (default vi). When the editor
+exits, the copy is validated like 'tk validate' does and written back over
+the ticket. If the editor fails, or the edited ticket doesn't parse, the
+ticket is left untouched and the edited copy is kept so you can retry.
+The id field can't be changed here; use 'tk reid'.
+
+Without a terminal, the path of the ticket file is printed instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
 }
 
 func init() {
@@ -32,13 +40,75 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Get editor
+	id, original, err := store.ReadRaw(args[0])
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", "tk-edit-"+id+"-*.md")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := f.Name()
+	_, err = f.WriteString(original)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+
+	if err := launchEditor(tmpPath); err != nil {
+		return fmt.Errorf("running editor: %w; %s left unchanged, edits kept in %s", err, id, tmpPath)
+	}
+
+	data, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return fmt.Errorf("reading temp file: %w", err)
+	}
+	edited := string(data)
+
+	if err := checkEdited(id, edited); err != nil {
+		return fmt.Errorf("invalid ticket: %w; %s left unchanged, edits kept in %s", err, id, tmpPath)
+	}
+	os.Remove(tmpPath)
+
+	if edited == original {
+		fmt.Printf("No changes to %s\n", id)
+		return nil
+	}
+	if err := store.WriteRaw(id, edited); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s\n", id)
+	return nil
+}
+
+// checkEdited validates edited ticket content as validate does, and rejects
+// a changed id
+func checkEdited(id, content string) error {
+	if _, err := ticket.Canonicalize(content); err != nil {
+		return err
+	}
+	t, err := ticket.Parse(strings.NewReader(content))
+	if err != nil {
+		return err
+	}
+	if t.ID != id {
+		return fmt.Errorf("id changed from %s to %s (use 'tk reid')", id, t.ID)
+	}
+	return nil
+}
+
+// launchEditor opens a file in $EDITOR (default vi) and waits for it to exit.
+// It is a variable so tests can substitute a non-interactive editor.
+var launchEditor = func(path string) error {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "vi"
 	}
 
-	// Run editor
 	editorCmd := exec.Command(editor, path)
 	editorCmd.Stdin = os.Stdin
 	editorCmd.Stdout = os.Stdout
@@ -47,7 +117,9 @@ func runEdit(cmd *cobra.Command, args []string) error {
 	return editorCmd.Run()
 }
 
-func isTerminal() bool {
+// isTerminal reports whether stdin is a terminal. It is a variable so tests
+// can exercise interactive paths.
+var isTerminal = func() bool {
 	fileInfo, _ := os.Stdin.Stat()
 	return (fileInfo.Mode() & os.ModeCharDevice) != 0
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -104,3 +105,125 @@ func TestEditCommand(t *testing.T) {
 		}
 	})
 }
+
+// withTerminal makes isTerminal report a terminal for the duration of a test
+func withTerminal(t *testing.T) {
+	t.Helper()
+	orig := isTerminal
+	isTerminal = func() bool { return true }
+	t.Cleanup(func() { isTerminal = orig })
+}
+
+func TestEditCommand_Interactive(t *testing.T) {
+	t.Run("writes back a valid edit", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		withTerminal(t)
+
+		id, _ := ctx.exec("new", "Edit me")
+		id = strings.TrimSpace(id)
+		path, _ := ctx.store().Path(id)
+
+		withStubEditor(t, func(tmp string) error {
+			if tmp == path {
+				t.Errorf("editor should get a copy, not the ticket file")
+			}
+			content, err := os.ReadFile(tmp)
+			if err != nil {
+				return err
+			}
+			edited := strings.Replace(string(content), "# Edit me", "# Edited", 1)
+			return os.WriteFile(tmp, []byte(edited), 0644)
+		})
+
+		output, err := ctx.exec("edit", id)
+		if err != nil {
+			t.Fatalf("edit error: %v", err)
+		}
+		if !strings.Contains(output, "Updated "+id) {
+			t.Errorf("unexpected output: %s", output)
+		}
+		tk, _ := ctx.store().Get(id)
+		if tk.Title != "Edited" {
+			t.Errorf("Title = %q, want Edited", tk.Title)
+		}
+	})
+
+	t.Run("invalid edit leaves ticket untouched", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		withTerminal(t)
+
+		id, _ := ctx.exec("new", "Keep me")
+		id = strings.TrimSpace(id)
+		path, _ := ctx.store().Path(id)
+		before, _ := os.ReadFile(path)
+
+		var tmpPath string
+		withStubEditor(t, func(tmp string) error {
+			tmpPath = tmp
+			return os.WriteFile(tmp, []byte("---\nid: [unclosed\n---\n# Broken\n"), 0644)
+		})
+
+		_, err := ctx.exec("edit", id)
+		if err == nil || !strings.Contains(err.Error(), "left unchanged") {
+			t.Fatalf("expected a parse error, got %v", err)
+		}
+		after, _ := os.ReadFile(path)
+		if string(after) != string(before) {
+			t.Errorf("ticket file changed after an invalid edit:\n%s", after)
+		}
+		if _, err := os.Stat(tmpPath); err != nil {
+			t.Errorf("edited copy should be kept for a retry: %v", err)
+		}
+		os.Remove(tmpPath)
+	})
+
+	t.Run("editor failure leaves ticket untouched", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		withTerminal(t)
+
+		id, _ := ctx.exec("new", "Keep me too")
+		id = strings.TrimSpace(id)
+		path, _ := ctx.store().Path(id)
+		before, _ := os.ReadFile(path)
+
+		var tmpPath string
+		withStubEditor(t, func(tmp string) error {
+			tmpPath = tmp
+			os.WriteFile(tmp, []byte("half-written"), 0644)
+			return fmt.Errorf("exit status 1")
+		})
+
+		if _, err := ctx.exec("edit", id); err == nil {
+			t.Fatal("expected an error when the editor fails")
+		}
+		after, _ := os.ReadFile(path)
+		if string(after) != string(before) {
+			t.Errorf("ticket file changed after an editor failure:\n%s", after)
+		}
+		os.Remove(tmpPath)
+	})
+
+	t.Run("changing the id is rejected", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		withTerminal(t)
+
+		id, _ := ctx.exec("new", "Stable id")
+		id = strings.TrimSpace(id)
+
+		var tmpPath string
+		withStubEditor(t, func(tmp string) error {
+			tmpPath = tmp
+			content, _ := os.ReadFile(tmp)
+			return os.WriteFile(tmp, []byte(strings.Replace(string(content), "id: "+id, "id: other-1234", 1)), 0644)
+		})
+
+		if _, err := ctx.exec("edit", id); err == nil || !strings.Contains(err.Error(), "reid") {
+			t.Errorf("expected id change to be rejected, got %v", err)
+		}
+		os.Remove(tmpPath)
+	})
+}
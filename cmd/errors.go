@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes reported for failed commands
+const (
+	exitError     = 1
+	exitNotFound  = 2
+	exitAmbiguous = 3
+)
+
+// jsonError is the structured error written to stderr with --json-errors
+type jsonError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+	ID    string `json:"id,omitempty"`
+}
+
+// classifyError maps an error to its structured form and exit code
+func classifyError(err error) (jsonError, int) {
+	je := jsonError{Error: err.Error(), Code: "error"}
+
+	var notFound ticket.ErrNotFound
+	if errors.As(err, &notFound) {
+		je.Code = "not_found"
+		je.ID = notFound.ID
+		return je, exitNotFound
+	}
+
+	var ambiguous ticket.ErrAmbiguous
+	if errors.As(err, &ambiguous) {
+		je.Code = "ambiguous"
+		je.ID = ambiguous.ID
+		return je, exitAmbiguous
+	}
+
+	return je, exitError
+}
+
+// silentExit ends a command with an exit code without printing an error
+type silentExit struct {
+	code int
+}
+
+func (e silentExit) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// failSilently returns a silentExit error and suppresses cobra's error output
+func failSilently(cmd *cobra.Command, code int) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return silentExit{code: code}
+}
+
+// writeJSONError writes err as a single JSON object and returns the exit code
+func writeJSONError(w io.Writer, err error) int {
+	je, code := classifyError(err)
+	data, marshalErr := json.Marshal(je)
+	if marshalErr != nil {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return code
+	}
+	fmt.Fprintln(w, string(data))
+	return code
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// TestJSONErrors tests structured error output
+func TestJSONErrors(t *testing.T) {
+	t.Run("not found show emits structured error", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Existing Ticket")
+
+		output, err := ctx.exec("show", "nonexistent", "--json-errors")
+		if err == nil {
+			t.Fatal("show should fail for non-existent ticket")
+		}
+		if strings.Contains(output, "Error:") {
+			t.Errorf("human-readable error should be suppressed, got: %s", output)
+		}
+
+		var buf bytes.Buffer
+		code := writeJSONError(&buf, err)
+		if code != exitNotFound {
+			t.Errorf("exit code = %d, want %d", code, exitNotFound)
+		}
+
+		var got jsonError
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("output is not valid JSON: %v (%s)", err, buf.String())
+		}
+		if got.Code != "not_found" {
+			t.Errorf("code = %q, want %q", got.Code, "not_found")
+		}
+		if got.ID != "nonexistent" {
+			t.Errorf("id = %q, want %q", got.ID, "nonexistent")
+		}
+		if got.Error == "" {
+			t.Error("error message should not be empty")
+		}
+	})
+
+	t.Run("classifies ambiguous and generic errors", func(t *testing.T) {
+		je, code := classifyError(ticket.ErrAmbiguous{ID: "a", Matches: []string{"a-1", "a-2"}})
+		if je.Code != "ambiguous" || code != exitAmbiguous {
+			t.Errorf("ambiguous: got code %q exit %d", je.Code, code)
+		}
+
+		je, code = classifyError(errors.New("boom"))
+		if je.Code != "error" || code != exitError {
+			t.Errorf("generic: got code %q exit %d", je.Code, code)
+		}
+		if je.ID != "" {
+			t.Errorf("generic error should have no id, got %q", je.ID)
+		}
+	})
+
+	t.Run("human errors remain default", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		output, err := ctx.exec("show", "nonexistent")
+		if err == nil {
+			t.Fatal("show should fail for non-existent ticket")
+		}
+		if !strings.Contains(output, "Error:") {
+			t.Errorf("expected human-readable error, got: %s", output)
+		}
+	})
+}
+
+// TestAmbiguousIDAcrossCommands checks that commands refuse a partial ID
+// matching several tickets rather than acting on one of them
+func TestAmbiguousIDAcrossCommands(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, id := range []string{"abc-1234", "def-1299", "xyz-5555"} {
+		tk := &ticket.Ticket{ID: id, Status: ticket.StatusOpen, Type: ticket.TypeTask, Priority: 2, Title: "Ticket " + id}
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commands := [][]string{
+		{"show", "12"},
+		{"status", "12", "closed"},
+		{"rm", "--force", "12"},
+		{"dep", "12", "xyz-5555"},
+		{"dep", "xyz-5555", "12"},
+		{"link", "12", "xyz-5555"},
+	}
+	for _, args := range commands {
+		_, err := ctx.exec(args...)
+		var ambiguous ticket.ErrAmbiguous
+		if !errors.As(err, &ambiguous) {
+			t.Errorf("%s: error = %v, want ErrAmbiguous", strings.Join(args, " "), err)
+			continue
+		}
+		if want := `ambiguous id "12": abc-1234, def-1299`; err.Error() != want {
+			t.Errorf("%s: error = %q, want %q", strings.Join(args, " "), err, want)
+		}
+	}
+
+	// Nothing was changed or removed
+	for _, id := range []string{"abc-1234", "def-1299", "xyz-5555"} {
+		tk, err := ctx.store().Get(id)
+		if err != nil {
+			t.Fatalf("%s should still exist: %v", id, err)
+		}
+		if tk.Status != ticket.StatusOpen || len(tk.Deps) > 0 || len(tk.Links) > 0 {
+			t.Errorf("%s was modified: status %s, deps %v, links %v", id, tk.Status, tk.Deps, tk.Links)
+		}
+	}
+}
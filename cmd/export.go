@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lo5/tk/internal/export"
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [--format markdown|html]",
+	Short: "Export all tickets to a single file",
+	Long: `Export all tickets to stdout, or to a file with --output, in the given
+format.
+
+Formats:
+  markdown  (default) every ticket file's raw content, byte for byte,
+            separated by lines holding only ---, or by "--- no newline at
+            end of ticket" after a file without a final newline. tk import
+            reads it back into identical files, for backups and moving
+            tickets between repositories
+  html      a single self-contained HTML page (inline CSS) listing tickets
+            grouped by status, with collapsible bodies rendered from
+            markdown and dependencies, links and parents as anchors within
+            the page
+
+The --output file is replaced atomically, so a failed export leaves an
+earlier one intact.
+
+  tk export -o backup.md && tk import --overwrite < backup.md
+  tk export --format html > tickets.html`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+var (
+	exportFormat string
+	exportOutput string
+)
+
+// exportFormats lists the supported --format values, default first
+var exportFormats = []string{"markdown", "html"}
+
+// exportSeparator is written between tickets in the markdown format
+const exportSeparator = "---\n"
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", exportFormats[0], "Output format ("+strings.Join(exportFormats, "|")+")")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to FILE instead of stdout")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	var buf bytes.Buffer
+	switch exportFormat {
+	case "markdown":
+		if err := exportMarkdown(&buf); err != nil {
+			return err
+		}
+	case "html":
+		tickets, err := store.List()
+		if err != nil {
+			return err
+		}
+		if err := export.HTML(&buf, tickets); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported format '%s'. Must be one of: %s", exportFormat, strings.Join(exportFormats, ", "))
+	}
+
+	if exportOutput == "" {
+		_, err := cmd.OutOrStdout().Write(buf.Bytes())
+		return err
+	}
+	if err := writeFileAtomic(exportOutput, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", exportOutput)
+	return nil
+}
+
+// exportMarkdown writes the raw content of every ticket file, separated by
+// exportSeparator. The separator must start a line, so a file lacking a
+// final newline is followed by a newline and ticket.NoNewlineSeparator
+// instead, which import strips again.
+func exportMarkdown(w io.Writer) error {
+	separator := ""
+	return store.Walk(func(id, content string) error {
+		if _, err := io.WriteString(w, separator+content); err != nil {
+			return err
+		}
+		separator = exportSeparator
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			separator = "\n" + ticket.NoNewlineSeparator + "\n"
+		}
+		return nil
+	})
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames it
+// into place
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestExportCommand(t *testing.T) {
+	t.Run("html report", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "First ticket")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "Second ticket")
+		b = strings.TrimSpace(b)
+		ctx.exec("link", a, b)
+
+		output, err := ctx.exec("export", "--format", "html")
+		if err != nil {
+			t.Fatalf("export error: %v", err)
+		}
+		for _, want := range []string{
+			"<!DOCTYPE html>", "</html>",
+			`id="ticket-` + a + `"`, "First ticket",
+			`id="ticket-` + b + `"`, "Second ticket",
+			`<a href="#ticket-` + b + `">`, `<a href="#ticket-` + a + `">`,
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected %q in export:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("format is validated", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		if output, err := ctx.exec("export", "--format", "pdf"); err == nil || !strings.Contains(output, "unsupported format") {
+			t.Errorf("expected unsupported format error, got %v: %s", err, output)
+		}
+	})
+}
+
+// ticketFiles returns the content of each ticket file by name
+func ticketFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files
+}
+
+func TestExportMarkdown(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	a, _ := ctx.exec("new", "First ticket")
+	a = strings.TrimSpace(a)
+	b, _ := ctx.exec("new", "Second ticket")
+	b = strings.TrimSpace(b)
+	ctx.exec("dep", b, a)
+	ctx.exec("note", a, "A note")
+
+	// Hand-written formatting that tk itself wouldn't produce
+	custom := "---\nid: hand-0001\nstatus: open\ndeps: []   # keep this comment\nlinks: []\n---\n# Hand written\n\nAbove a rule\n---\nBelow a rule\n"
+	if err := os.WriteFile(filepath.Join(ctx.ticketsDir, "hand-0001.md"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Files without a final newline, one sorting before every other ticket
+	// and one after. The first ends in a rule, which must stay in its body.
+	for name, content := range map[string]string{
+		"aaa-0001.md": "---\nid: aaa-0001\nstatus: open\n---\n# No newline\n\nEnds in a rule\n---",
+		"zzz-0001.md": "---\nid: zzz-0001\nstatus: open\n---\n# Last, no newline",
+	} {
+		if err := os.WriteFile(filepath.Join(ctx.ticketsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := ticketFiles(t, ctx.ticketsDir)
+
+	t.Run("stdout", func(t *testing.T) {
+		output, err := ctx.exec("export")
+		if err != nil {
+			t.Fatalf("export error: %v", err)
+		}
+		for _, content := range before {
+			if !strings.Contains(output, content) {
+				t.Errorf("export should contain each ticket verbatim, missing:\n%s", content)
+			}
+		}
+		separators := strings.Count(output, "\n---\n---\n") +
+			strings.Count(output, "\n"+ticket.NoNewlineSeparator+"\n---\n")
+		if separators != len(before)-1 {
+			t.Errorf("expected %d separators, got %d in:\n%s", len(before)-1, separators, output)
+		}
+	})
+
+	t.Run("round trip through import", func(t *testing.T) {
+		backup := filepath.Join(t.TempDir(), "backup.md")
+		if output, err := ctx.exec("export", "-o", backup); err != nil {
+			t.Fatalf("export -o error: %v\n%s", err, output)
+		}
+		data, err := os.ReadFile(backup)
+		if err != nil {
+			t.Fatalf("backup not written: %v", err)
+		}
+
+		for name := range before {
+			os.Remove(filepath.Join(ctx.ticketsDir, name))
+		}
+		if output, err := ctx.execWithStdin(string(data), "import"); err != nil {
+			t.Fatalf("import error: %v\n%s", err, output)
+		}
+
+		after := ticketFiles(t, ctx.ticketsDir)
+		if len(after) != len(before) {
+			t.Fatalf("imported %d files, exported %d", len(after), len(before))
+		}
+		for name, content := range before {
+			if after[name] != content {
+				t.Errorf("%s changed in the round trip:\n%q\nvs\n%q", name, after[name], content)
+			}
+		}
+	})
+}
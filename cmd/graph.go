@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lo5/tk/internal/deptree"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [--output FILE]",
+	Short: "Export the dependency graph as Graphviz DOT",
+	Long: `Export all tickets and their dependencies as a Graphviz DOT digraph.
+Edges point from a ticket to the tickets it depends on.
+
+By default the DOT text is printed to stdout. Use --output to write to a file;
+when Graphviz's dot is on PATH, the graph is rendered in the format given by
+the file extension (e.g. graph.png, graph.svg). Files ending in .dot or .gv,
+or any file when dot is not installed, receive the DOT text.`,
+	Args: cobra.NoArgs,
+	RunE: runGraph,
+}
+
+var graphOutput string
+
+// findDot locates Graphviz's dot executable. It is a variable so tests can
+// simulate dot being absent.
+var findDot = func() (string, error) {
+	return exec.LookPath("dot")
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Write to FILE, rendering via dot based on the extension")
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	tickets, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	dot := deptree.DOT(tickets)
+	if graphOutput == "" {
+		fmt.Print(dot)
+		return nil
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(graphOutput)), ".")
+	if format == "" || format == "dot" || format == "gv" {
+		return writeDOT(graphOutput, dot)
+	}
+
+	dotPath, err := findDot()
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: graphviz 'dot' not found on PATH; wrote DOT text to %s\n", graphOutput)
+		return writeDOT(graphOutput, dot)
+	}
+
+	render := exec.Command(dotPath, "-T"+format, "-o", graphOutput)
+	render.Stdin = strings.NewReader(dot)
+	var stderr bytes.Buffer
+	render.Stderr = &stderr
+	if err := render.Run(); err != nil {
+		return fmt.Errorf("rendering %s with dot: %w: %s", graphOutput, err, strings.TrimSpace(stderr.String()))
+	}
+
+	fmt.Printf("Wrote %s\n", graphOutput)
+	return nil
+}
+
+// writeDOT writes the DOT text to path
+func writeDOT(path, dot string) error {
+	if err := os.WriteFile(path, []byte(dot), 0644); err != nil {
+		return fmt.Errorf("writing graph: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", path)
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGraphCommand(t *testing.T) {
+	t.Run("prints DOT to stdout by default", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "A")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "B")
+		b = strings.TrimSpace(b)
+		ctx.exec("dep", a, b)
+
+		output, err := ctx.exec("graph")
+		if err != nil {
+			t.Fatalf("graph error: %v", err)
+		}
+		if !strings.HasPrefix(output, "digraph tickets {") {
+			t.Errorf("expected a digraph, got:\n%s", output)
+		}
+		if edge := `"` + a + `" -> "` + b + `";`; !strings.Contains(output, edge) {
+			t.Errorf("expected edge %s, got:\n%s", edge, output)
+		}
+	})
+
+	t.Run("falls back to DOT when dot is absent", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		orig := findDot
+		findDot = func() (string, error) { return "", errors.New("not found") }
+		defer func() { findDot = orig }()
+
+		ctx.exec("new", "A")
+		out := filepath.Join(t.TempDir(), "graph.png")
+
+		output, err := ctx.exec("graph", "--output", out)
+		if err != nil {
+			t.Fatalf("graph --output error: %v", err)
+		}
+		if !strings.Contains(output, "Warning: graphviz 'dot' not found") {
+			t.Errorf("expected warning, got: %s", output)
+		}
+
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("reading output: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "digraph tickets {") {
+			t.Errorf("expected DOT text in %s, got:\n%s", out, data)
+		}
+	})
+}
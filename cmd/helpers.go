@@ -2,11 +2,24 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/lo5/tk/internal/ticket"
 )
 
+// loadGraph parses every ticket once and indexes them in a dependency graph,
+// for commands that walk relationships. The graph lists tickets in ID order.
+func loadGraph() (*deptree.DependencyGraph, error) {
+	tickets, err := store.ListParsed()
+	if err != nil {
+		return nil, err
+	}
+	return deptree.NewGraphFromMap(tickets), nil
+}
+
 // formatBlockingTickets formats a list of tickets for error messages
 func formatBlockingTickets(tickets []*ticket.Ticket) string {
 	var lines []string
@@ -33,6 +46,46 @@ func findDependants(allTickets []*ticket.Ticket, targetID string) []*ticket.Tick
 	return dependants
 }
 
+// parseAge parses a duration such as "14d", "2w" or any time.ParseDuration
+// value ("36h", "90m"). Days and weeks are not supported by time.ParseDuration.
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration '%s'", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration '%s'. Use e.g. 14d, 2w or 36h", s)
+	}
+	return d, nil
+}
+
+// priorityLess orders tickets by priority (0 first), then by ID
+func priorityLess(a, b *ticket.Ticket) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.ID < b.ID
+}
+
+// countNoun formats a count with a singular or plural noun, e.g. "1 link",
+// "0 links" or "2 dependencies"
+func countNoun(n int, singular string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", singular)
+	}
+	if strings.HasSuffix(singular, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(singular, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, singular)
+}
+
 // findChildren returns tickets that have targetID as parent
 func findChildren(allTickets []*ticket.Ticket, targetID string) []*ticket.Ticket {
 	var children []*ticket.Ticket
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// historyEntry is a single event in a ticket's merged timeline
+type historyEntry struct {
+	when   time.Time
+	source string
+	text   string
+}
+
+// gitHistory returns the commits touching a ticket file, newest first.
+// It is a variable so tests can stub out git.
+var gitHistory = func(path string) ([]historyEntry, error) {
+	out, err := exec.Command("git", "-C", filepath.Dir(path), "log", "--follow",
+		"--format=%aI%x09%s", "--", filepath.Base(path)).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []historyEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		stamp, subject, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		when, err := time.Parse(time.RFC3339, stamp)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, historyEntry{when: when, source: "git", text: subject})
+	}
+	return entries, nil
+}
+
+// parseHistorySection extracts timestamped entries from the body's
+// "## History" section. Entries look like "- 2024-01-02T15:04:05Z open -> closed".
+func parseHistorySection(body string) []historyEntry {
+	section, _ := ticket.GetSection(body, ticket.HistorySection)
+
+	var entries []historyEntry
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "- ")
+		stamp, text, _ := strings.Cut(line, " ")
+		when, err := time.Parse(time.RFC3339, strings.Trim(stamp, "*"))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, historyEntry{when: when, source: "history", text: strings.TrimSpace(text)})
+	}
+	return entries
+}
+
+// mergeHistory combines body and git entries in chronological order. A git
+// entry with the same timestamp as a body entry is dropped as a duplicate.
+func mergeHistory(body, git []historyEntry) []historyEntry {
+	seen := make(map[int64]bool)
+	merged := append([]historyEntry{}, body...)
+	for _, e := range body {
+		seen[e.when.Unix()] = true
+	}
+	for _, e := range git {
+		if !seen[e.when.Unix()] {
+			merged = append(merged, e)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].when.Before(merged[j].when)
+	})
+	return merged
+}
+
+// printHistory prints the merged timeline for a ticket
+func printHistory(path, body string) {
+	// Git is optional: without it (or outside a repo) only the body is used
+	git, _ := gitHistory(path)
+	entries := mergeHistory(parseHistorySection(body), git)
+
+	fmt.Println()
+	fmt.Println("## Timeline")
+	if len(entries) == 0 {
+		fmt.Println("- (no history)")
+		return
+	}
+	for _, e := range entries {
+		fmt.Printf("- %s [%s] %s\n", e.when.UTC().Format(time.RFC3339), e.source, e.text)
+	}
+}
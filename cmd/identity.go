@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// currentUser resolves the identity of the person running tk: $TK_USER,
+// else git user.name, else $USER. Returns an empty string if none is set.
+// Every command that needs the user goes through it, so tickets new assigns
+// to you are the ones mine lists. It is a variable so tests can substitute
+// a fixed identity.
+var currentUser = func() string {
+	if user := os.Getenv("TK_USER"); user != "" {
+		return user
+	}
+	if user := gitUserName(); user != "" {
+		return user
+	}
+	return os.Getenv("USER")
+}
+
+// gitUserName returns git's user.name, or an empty string if it isn't set.
+// It is a variable so tests can run without a git identity.
+var gitUserName = func() string {
+	out, err := exec.Command("git", "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// noUserHelp says how to set the current user, for errors when there is none
+const noUserHelp = "set TK_USER, git user.name or USER"
+
+// resolveUser expands "me" to the current user; other names are returned
+// unchanged
+func resolveUser(name string) (string, error) {
+	if name != "me" {
+		return name, nil
+	}
+	user := currentUser()
+	if user == "" {
+		return "", fmt.Errorf("cannot resolve 'me': %s", noUserHelp)
+	}
+	return user, nil
+}
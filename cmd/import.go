@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create tickets from markdown on stdin",
+	Long: `Read one or more ticket files from stdin and create them in the store,
+keeping their IDs. Tickets may simply be concatenated, each starting with
+its own frontmatter, or separated by a line holding only ---. A --- line in
+a ticket's body that doesn't open frontmatter with an id is left in the body.
+A separator reading "--- no newline at end of ticket" also drops the newline
+ending the ticket before it.
+
+Each ticket file is written exactly as given, so the output of tk export
+imports back unchanged. Tickets whose ID already exists are reported on
+stderr and skipped; pass --overwrite to replace them instead. The imported
+IDs are printed.
+
+  cat saved/*.md | tk import
+  ssh host 'cat project/.tickets/*.md' | tk import --overwrite`,
+	Args: cobra.NoArgs,
+	RunE: runImport,
+}
+
+var importOverwrite bool
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().BoolVar(&importOverwrite, "overwrite", false, "Replace existing tickets with the same ID")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	data, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+
+	docs, err := parseImport(string(data))
+	if err != nil {
+		return err
+	}
+
+	created, replaced, skipped := 0, 0, 0
+	for _, doc := range docs {
+		id := doc.ticket.ID
+		exists := idExists(id)
+		if exists && !importOverwrite {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %s: ticket already exists\n", id)
+			skipped++
+			continue
+		}
+		if exists {
+			replaced++
+		} else {
+			// Create puts the file where the store expects it; the raw write
+			// then keeps the document byte for byte
+			if err := store.Create(doc.ticket); err != nil {
+				return fmt.Errorf("importing %s: %w", id, err)
+			}
+			created++
+		}
+		if err := store.WriteRaw(id, doc.content); err != nil {
+			return fmt.Errorf("importing %s: %w", id, err)
+		}
+		fmt.Println(id)
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Imported %d ticket(s): %d created, %d replaced, %d skipped\n",
+		created+replaced, created, replaced, skipped)
+	return nil
+}
+
+// importDoc is one ticket read by import, with its content as given
+type importDoc struct {
+	ticket  *ticket.Ticket
+	content string
+}
+
+// parseImport parses every ticket in the input before any is written, so a
+// malformed ticket imports nothing. IDs must be usable and not repeat.
+func parseImport(content string) ([]importDoc, error) {
+	docs := ticket.SplitDocuments(content)
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no tickets found on stdin")
+	}
+
+	seen := make(map[string]bool)
+	parsed := make([]importDoc, 0, len(docs))
+	for i, doc := range docs {
+		t, err := ticket.Parse(strings.NewReader(doc))
+		if err != nil {
+			return nil, fmt.Errorf("ticket %d: %w", i+1, err)
+		}
+		if t.ID == "" || strings.ContainsAny(t.ID, " \t\n/\\:,[]") {
+			return nil, fmt.Errorf("ticket %d: invalid ticket ID %q", i+1, t.ID)
+		}
+		if seen[t.ID] {
+			return nil, fmt.Errorf("ticket %d: %s appears more than once", i+1, t.ID)
+		}
+		seen[t.ID] = true
+		parsed = append(parsed, importDoc{ticket: t, content: doc})
+	}
+	return parsed, nil
+}
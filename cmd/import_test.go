@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	input := `---
+id: imp-0001
+status: open
+deps: []
+links: []
+type: bug
+priority: 1
+---
+# First imported
+
+Body text
+---
+More body after a rule
+---
+---
+id: imp-0002
+status: closed
+deps: [imp-0001]
+links: []
+---
+# Second imported
+`
+
+	t.Run("creates tickets with their IDs", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		output, err := ctx.execWithStdin(input, "import")
+		if err != nil {
+			t.Fatalf("import failed: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "2 created, 0 replaced, 0 skipped") {
+			t.Errorf("expected a summary, got:\n%s", output)
+		}
+
+		first, err := ctx.store().Get("imp-0001")
+		if err != nil {
+			t.Fatalf("imp-0001 not imported: %v", err)
+		}
+		if first.Title != "First imported" || first.Priority != 1 {
+			t.Errorf("imp-0001 = %q P%d", first.Title, first.Priority)
+		}
+		if !strings.Contains(first.Body, "More body after a rule") {
+			t.Errorf("horizontal rule should stay in the body, got %q", first.Body)
+		}
+
+		second, err := ctx.store().Get("imp-0002")
+		if err != nil {
+			t.Fatalf("imp-0002 not imported: %v", err)
+		}
+		if len(second.Deps) != 1 || second.Deps[0] != "imp-0001" {
+			t.Errorf("imp-0002 deps = %v", second.Deps)
+		}
+	})
+
+	t.Run("skips existing tickets", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.execWithStdin(input, "import")
+		ctx.exec("status", "imp-0002", "open")
+
+		output, err := ctx.execWithStdin(input, "import")
+		if err != nil {
+			t.Fatalf("import failed: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "Skipped imp-0001") || !strings.Contains(output, "Skipped imp-0002") {
+			t.Errorf("collisions should be reported, got:\n%s", output)
+		}
+		if tk, _ := ctx.store().Get("imp-0002"); tk.Status != "open" {
+			t.Errorf("skipped ticket should be unchanged, status = %s", tk.Status)
+		}
+	})
+
+	t.Run("overwrite replaces existing tickets", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.execWithStdin(input, "import")
+		ctx.exec("status", "imp-0002", "open")
+
+		output, err := ctx.execWithStdin(input, "import", "--overwrite")
+		if err != nil {
+			t.Fatalf("import --overwrite failed: %v\n%s", err, output)
+		}
+		if !strings.Contains(output, "0 created, 2 replaced") {
+			t.Errorf("expected replacements, got:\n%s", output)
+		}
+		if tk, _ := ctx.store().Get("imp-0002"); tk.Status != "closed" {
+			t.Errorf("ticket should be replaced, status = %s", tk.Status)
+		}
+	})
+
+	t.Run("malformed input imports nothing", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		bad := input + "---\nid: bad/id\n---\n# Bad\n"
+		if _, err := ctx.execWithStdin(bad, "import"); err == nil {
+			t.Fatal("expected an error for an invalid ID")
+		}
+		if _, err := ctx.store().Get("imp-0001"); err == nil {
+			t.Error("no ticket should be imported when one is invalid")
+		}
+
+		if _, err := ctx.execWithStdin("# No frontmatter\n", "import"); err == nil {
+			t.Error("expected an error when stdin holds no tickets")
+		}
+	})
+}
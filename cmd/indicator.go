@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/lo5/tk/internal/deptree"
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// indicatorGlyphs are the readiness marks shown by ls and show
+type indicatorGlyphs struct {
+	ready   string
+	blocked string
+}
+
+var (
+	unicodeIndicators = indicatorGlyphs{ready: "●", blocked: "○"}
+	asciiIndicators   = indicatorGlyphs{ready: "*", blocked: "-"}
+)
+
+// stdoutIsTerminal reports whether stdout is a terminal. It is a variable so
+// tests can exercise the marks.
+var stdoutIsTerminal = func() bool {
+	fileInfo, err := os.Stdout.Stat()
+	return err == nil && (fileInfo.Mode()&os.ModeCharDevice) != 0
+}
+
+// showIndicators reports whether to print readiness marks. They are only for
+// people reading a terminal, so piped output stays the same as without them.
+func showIndicators(disabled bool) bool {
+	return !disabled && stdoutIsTerminal()
+}
+
+// readinessGlyphs returns the ASCII glyphs when asked to, or when the locale
+// does not use UTF-8
+func readinessGlyphs(ascii bool) indicatorGlyphs {
+	if ascii || !utf8Locale() {
+		return asciiIndicators
+	}
+	return unicodeIndicators
+}
+
+// readinessIndicator returns the glyph for a ticket: ready when it is open or
+// in progress with every dependency closed, blocked when it is unclosed
+// otherwise, and a blank of the same width when it is closed
+func readinessIndicator(graph *deptree.DependencyGraph, t *ticket.Ticket, glyphs indicatorGlyphs) string {
+	switch {
+	case graph.Status(t.ID) == ticket.StatusClosed:
+		return " "
+	case graph.IsReady(t.ID):
+		return glyphs.ready
+	default:
+		return glyphs.blocked
+	}
+}
+
+// utf8Locale reports whether the first locale variable set among LC_ALL,
+// LC_CTYPE and LANG names a UTF-8 encoding
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := strings.ToLower(os.Getenv(name)); v != "" {
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}
+
+// readinessWord names the state readinessIndicator marks
+func readinessWord(graph *deptree.DependencyGraph, t *ticket.Ticket) string {
+	if graph.IsReady(t.ID) {
+		return "ready"
+	}
+	return "blocked"
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// withStdoutTerminal makes stdoutIsTerminal report a terminal for the
+// duration of a test
+func withStdoutTerminal(t *testing.T) {
+	t.Helper()
+	orig := stdoutIsTerminal
+	stdoutIsTerminal = func() bool { return true }
+	t.Cleanup(func() { stdoutIsTerminal = orig })
+}
+
+func TestReadinessIndicators(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	dep, _ := ctx.exec("new", "Dependency")
+	dep = strings.TrimSpace(dep)
+	blocked, _ := ctx.exec("new", "Blocked work")
+	blocked = strings.TrimSpace(blocked)
+	done, _ := ctx.exec("new", "Done work")
+	done = strings.TrimSpace(done)
+	ctx.exec("dep", blocked, dep)
+	ctx.exec("close", done)
+
+	t.Run("ls with a UTF-8 locale", func(t *testing.T) {
+		withStdoutTerminal(t)
+		t.Setenv("LC_ALL", "en_US.UTF-8")
+		output, err := ctx.exec("ls")
+		if err != nil {
+			t.Fatalf("ls error: %v", err)
+		}
+		for _, want := range []string{"● " + dep, "○ " + blocked, "  " + done} {
+			if !strings.Contains(output, want) {
+				t.Errorf("ls output missing %q:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("ls falls back to ASCII", func(t *testing.T) {
+		withStdoutTerminal(t)
+		t.Setenv("LC_ALL", "C")
+		output, _ := ctx.exec("ls")
+		for _, want := range []string{"* " + dep, "- " + blocked} {
+			if !strings.Contains(output, want) {
+				t.Errorf("ls output missing %q:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("show marks the status and related tickets", func(t *testing.T) {
+		withStdoutTerminal(t)
+		t.Setenv("LC_ALL", "en_US.UTF-8")
+		output, err := ctx.exec("show", blocked, "--ascii")
+		if err != nil {
+			t.Fatalf("show error: %v", err)
+		}
+		for _, want := range []string{"status: open  # - blocked", "\n# Blocked work\n", "- * " + dep + " [open] Dependency"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("show output missing %q:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("no marks when piped", func(t *testing.T) {
+		t.Setenv("LC_ALL", "en_US.UTF-8")
+		output, _ := ctx.exec("ls")
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			if !strings.HasPrefix(line, dep) && !strings.HasPrefix(line, blocked) && !strings.HasPrefix(line, done) {
+				t.Errorf("piped ls lines should start with the ID:\n%s", output)
+			}
+		}
+		output, _ = ctx.exec("show", blocked)
+		if strings.Contains(output, "●") || strings.Contains(output, "○") || !strings.Contains(output, "status: open\n") {
+			t.Errorf("piped show should not be marked:\n%s", output)
+		}
+	})
+
+	t.Run("no-indicators", func(t *testing.T) {
+		withStdoutTerminal(t)
+		output, _ := ctx.exec("ls", "--no-indicators")
+		if !strings.Contains(output, "\n"+blocked) && !strings.HasPrefix(output, blocked) {
+			t.Errorf("lines should start with the ID without marks:\n%s", output)
+		}
+	})
+}
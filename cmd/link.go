@@ -1,18 +1,29 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
 
 var linkCmd = &cobra.Command{
-	Use:   "link <id> <id> [id...]",
+	Use:   "link <id> <id> [id...] [--rel=TYPE]",
 	Short: "Link tickets together",
 	Long: `Link two or more tickets together (symmetric relationship).
-If A links to B, then B also links to A.`,
-	Args: cobra.MinimumNArgs(2),
+If A links to B, then B also links to A.
+
+Use --rel to record the type of relationship between exactly two tickets,
+e.g. "tk link a b --rel duplicates". The second ticket records the inverse
+where one exists (duplicated-by, caused-by, superseded-by); other types are
+recorded as-is on both sides. Typed links are stored as id:type.
+
+Use --batch-json <file> (or - for stdin) to apply a JSON array of links,
+e.g. [{"id":"a","target":"b","rel":"duplicates"}]. Per-item results are
+printed as JSON.`,
+	Args: orBatchJSON(&linkBatchJSON, cobra.MinimumNArgs(2)),
 	RunE: runLink,
 }
 
@@ -23,18 +34,81 @@ var unlinkCmd = &cobra.Command{
 	RunE:  runUnlink,
 }
 
+var (
+	linkBatchJSON string
+	linkRel       string
+)
+
+// linkOp is a single --batch-json link between two tickets
+type linkOp struct {
+	ID     string `json:"id"`
+	Target string `json:"target"`
+	Rel    string `json:"rel"`
+}
+
 func init() {
+	linkCmd.Flags().StringVar(&linkBatchJSON, "batch-json", "", "Apply a JSON array of links from a file (- for stdin)")
+	linkCmd.Flags().StringVar(&linkRel, "rel", "", "Relationship type, e.g. duplicates, relates-to, caused-by")
+
 	rootCmd.AddCommand(linkCmd)
 	rootCmd.AddCommand(unlinkCmd)
 }
 
 func runLink(cmd *cobra.Command, args []string) error {
+	if linkBatchJSON != "" {
+		return runBatchJSON(cmd, linkBatchJSON, applyLinkOp)
+	}
+
+	ids, addedCount, err := linkTickets(args, linkRel)
+	if err != nil {
+		return err
+	}
+
+	if addedCount == 0 {
+		fmt.Println("All links already exist")
+	} else {
+		fmt.Printf("Added %d link(s) between %d tickets\n", addedCount, len(ids))
+	}
+
+	return nil
+}
+
+// applyLinkOp validates and applies a --batch-json link
+func applyLinkOp(item json.RawMessage) (string, error) {
+	var op linkOp
+	if err := decodeBatchItem(item, &op); err != nil {
+		return "", err
+	}
+	if op.ID == "" || op.Target == "" {
+		return "", fmt.Errorf("missing 'id' or 'target'")
+	}
+
+	ids, _, err := linkTickets([]string{op.ID, op.Target}, op.Rel)
+	if err != nil {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// linkTickets links every given ticket to every other, returning the
+// resolved IDs and the number of links added or retyped. A relationship
+// type may only be given when linking exactly two tickets.
+func linkTickets(args []string, rel string) ([]string, int, error) {
+	if rel != "" {
+		if len(args) != 2 {
+			return nil, 0, fmt.Errorf("--rel requires exactly two tickets")
+		}
+		if err := ticket.ValidateRel(rel); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	// Resolve all ticket IDs first
 	var ids []string
 	for _, arg := range args {
 		t, err := store.Get(arg)
 		if err != nil {
-			return err
+			return nil, 0, err
 		}
 		ids = append(ids, t.ID)
 	}
@@ -44,44 +118,51 @@ func runLink(cmd *cobra.Command, args []string) error {
 	for i, id := range ids {
 		t, err := store.Get(id)
 		if err != nil {
-			return err
+			return nil, 0, err
 		}
 
-		// Build set of existing links
-		existingLinks := make(map[string]bool)
-		for _, l := range t.Links {
-			existingLinks[l] = true
+		// The second ticket of a typed pair records the inverse relationship
+		sideRel := rel
+		if rel != "" && i == 1 {
+			sideRel = ticket.InverseRel(rel)
 		}
 
-		// Add all other IDs as links
-		newLinks := t.Links
+		// Add all other IDs as links, retyping existing links if a type is given
+		newLinks := append([]string{}, t.Links...)
+		changed := false
 		for j, otherID := range ids {
 			if i == j {
 				continue
 			}
-			if !existingLinks[otherID] {
-				newLinks = append(newLinks, otherID)
-				addedCount++
+			want := ticket.Link{ID: otherID, Rel: sideRel}
+			existing, ok := t.FindLink(otherID)
+			switch {
+			case !ok:
+				newLinks = append(newLinks, want.String())
+			case sideRel != "" && existing.Rel != sideRel:
+				for k, l := range newLinks {
+					if ticket.ParseLink(l).ID == otherID {
+						newLinks[k] = want.String()
+					}
+				}
+			default:
+				continue
 			}
+			addedCount++
+			changed = true
 		}
 
 		// Update if changed
-		if len(newLinks) != len(t.Links) {
+		if changed {
 			linksStr := formatLinksArray(newLinks)
 			_, err := store.UpdateField(id, "links", linksStr)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 		}
 	}
 
-	if addedCount == 0 {
-		fmt.Println("All links already exist")
-	} else {
-		fmt.Printf("Added %d link(s) between %d tickets\n", addedCount, len(ids))
-	}
-
-	return nil
+	return ids, addedCount, nil
 }
 
 func runUnlink(cmd *cobra.Command, args []string) error {
@@ -100,22 +181,14 @@ func runUnlink(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check if link exists
-	found := false
-	for _, l := range source.Links {
-		if l == target.ID {
-			found = true
-			break
-		}
-	}
-
-	if !found {
+	if _, found := source.FindLink(target.ID); !found {
 		return fmt.Errorf("link not found")
 	}
 
 	// Remove from source
 	var newSourceLinks []string
 	for _, l := range source.Links {
-		if l != target.ID {
+		if ticket.ParseLink(l).ID != target.ID {
 			newSourceLinks = append(newSourceLinks, l)
 		}
 	}
@@ -130,7 +203,7 @@ func runUnlink(cmd *cobra.Command, args []string) error {
 	// Remove from target
 	var newTargetLinks []string
 	for _, l := range target.Links {
-		if l != source.ID {
+		if ticket.ParseLink(l).ID != source.ID {
 			newTargetLinks = append(newTargetLinks, l)
 		}
 	}
@@ -142,7 +215,11 @@ func runUnlink(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Removed link: %s <-> %s\n", source.ID, target.ID)
+	// Report the counts that were written so an asymmetric removal shows up
+	source.Links, target.Links = newSourceLinks, newTargetLinks
+	fmt.Printf("Removed link: %s <-> %s (both sides updated)\n", source.ID, target.ID)
+	fmt.Printf("%s now has %s, %s now has %s\n",
+		source.ID, countNoun(len(source.Links), "link"), target.ID, countNoun(len(target.Links), "link"))
 	return nil
 }
 
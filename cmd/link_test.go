@@ -645,3 +645,111 @@ func TestLinkTicketExistence(t *testing.T) {
 		}
 	})
 }
+
+// TestLinkRelationshipType tests typed links with --rel
+func TestLinkRelationshipType(t *testing.T) {
+	t.Run("records relation and inverse", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		dup, _ := ctx.exec("new", "Duplicate report")
+		dup = strings.TrimSpace(dup)
+		orig, _ := ctx.exec("new", "Original report")
+		orig = strings.TrimSpace(orig)
+
+		if _, err := ctx.exec("link", dup, orig, "--rel", "duplicates"); err != nil {
+			t.Fatalf("link --rel error: %v", err)
+		}
+
+		dupTicket, _ := ctx.store().Get(dup)
+		if len(dupTicket.Links) != 1 || dupTicket.Links[0] != orig+":duplicates" {
+			t.Errorf("expected %s:duplicates, got %v", orig, dupTicket.Links)
+		}
+		origTicket, _ := ctx.store().Get(orig)
+		if len(origTicket.Links) != 1 || origTicket.Links[0] != dup+":duplicated-by" {
+			t.Errorf("expected %s:duplicated-by, got %v", dup, origTicket.Links)
+		}
+
+		output, _ := ctx.exec("show", dup)
+		if !strings.Contains(output, "- "+orig+" [open] Original report (duplicates)") {
+			t.Errorf("show should display the relationship, got:\n%s", output)
+		}
+
+		// Typed links are still found by ID
+		if _, err := ctx.exec("unlink", orig, dup); err != nil {
+			t.Fatalf("unlink error: %v", err)
+		}
+		dupTicket, _ = ctx.store().Get(dup)
+		if len(dupTicket.Links) != 0 {
+			t.Errorf("expected links removed, got %v", dupTicket.Links)
+		}
+	})
+
+	t.Run("retypes an existing plain link", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "A")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "B")
+		b = strings.TrimSpace(b)
+
+		ctx.exec("link", a, b)
+		if _, err := ctx.exec("link", a, b, "--rel", "relates-to"); err != nil {
+			t.Fatalf("link --rel error: %v", err)
+		}
+
+		ta, _ := ctx.store().Get(a)
+		if len(ta.Links) != 1 || ta.Links[0] != b+":relates-to" {
+			t.Errorf("expected single retyped link, got %v", ta.Links)
+		}
+	})
+
+	t.Run("rejects invalid usage", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "A")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "B")
+		b = strings.TrimSpace(b)
+		c, _ := ctx.exec("new", "C")
+		c = strings.TrimSpace(c)
+
+		if _, err := ctx.exec("link", a, b, c, "--rel", "duplicates"); err == nil {
+			t.Error("expected error for --rel with three tickets")
+		}
+		if _, err := ctx.exec("link", a, b, "--rel", "Not Valid"); err == nil {
+			t.Error("expected error for invalid relationship")
+		}
+	})
+}
+
+func TestUnlinkReportsCounts(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	a, _ := ctx.exec("new", "A")
+	a = strings.TrimSpace(a)
+	b, _ := ctx.exec("new", "B")
+	b = strings.TrimSpace(b)
+	c, _ := ctx.exec("new", "C")
+	c = strings.TrimSpace(c)
+	ctx.exec("link", a, b)
+	ctx.exec("link", a, c)
+
+	output, err := ctx.exec("unlink", a, b)
+	if err != nil {
+		t.Fatalf("unlink error: %v", err)
+	}
+
+	ta, _ := ctx.store().Get(a)
+	tb, _ := ctx.store().Get(b)
+	if len(ta.Links) != 1 || len(tb.Links) != 0 {
+		t.Fatalf("persisted links: %s=%v %s=%v, want 1 and 0", a, ta.Links, b, tb.Links)
+	}
+	want := a + " now has 1 link, " + b + " now has 0 links"
+	if !strings.Contains(output, want) || !strings.Contains(output, "both sides updated") {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+}
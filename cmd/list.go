@@ -4,56 +4,362 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
 
 var listCmd = &cobra.Command{
-	Use:     "ls [--status=X]",
+	Use:     "ls [--status=X[,Y]]",
 	Aliases: []string{"list"},
 	Short:   "List tickets",
-	Long:    "List all tickets, optionally filtered by status.",
-	RunE:    runList,
+	Long: `List all tickets, optionally filtered by status, sorted by priority
+(0=highest) then ID.
+
+Use --status with a comma-separated set of statuses to list tickets in any of
+them:
+  tk ls --status open,in_progress
+
+Use --porcelain for stable machine-readable output: one tab-separated row per
+ticket with the columns id, status, priority, type, assignee, title. There is
+no header, and the column order will not change across releases.
+
+Use --topo to list unclosed tickets in dependency order (dependencies before
+the tickets that depend on them), breaking ties by priority then ID. Fails if
+the dependencies contain a cycle.
+
+Use --limit N and --after <id> to page through results. Paged listings are
+ordered by priority, then creation time, then ID; --after resumes after the
+given ticket, so passing the last ID of each page walks the whole set:
+  tk ls --limit 50
+  tk ls --limit 50 --after <last-id>
+
+On a terminal, each ticket is marked as ready to work on (● or *), blocked
+by unclosed dependencies (○ or -) or, left blank, closed. ASCII marks are used with
+--ascii or when the locale is not UTF-8; --no-indicators drops them.
+
+Use --format calendar for an agenda of unclosed tickets grouped by their due
+date (set with 'tk new --due YYYY-MM-DD'): Overdue, Today, This Week (the next
+six days), Later, and finally No due date.`,
+	RunE: runList,
 }
 
-var listStatus string
+var (
+	listStatus    string
+	listCreatedBy string
+	listFailIfAny bool
+	listPorcelain bool
+	listTopo      bool
+	listAfter     string
+	listLimit     int
+	listFormat    string
+	listNoIndic   bool
+	listASCII     bool
+)
 
 func init() {
 	rootCmd.AddCommand(listCmd)
-	listCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status (open|in_progress|closed)")
+	listCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status, comma-separated (open,in_progress,closed)")
+	listCmd.Flags().StringVar(&listCreatedBy, "created-by", "", "Filter by who filed the ticket")
+	listCmd.Flags().BoolVar(&listNoIndic, "no-indicators", false, "Don't mark tickets as ready or blocked")
+	listCmd.Flags().BoolVar(&listASCII, "ascii", false, "Use ASCII readiness marks")
+	listCmd.Flags().BoolVar(&listPorcelain, "porcelain", false, "Stable tab-separated output for scripts")
+	listCmd.Flags().BoolVar(&listTopo, "topo", false, "Order unclosed tickets so dependencies come first")
+	listCmd.Flags().StringVar(&listAfter, "after", "", "Resume a paged listing after this ticket ID")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Show at most N tickets (0 for no limit)")
+	listCmd.Flags().StringVar(&listFormat, "format", "", "Output format: calendar groups unclosed tickets by due date")
+	listCmd.Flags().BoolVar(&listFailIfAny, "fail-if-any", false, "Print nothing and exit non-zero if any ticket matches")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	paged := listAfter != "" || listLimit != 0
+	if paged && listTopo {
+		return fmt.Errorf("--after and --limit cannot be combined with --topo")
+	}
+	if listLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	switch listFormat {
+	case "":
+	case "calendar":
+		if paged || listTopo || listPorcelain {
+			return fmt.Errorf("--format calendar cannot be combined with --topo, --porcelain, --after or --limit")
+		}
+	default:
+		return fmt.Errorf("unsupported --format %q (use calendar)", listFormat)
+	}
+
+	statuses, err := parseStatusSet(listStatus)
+	if err != nil {
+		return err
+	}
+
 	tickets, err := store.List()
 	if err != nil {
 		return err
 	}
+	// Readiness depends on tickets the filters may drop
+	graph := deptree.NewGraph(tickets)
 
 	// Filter by status if specified
-	if listStatus != "" {
-		status := ticket.Status(listStatus)
+	if len(statuses) > 0 {
 		var filtered []*ticket.Ticket
 		for _, t := range tickets {
-			if t.Status == status {
+			if statuses[t.Status] {
 				filtered = append(filtered, t)
 			}
 		}
 		tickets = filtered
 	}
 
-	// Sort by ID for consistent output
-	sort.Slice(tickets, func(i, j int) bool {
-		return tickets[i].ID < tickets[j].ID
-	})
+	// Filter by reporter if specified
+	if listCreatedBy != "" {
+		var filtered []*ticket.Ticket
+		for _, t := range tickets {
+			if t.CreatedBy == listCreatedBy {
+				filtered = append(filtered, t)
+			}
+		}
+		tickets = filtered
+	}
+
+	if listFailIfAny {
+		if len(tickets) > 0 {
+			return failSilently(cmd, exitError)
+		}
+		return nil
+	}
+
+	if listFormat == "calendar" {
+		printAgenda(agendaBuckets(tickets, time.Now()))
+		return nil
+	}
+
+	if listTopo {
+		tickets, err = topoOrder(tickets, listStatus == "")
+		if err != nil {
+			return err
+		}
+	} else if paged {
+		tickets, err = page(tickets, listAfter, listLimit)
+		if err != nil {
+			return err
+		}
+	} else {
+		sort.Slice(tickets, func(i, j int) bool {
+			if tickets[i].Priority != tickets[j].Priority {
+				return tickets[i].Priority < tickets[j].Priority
+			}
+			return tickets[i].ID < tickets[j].ID
+		})
+	}
+
+	if listPorcelain {
+		for _, t := range tickets {
+			fmt.Println(porcelainRow(t))
+		}
+		return nil
+	}
 
+	glyphs := readinessGlyphs(listASCII)
+	indicators := showIndicators(listNoIndic)
 	for _, t := range tickets {
 		depStr := ""
 		if len(t.Deps) > 0 {
-			depStr = " <- [" + strings.Join(t.Deps, ", ") + "]"
+			depStr = " <- [" + strings.Join(displayIDs(t.Deps), ", ") + "]"
 		}
-		fmt.Printf("%-8s [%s] - %s%s\n", t.ID, t.Status, t.Title, depStr)
+		mark := ""
+		if indicators {
+			mark = readinessIndicator(graph, t, glyphs) + " "
+		}
+		fmt.Printf("%s%-8s [%s] - %s%s\n", mark, displayID(t.ID), t.Status, t.Title, depStr)
 	}
 
 	return nil
 }
+
+// agendaBucket is a heading of the calendar agenda with its tickets
+type agendaBucket struct {
+	name    string
+	tickets []*ticket.Ticket
+}
+
+// calendarDay truncates t to its date in UTC, the zone due dates are stored
+// in, so a ticket due later today still falls on today
+func calendarDay(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// agendaBuckets groups unclosed tickets by due date relative to now:
+// Overdue, Today, This Week (the next six days), Later and No due date.
+// Empty buckets are dropped; tickets are ordered by due date, then
+// priority, then ID.
+func agendaBuckets(tickets []*ticket.Ticket, now time.Time) []agendaBucket {
+	today := calendarDay(now)
+	weekEnd := today.AddDate(0, 0, 7)
+
+	buckets := []agendaBucket{{name: "Overdue"}, {name: "Today"}, {name: "This Week"}, {name: "Later"}, {name: "No due date"}}
+	for _, t := range tickets {
+		if t.Status == ticket.StatusClosed {
+			continue
+		}
+		due := calendarDay(t.Due)
+		i := 4
+		switch {
+		case due.IsZero():
+		case due.Before(today):
+			i = 0
+		case due.Equal(today):
+			i = 1
+		case due.Before(weekEnd):
+			i = 2
+		default:
+			i = 3
+		}
+		buckets[i].tickets = append(buckets[i].tickets, t)
+	}
+
+	var result []agendaBucket
+	for _, b := range buckets {
+		if len(b.tickets) == 0 {
+			continue
+		}
+		sort.Slice(b.tickets, func(i, j int) bool {
+			di, dj := b.tickets[i].Due, b.tickets[j].Due
+			if !di.Equal(dj) {
+				return di.Before(dj)
+			}
+			if b.tickets[i].Priority != b.tickets[j].Priority {
+				return b.tickets[i].Priority < b.tickets[j].Priority
+			}
+			return b.tickets[i].ID < b.tickets[j].ID
+		})
+		result = append(result, b)
+	}
+	return result
+}
+
+// printAgenda prints the calendar agenda, one compact line per ticket
+func printAgenda(buckets []agendaBucket) {
+	for i, b := range buckets {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%d):\n", b.name, len(b.tickets))
+		for _, t := range b.tickets {
+			due := "          "
+			if !t.Due.IsZero() {
+				due = t.Due.Format(ticket.DueLayout)
+			}
+			fmt.Printf("  %s  %-8s [P%d][%s] - %s\n", due, displayID(t.ID), t.Priority, t.Status, t.Title)
+		}
+	}
+}
+
+// parseStatusSet parses a comma-separated list of statuses. An empty string
+// yields an empty set, meaning no filter.
+func parseStatusSet(value string) (map[ticket.Status]bool, error) {
+	set := make(map[ticket.Status]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		status := ticket.Status(name)
+		if !status.IsValid() {
+			return nil, fmt.Errorf("invalid status '%s'. Must be one of: %s", name, strings.Join(statusNames(), ", "))
+		}
+		set[status] = true
+	}
+	return set, nil
+}
+
+// pageLess is the total order used for paged listings: priority, then
+// creation time, then ID
+func pageLess(a, b *ticket.Ticket) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if !a.Created.Equal(b.Created) {
+		return a.Created.Before(b.Created)
+	}
+	return a.ID < b.ID
+}
+
+// page sorts tickets by pageLess and returns up to limit tickets that sort
+// after the cursor ticket. The cursor is compared by its sort key, so paging
+// still works if it no longer matches the listing's filters.
+func page(tickets []*ticket.Ticket, after string, limit int) ([]*ticket.Ticket, error) {
+	sort.Slice(tickets, func(i, j int) bool {
+		return pageLess(tickets[i], tickets[j])
+	})
+
+	if after != "" {
+		cursor, err := store.Get(after)
+		if err != nil {
+			return nil, fmt.Errorf("--after: %w", err)
+		}
+		start := sort.Search(len(tickets), func(i int) bool {
+			return pageLess(cursor, tickets[i])
+		})
+		tickets = tickets[start:]
+	}
+
+	if limit > 0 && len(tickets) > limit {
+		tickets = tickets[:limit]
+	}
+	return tickets, nil
+}
+
+// topoOrder sorts tickets so dependencies come before dependants, breaking
+// ties by priority then ID. Closed tickets are dropped when skipClosed is set.
+func topoOrder(tickets []*ticket.Ticket, skipClosed bool) ([]*ticket.Ticket, error) {
+	byID := make(map[string]*ticket.Ticket)
+	deps := make(map[string][]string)
+	var ids []string
+	for _, t := range tickets {
+		if skipClosed && t.Status == ticket.StatusClosed {
+			continue
+		}
+		byID[t.ID] = t
+		deps[t.ID] = t.Deps
+		ids = append(ids, t.ID)
+	}
+
+	order, err := deptree.TopoSort(ids, deps, func(a, b string) bool {
+		if byID[a].Priority != byID[b].Priority {
+			return byID[a].Priority < byID[b].Priority
+		}
+		return a < b
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot order tickets: %w", err)
+	}
+
+	sorted := make([]*ticket.Ticket, len(order))
+	for i, id := range order {
+		sorted[i] = byID[id]
+	}
+	return sorted, nil
+}
+
+// porcelainField replaces tabs and line breaks so a value stays in its column
+var porcelainField = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// porcelainRow formats a ticket as a tab-separated porcelain row
+func porcelainRow(t *ticket.Ticket) string {
+	return strings.Join([]string{
+		porcelainField.Replace(t.ID),
+		porcelainField.Replace(string(t.Status)),
+		fmt.Sprintf("%d", t.Priority),
+		porcelainField.Replace(string(t.Type)),
+		porcelainField.Replace(t.Assignee),
+		porcelainField.Replace(t.Title),
+	}, "\t")
+}
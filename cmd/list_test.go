@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
 )
 
 // TestListCommand tests the list command
@@ -120,6 +124,49 @@ func TestListByStatus(t *testing.T) {
 			t.Error("should not include open ticket when filtering by closed")
 		}
 	})
+
+	t.Run("filter by multiple statuses", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		idOpen, _ := ctx.exec("new", "Open Ticket")
+		idOpen = strings.TrimSpace(idOpen)
+
+		idInProgress, _ := ctx.exec("new", "InProgress Ticket")
+		idInProgress = strings.TrimSpace(idInProgress)
+		ctx.exec("start", idInProgress)
+
+		idClosed, _ := ctx.exec("new", "Closed Ticket")
+		idClosed = strings.TrimSpace(idClosed)
+		ctx.exec("close", idClosed)
+
+		output, err := ctx.exec("list", "--status", "open, in_progress")
+		if err != nil {
+			t.Fatalf("list failed: %v", err)
+		}
+
+		if !strings.Contains(output, idOpen) || !strings.Contains(output, idInProgress) {
+			t.Errorf("should include open and in_progress tickets, got:\n%s", output)
+		}
+		if strings.Contains(output, idClosed) {
+			t.Error("should not include closed ticket")
+		}
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Ticket")
+
+		_, err := ctx.exec("list", "--status", "open,bogus")
+		if err == nil {
+			t.Fatal("expected error for invalid status")
+		}
+		if !strings.Contains(err.Error(), "'bogus'") {
+			t.Errorf("error should name the bad value, got: %v", err)
+		}
+	})
 }
 
 // TestListSorting tests sorting behavior
@@ -148,6 +195,28 @@ func TestListSorting(t *testing.T) {
 		}
 	})
 
+	t.Run("sorted by priority then ID", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		idLow, _ := ctx.exec("new", "Low", "-p", "3")
+		idLow = strings.TrimSpace(idLow)
+
+		idHigh, _ := ctx.exec("new", "High", "-p", "0")
+		idHigh = strings.TrimSpace(idHigh)
+
+		output, _ := ctx.exec("ls")
+
+		posLow := strings.Index(output, idLow)
+		posHigh := strings.Index(output, idHigh)
+		if posLow == -1 || posHigh == -1 {
+			t.Fatal("both tickets should be in output")
+		}
+		if posHigh > posLow {
+			t.Errorf("P0 ticket should be listed before P3 ticket, got:\n%s", output)
+		}
+	})
+
 	t.Run("sorted by ID", func(t *testing.T) {
 		ctx, cleanup := setupTestCmd(t)
 		defer cleanup()
@@ -459,3 +528,330 @@ func TestListStatusDisplay(t *testing.T) {
 		}
 	})
 }
+
+// TestListCreatedBy tests filtering by reporter
+func TestListCreatedBy(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	alice, _ := ctx.exec("new", "Alice's ticket", "--created-by", "alice")
+	alice = strings.TrimSpace(alice)
+	bob, _ := ctx.exec("new", "Bob's ticket", "--created-by", "bob")
+	bob = strings.TrimSpace(bob)
+
+	output, err := ctx.exec("ls", "--created-by", "alice")
+	if err != nil {
+		t.Fatalf("ls --created-by error: %v", err)
+	}
+
+	if !strings.Contains(output, alice) {
+		t.Errorf("should include ticket created by alice, got: %s", output)
+	}
+	if strings.Contains(output, bob) {
+		t.Errorf("should exclude ticket created by bob, got: %s", output)
+	}
+}
+
+// TestListFailIfAny tests the --fail-if-any CI gate on ls
+func TestListFailIfAny(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Open ticket")
+	id = strings.TrimSpace(id)
+
+	output, err := ctx.exec("ls", "--status", "open", "--fail-if-any")
+	if err == nil {
+		t.Error("expected non-zero exit when an open ticket exists")
+	}
+	if strings.TrimSpace(output) != "" {
+		t.Errorf("should print nothing, got: %q", output)
+	}
+
+	listFailIfAny = false
+	output, err = ctx.exec("ls", "--status", "in_progress", "--fail-if-any")
+	if err != nil {
+		t.Errorf("expected zero exit when nothing matches, got: %v", err)
+	}
+	if strings.TrimSpace(output) != "" {
+		t.Errorf("should print nothing, got: %q", output)
+	}
+}
+
+// TestListPorcelain tests the stable tab-separated output
+func TestListPorcelain(t *testing.T) {
+	t.Run("column order and count", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Porcelain ticket", "--priority", "1", "--type", "bug", "--assignee", "alice")
+		id = strings.TrimSpace(id)
+
+		output, err := ctx.exec("ls", "--porcelain")
+		if err != nil {
+			t.Fatalf("ls --porcelain error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("expected 1 row and no header, got %d lines: %q", len(lines), output)
+		}
+
+		want := []string{id, "open", "1", "bug", "alice", "Porcelain ticket"}
+		got := strings.Split(lines[0], "\t")
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("row = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("special characters in title", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "placeholder", "--assignee", "")
+		id = strings.TrimSpace(id)
+
+		tk, _ := ctx.store().Get(id)
+		tk.Title = "Tab\there, comma, \"quotes\""
+		tk.Assignee = ""
+		ctx.store().Update(tk)
+
+		output, _ := ctx.exec("ls", "--porcelain")
+		lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("expected 1 row, got %d: %q", len(lines), output)
+		}
+
+		fields := strings.Split(lines[0], "\t")
+		if len(fields) != 6 {
+			t.Fatalf("expected 6 columns, got %d: %q", len(fields), fields)
+		}
+		if fields[4] != "" {
+			t.Errorf("empty assignee should be an empty column, got %q", fields[4])
+		}
+		if fields[5] != `Tab here, comma, "quotes"` {
+			t.Errorf("title column = %q", fields[5])
+		}
+	})
+}
+
+// TestPorcelainRowStripsNewlines tests that line breaks never split a row
+func TestPorcelainRowStripsNewlines(t *testing.T) {
+	row := porcelainRow(&ticket.Ticket{ID: "a-1", Status: "open", Type: "task", Title: "two\nlines\r\nhere"})
+	if strings.ContainsAny(row, "\r\n") {
+		t.Errorf("row should not contain line breaks: %q", row)
+	}
+	if !strings.HasSuffix(row, "\ttwo lines here") {
+		t.Errorf("unexpected row: %q", row)
+	}
+}
+
+func TestListTopo(t *testing.T) {
+	t.Run("dependencies first", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		// Diamond: top depends on left and right, which both depend on base
+		base, _ := ctx.exec("new", "Base")
+		base = strings.TrimSpace(base)
+		left, _ := ctx.exec("new", "Left")
+		left = strings.TrimSpace(left)
+		right, _ := ctx.exec("new", "Right")
+		right = strings.TrimSpace(right)
+		top, _ := ctx.exec("new", "Top")
+		top = strings.TrimSpace(top)
+		done, _ := ctx.exec("new", "Done")
+		done = strings.TrimSpace(done)
+		ctx.exec("dep", top, left)
+		ctx.exec("dep", top, right)
+		ctx.exec("dep", left, base)
+		ctx.exec("dep", right, base)
+		ctx.exec("dep", base, done)
+		ctx.exec("close", done)
+
+		output, err := ctx.exec("ls", "--topo")
+		if err != nil {
+			t.Fatalf("ls --topo error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("expected 4 unclosed tickets, got:\n%s", output)
+		}
+		if !strings.HasPrefix(lines[0], base) || !strings.HasPrefix(lines[3], top) {
+			t.Errorf("expected %s first and %s last, got:\n%s", base, top, output)
+		}
+	})
+
+	t.Run("cycle is reported", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "A")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "B")
+		b = strings.TrimSpace(b)
+		ctx.exec("dep", a, b)
+		ctx.exec("dep", b, a)
+
+		output, err := ctx.exec("ls", "--topo")
+		if err == nil {
+			t.Fatal("expected error for dependency cycle")
+		}
+		if !strings.Contains(output, "dependency cycle among") {
+			t.Errorf("expected cycle message, got: %s", output)
+		}
+	})
+}
+
+func TestListShortIDs(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, id := range []string{"tk-a1b2", "tk-c3b2", "tk-x9z8"} {
+		if err := ctx.store().Create(&ticket.Ticket{ID: id, Status: "open", Type: "task", Title: "Short ID fixture"}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	ctx.exec("dep", "tk-x9z8", "tk-a1b2")
+
+	output, err := ctx.exec("--short-ids", "ls")
+	if err != nil {
+		t.Fatalf("ls --short-ids error: %v", err)
+	}
+
+	for _, want := range []string{"1b2 ", "3b2 ", "8 ", "<- [1b2]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "tk-") {
+		t.Errorf("expected no full IDs in output, got:\n%s", output)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// Mixed priorities and identical creation times exercise every sort key
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := make(map[string]bool)
+	for i := 0; i < 7; i++ {
+		id := fmt.Sprintf("pg-%d", i)
+		tk := &ticket.Ticket{ID: id, Status: "open", Type: "task", Title: "Page", Priority: i % 3, Created: created.Add(time.Duration(i%2) * time.Hour)}
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+		want[id] = true
+	}
+
+	var seen []string
+	after := ""
+	for pages := 0; pages < 10; pages++ {
+		args := []string{"ls", "--porcelain", "--limit", "3"}
+		if after != "" {
+			args = append(args, "--after", after)
+		}
+		output, err := ctx.exec(args...)
+		if err != nil {
+			t.Fatalf("ls %v error: %v", args, err)
+		}
+		output = strings.TrimSpace(output)
+		if output == "" {
+			break
+		}
+		lines := strings.Split(output, "\n")
+		if len(lines) > 3 {
+			t.Fatalf("page has %d rows, want at most 3", len(lines))
+		}
+		for _, line := range lines {
+			seen = append(seen, strings.Split(line, "\t")[0])
+		}
+		after = seen[len(seen)-1]
+	}
+
+	if len(seen) != len(want) {
+		t.Fatalf("paged through %v, want each of %d tickets once", seen, len(want))
+	}
+	for i, id := range seen {
+		if !want[id] {
+			t.Errorf("ticket %s repeated or unexpected", id)
+		}
+		delete(want, id)
+		if i > 0 {
+			prev, _ := ctx.store().Get(seen[i-1])
+			cur, _ := ctx.store().Get(id)
+			if !pageLess(prev, cur) {
+				t.Errorf("%s listed before %s out of order", prev.ID, cur.ID)
+			}
+		}
+	}
+
+	if _, err := ctx.exec("ls", "--after", "no-such-ticket"); err == nil {
+		t.Error("expected error for unknown cursor")
+	}
+}
+
+func TestAgendaBuckets(t *testing.T) {
+	now := time.Date(2026, 5, 13, 9, 0, 0, 0, time.UTC)
+	due := func(id, date string, status ticket.Status) *ticket.Ticket {
+		tk := &ticket.Ticket{ID: id, Status: status}
+		if date != "" {
+			tk.Due, _ = ticket.ParseDue(date)
+		}
+		return tk
+	}
+	tickets := []*ticket.Ticket{
+		due("later", "2026-05-20", ticket.StatusOpen),
+		due("week-end", "2026-05-19", ticket.StatusOpen),
+		due("tomorrow", "2026-05-14", ticket.StatusInProgress),
+		due("today", "2026-05-13", ticket.StatusOpen),
+		due("today-earlier", "2026-05-13T01:00:00Z", ticket.StatusOpen),
+		due("today-later", "2026-05-13T15:00:00Z", ticket.StatusOpen),
+		due("overdue", "2026-05-12", ticket.StatusOpen),
+		due("done", "2026-05-01", ticket.StatusClosed),
+		due("undated", "", ticket.StatusOpen),
+	}
+
+	var got []string
+	for _, b := range agendaBuckets(tickets, now) {
+		var ids []string
+		for _, tk := range b.tickets {
+			ids = append(ids, tk.ID)
+		}
+		got = append(got, b.name+": "+strings.Join(ids, ","))
+	}
+	want := []string{
+		"Overdue: overdue",
+		"Today: today,today-earlier,today-later",
+		"This Week: tomorrow,week-end",
+		"Later: later",
+		"No due date: undated",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("agendaBuckets() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestListCalendarFormat(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	ctx.exec("new", "Undated")
+	ctx.exec("new", "Past due", "--due", "2001-01-01")
+
+	output, err := ctx.exec("ls", "--format", "calendar")
+	if err != nil {
+		t.Fatalf("ls --format calendar error: %v", err)
+	}
+	overdue := strings.Index(output, "Overdue (1):")
+	undated := strings.Index(output, "No due date (1):")
+	if overdue < 0 || undated < overdue || !strings.Contains(output, "2001-01-01") {
+		t.Errorf("unexpected agenda:\n%s", output)
+	}
+
+	if _, err := ctx.exec("new", "Bad", "--due", "tomorrow"); err == nil {
+		t.Error("new --due with an invalid date should fail")
+	}
+}
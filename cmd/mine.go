@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var mineCmd = &cobra.Command{
+	Use:   "mine",
+	Short: "List open tickets assigned to you",
+	Long: `List open and in_progress tickets assigned to the current user: $TK_USER,
+else git user.name, else $USER, the same identity new assigns tickets to.
+Sorted by priority (ascending, 0=highest), then by ID, as for blocked.`,
+	Args: cobra.NoArgs,
+	RunE: runMine,
+}
+
+func init() {
+	rootCmd.AddCommand(mineCmd)
+}
+
+func runMine(cmd *cobra.Command, args []string) error {
+	user := currentUser()
+	if user == "" {
+		return fmt.Errorf("cannot determine the current user: %s", noUserHelp)
+	}
+
+	tickets, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	var mine []*ticket.Ticket
+	for _, t := range tickets {
+		if t.Status != ticket.StatusOpen && t.Status != ticket.StatusInProgress {
+			continue
+		}
+		if t.Assignee == user {
+			mine = append(mine, t)
+		}
+	}
+
+	sort.Slice(mine, func(i, j int) bool {
+		return priorityLess(mine[i], mine[j])
+	})
+
+	for _, t := range mine {
+		fmt.Printf("%-8s [P%d][%s] - %s\n", displayID(t.ID), t.Priority, t.Status, t.Title)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestMineCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, tk := range []*ticket.Ticket{
+		{ID: "m-2", Status: ticket.StatusOpen, Priority: 2, Assignee: "alice"},
+		{ID: "m-1", Status: ticket.StatusInProgress, Priority: 2, Assignee: "alice"},
+		{ID: "m-0", Status: ticket.StatusOpen, Priority: 0, Assignee: "alice"},
+		{ID: "m-closed", Status: ticket.StatusClosed, Priority: 0, Assignee: "alice"},
+		{ID: "m-bob", Status: ticket.StatusOpen, Priority: 0, Assignee: "bob"},
+		{ID: "m-none", Status: ticket.StatusOpen, Priority: 0},
+	} {
+		tk.Type, tk.Title = ticket.TypeTask, "Ticket "+tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	t.Run("TK_USER", func(t *testing.T) {
+		t.Setenv("TK_USER", "alice")
+		t.Setenv("USER", "bob")
+
+		output, err := ctx.exec("mine")
+		if err != nil {
+			t.Fatalf("mine error: %v", err)
+		}
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			ids = append(ids, strings.Fields(line)[0])
+		}
+		if got := strings.Join(ids, " "); got != "m-0 m-1 m-2" {
+			t.Errorf("mine = %s, want m-0 m-1 m-2 (priority, then ID)", got)
+		}
+	})
+
+	// Without TK_USER, git's user.name comes before USER
+	gitUser := ""
+	orig := gitUserName
+	gitUserName = func() string { return gitUser }
+	defer func() { gitUserName = orig }()
+
+	t.Run("git user.name", func(t *testing.T) {
+		t.Setenv("TK_USER", "")
+		t.Setenv("USER", "alice")
+		gitUser = "bob"
+		defer func() { gitUser = "" }()
+
+		output, err := ctx.exec("mine")
+		if err != nil {
+			t.Fatalf("mine error: %v", err)
+		}
+		if strings.TrimSpace(output) != "m-bob    [P0][open] - Ticket m-bob" {
+			t.Errorf("expected only bob's ticket, got: %q", output)
+		}
+	})
+
+	t.Run("lists tickets new assigned to you", func(t *testing.T) {
+		t.Setenv("TK_USER", "")
+		t.Setenv("USER", "someone-else")
+		gitUser = "carol"
+		defer func() { gitUser = "" }()
+
+		id, _ := ctx.exec("new", "Filed for myself")
+		id = strings.TrimSpace(id)
+		output, err := ctx.exec("mine")
+		if err != nil {
+			t.Fatalf("mine error: %v", err)
+		}
+		if !strings.Contains(output, id) {
+			t.Errorf("mine should list %s, got: %q", id, output)
+		}
+	})
+
+	t.Run("falls back to USER", func(t *testing.T) {
+		t.Setenv("TK_USER", "")
+		t.Setenv("USER", "bob")
+
+		output, err := ctx.exec("mine")
+		if err != nil {
+			t.Fatalf("mine error: %v", err)
+		}
+		if strings.TrimSpace(output) != "m-bob    [P0][open] - Ticket m-bob" {
+			t.Errorf("expected only bob's ticket, got: %q", output)
+		}
+	})
+
+	t.Run("no user", func(t *testing.T) {
+		t.Setenv("TK_USER", "")
+		t.Setenv("USER", "")
+
+		if _, err := ctx.exec("mine"); err == nil {
+			t.Error("expected error when no user is set")
+		}
+	})
+}
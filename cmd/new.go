@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/lo5/tk/internal/config"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -15,7 +17,27 @@ var newCmd = &cobra.Command{
 	Use:   "new [title]",
 	Short: "Create a new ticket",
 	Long: `Create a new ticket with the specified title and options.
-Prints the generated ticket ID on success.`,
+Prints the generated ticket ID on success.
+
+Use --edit (-e) to draft the title and body in $EDITOR before saving.
+The ticket is still created if the editor is closed without changes.
+
+Use --batch to create one ticket per line of stdin (blank lines and lines
+starting with # are skipped); the other flags apply to every ticket. Add
+--assignees a,b,c to assign the created tickets round-robin.
+
+If an unclosed ticket already has the same title (ignoring case, punctuation
+and spacing), new lists it and asks for confirmation. Without a terminal, or
+with --batch, it refuses instead; pass --allow-duplicate to create anyway.
+
+Without --priority, the priority comes from the first entry of the config's
+priority_keywords whose keyword appears in the title (ignoring case), else
+from default_priority:
+  priority_keywords:
+    - keyword: critical
+      priority: 0
+    - keyword: urgent
+      priority: 1`,
 	RunE: runNew,
 }
 
@@ -28,6 +50,12 @@ var (
 	newAssignee    string
 	newExternalRef string
 	newParent      string
+	newCreatedBy   string
+	newEdit        bool
+	newBatch       bool
+	newAssignees   []string
+	newAllowDup    bool
+	newDue         string
 )
 
 func init() {
@@ -36,26 +64,44 @@ func init() {
 	newCmd.Flags().StringVarP(&newDescription, "description", "d", "", "Description text")
 	newCmd.Flags().StringVar(&newDesign, "design", "", "Design notes")
 	newCmd.Flags().StringVar(&newAcceptance, "acceptance", "", "Acceptance criteria")
-	newCmd.Flags().IntVarP(&newPriority, "priority", "p", 2, "Priority 0-4, 0=highest")
+	newCmd.Flags().IntVarP(&newPriority, "priority", "p", 2, "Priority 0-4, 0=highest (default: config default_priority, else 2)")
 	newCmd.Flags().StringVarP(&newType, "type", "t", "task", "Type (bug|feature|task|epic|chore)")
 	newCmd.Flags().StringVarP(&newAssignee, "assignee", "a", "", "Assignee")
 	newCmd.Flags().StringVar(&newExternalRef, "external-ref", "", "External reference (e.g., gh-123)")
 	newCmd.Flags().StringVar(&newParent, "parent", "", "Parent ticket ID")
+	newCmd.Flags().StringVar(&newDue, "due", "", "Due date (YYYY-MM-DD)")
+	newCmd.Flags().StringVar(&newCreatedBy, "created-by", "", "Who filed the ticket (default: current user)")
+	newCmd.Flags().BoolVarP(&newEdit, "edit", "e", false, "Draft the title and body in $EDITOR before saving")
+	newCmd.Flags().BoolVar(&newBatch, "batch", false, "Create one ticket per title read from stdin")
+	newCmd.Flags().BoolVar(&newAllowDup, "allow-duplicate", false, "Create even if an unclosed ticket has the same title")
+	newCmd.Flags().StringSliceVar(&newAssignees, "assignees", nil, "With --batch, assign tickets round-robin across these users")
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
+	if newBatch {
+		if len(args) > 0 {
+			return fmt.Errorf("--batch reads titles from stdin and takes no arguments")
+		}
+		if newEdit {
+			return fmt.Errorf("cannot combine --batch with --edit")
+		}
+	} else if len(newAssignees) > 0 {
+		return fmt.Errorf("--assignees requires --batch")
+	}
+
 	title := "Untitled"
 	if len(args) > 0 {
 		title = strings.Join(args, " ")
 	}
 
-	// Default assignee from git config
+	// Default assignee and reporter to the current user
 	assignee := newAssignee
 	if assignee == "" {
-		out, err := exec.Command("git", "config", "user.name").Output()
-		if err == nil {
-			assignee = strings.TrimSpace(string(out))
-		}
+		assignee = currentUser()
+	}
+	createdBy := newCreatedBy
+	if createdBy == "" {
+		createdBy = currentUser()
 	}
 
 	// Validate type
@@ -64,30 +110,29 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid type '%s'. Must be one of: bug, feature, task, epic, chore", newType)
 	}
 
-	// Validate priority
-	if newPriority < 0 || newPriority > 4 {
-		return fmt.Errorf("invalid priority '%d'. Must be 0-4", newPriority)
+	var due time.Time
+	if newDue != "" {
+		d, err := ticket.ParseDue(newDue)
+		if err != nil {
+			return err
+		}
+		due = d
 	}
 
-	// Generate ID with collision detection
-	cwd, err := os.Getwd()
+	cfg, err := config.Load(store.Dir())
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return err
 	}
 
-	var id string
-	maxRetries := 10
-	for i := 0; i < maxRetries; i++ {
-		id = ticket.GenerateID(cwd)
-		_, err := store.Get(id)
-		if err != nil {
-			// ID doesn't exist, we can use it
-			break
-		}
-		// ID exists, retry (unless it's the last attempt)
-		if i == maxRetries-1 {
-			return fmt.Errorf("failed to generate unique ticket ID after %d attempts", maxRetries)
-		}
+	// Fall back to the configured default priority
+	priority := newPriority
+	if !cmd.Flags().Changed("priority") && cfg.DefaultPriority != nil {
+		priority = *cfg.DefaultPriority
+	}
+
+	// Validate priority
+	if priority < 0 || priority > 4 {
+		return fmt.Errorf("invalid priority '%d'. Must be 0-4", priority)
 	}
 
 	// Build body content
@@ -107,25 +152,197 @@ func runNew(cmd *cobra.Command, args []string) error {
 		body = strings.Join(bodyParts, "\n\n")
 	}
 
+	if newEdit {
+		title, body, err = draftInEditor(title, body)
+		if err != nil {
+			return err
+		}
+	}
+	if !newBatch {
+		if err := cfg.CheckTitle(title); err != nil {
+			return err
+		}
+		if err := confirmNotDuplicate(cmd, []string{title}); err != nil {
+			return err
+		}
+	}
+
 	t := &ticket.Ticket{
-		ID:          id,
-		Status:      ticket.StatusOpen,
-		Deps:        []string{},
-		Links:       []string{},
-		Created:     time.Now().UTC(),
 		Type:        issueType,
-		Priority:    newPriority,
+		Priority:    titlePriority(cmd, cfg, title, priority),
 		Assignee:    assignee,
 		ExternalRef: newExternalRef,
 		Parent:      newParent,
+		CreatedBy:   createdBy,
+		Due:         due,
 		Title:       title,
 		Body:        body,
 	}
 
-	if err := store.Create(t); err != nil {
-		return fmt.Errorf("creating ticket: %w", err)
+	if newBatch {
+		return createBatch(cmd, t, cfg)
+	}
+
+	id, err := createWithNewID(t)
+	if err != nil {
+		return err
 	}
 
 	fmt.Println(id)
 	return nil
 }
+
+// createWithNewID assigns a fresh unique ID to the ticket and saves it
+func createWithNewID(t *ticket.Ticket) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getting current directory: %w", err)
+	}
+
+	id, err := ticket.CreateNew(store, t, cwd)
+	if err != nil {
+		return "", fmt.Errorf("creating ticket: %w", err)
+	}
+	return id, nil
+}
+
+// createBatch creates a copy of the template ticket for every title read from
+// stdin, assigning them round-robin when --assignees is given. Titles are all
+// checked against the configured length limit before any ticket is created.
+func createBatch(cmd *cobra.Command, template *ticket.Ticket, cfg *config.Config) error {
+	var titles []string
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		titles = append(titles, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+	if len(titles) == 0 {
+		return fmt.Errorf("no titles provided on stdin")
+	}
+	for _, title := range titles {
+		if err := cfg.CheckTitle(title); err != nil {
+			return fmt.Errorf("%q: %w", title, err)
+		}
+	}
+	if err := confirmNotDuplicate(cmd, titles); err != nil {
+		return err
+	}
+
+	for i, title := range titles {
+		t := *template
+		t.Title = title
+		t.Priority = titlePriority(cmd, cfg, title, template.Priority)
+		if len(newAssignees) > 0 {
+			t.Assignee = newAssignees[i%len(newAssignees)]
+		}
+
+		id, err := createWithNewID(&t)
+		if err != nil {
+			return err
+		}
+
+		if len(newAssignees) > 0 {
+			fmt.Printf("%s -> %s\n", id, t.Assignee)
+		} else {
+			fmt.Println(id)
+		}
+	}
+	return nil
+}
+
+// titlePriority returns the priority the config's priority_keywords infer
+// from the title, or fallback when --priority was passed or nothing matches
+func titlePriority(cmd *cobra.Command, cfg *config.Config, title string, fallback int) int {
+	if cmd.Flags().Changed("priority") {
+		return fallback
+	}
+	if p, ok := cfg.InferPriority(title); ok {
+		return p
+	}
+	return fallback
+}
+
+// confirmNotDuplicate warns about unclosed tickets whose normalized title
+// matches one of titles. On a terminal the user is asked to confirm; otherwise
+// creation is refused unless --allow-duplicate was passed.
+func confirmNotDuplicate(cmd *cobra.Command, titles []string) error {
+	if newAllowDup {
+		return nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, title := range titles {
+		wanted[ticket.NormalizeTitle(title)] = true
+	}
+
+	tickets, err := store.List()
+	if err != nil {
+		return err
+	}
+	var candidates []*ticket.Ticket
+	for _, t := range tickets {
+		if t.Status != ticket.StatusClosed && wanted[ticket.NormalizeTitle(t.Title)] {
+			candidates = append(candidates, t)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ID < candidates[j].ID
+	})
+	stderr := cmd.ErrOrStderr()
+	fmt.Fprintf(stderr, "Warning: %d unclosed ticket(s) with the same title:\n%s\n", len(candidates), formatBlockingTickets(candidates))
+
+	if newBatch || !isTerminal() {
+		return fmt.Errorf("possible duplicate; pass --allow-duplicate to create anyway")
+	}
+
+	fmt.Fprint(stderr, "Create anyway? [y/N] ")
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return fmt.Errorf("not created")
+	}
+	return nil
+}
+
+// draftInEditor opens a temp file pre-filled with the title heading and body
+// in $EDITOR and returns the edited values. An emptied title keeps the original.
+func draftInEditor(title, body string) (string, string, error) {
+	f, err := os.CreateTemp("", "tk-new-*.md")
+	if err != nil {
+		return "", "", fmt.Errorf("creating draft file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	_, err = fmt.Fprintf(f, "# %s\n\n%s\n", title, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("writing draft file: %w", err)
+	}
+
+	if err := launchEditor(path); err != nil {
+		return "", "", fmt.Errorf("running editor: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("reading draft file: %w", err)
+	}
+
+	editedTitle, editedBody := ticket.SplitTitle(string(content))
+	if editedTitle == "" {
+		editedTitle = title
+	}
+	return editedTitle, editedBody, nil
+}
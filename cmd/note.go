@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
 
@@ -14,7 +15,11 @@ var noteCmd = &cobra.Command{
 	Use:   "note <id> [note text]",
 	Short: "Append timestamped note to ticket",
 	Long: `Append a timestamped note to a ticket.
-Note text can be provided as arguments or piped via stdin.`,
+Note text can be provided as arguments or piped via stdin.
+
+Notes accumulate in the ticket's ## Notes section, each under its UTC time
+in bold, and are available to tk query as a notes array of {time, text}:
+  tk query '.notes | length > 0'`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runNote,
 }
@@ -45,24 +50,18 @@ func runNote(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no note provided")
 	}
 
-	// Check if Notes section exists
-	contains, id, err := store.FileContains(ticketID, "## Notes")
+	id, content, err := store.ReadRaw(ticketID)
 	if err != nil {
 		return err
 	}
 
-	// Build content to append
-	timestamp := time.Now().UTC().Format(time.RFC3339)
-	var content strings.Builder
-
-	if !contains {
-		content.WriteString("\n## Notes\n")
-	}
-	content.WriteString(fmt.Sprintf("\n**%s**\n\n%s\n", timestamp, note))
-
-	// Append to file
-	_, err = store.AppendToFile(id, content.String())
-	if err != nil {
+	// Add the note to the Notes section, separated from earlier notes by a
+	// blank line
+	entry := "\n" + ticket.FormatNote(time.Now(), note)
+	content = ticket.UpdateBody(content, func(body string) string {
+		return ticket.AppendToSection(body, ticket.NotesSection, entry)
+	})
+	if err := store.WriteRaw(id, content); err != nil {
 		return err
 	}
 
@@ -463,3 +463,37 @@ func TestAddNoteFromStdin(t *testing.T) {
 		}
 	})
 }
+
+// TestNotesVisibleToQuery tests that notes added by the command are parsed
+// back as structured entries
+func TestNotesVisibleToQuery(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Queried notes", "-d", "Original description")
+	id = strings.TrimSpace(id)
+
+	if _, err := ctx.exec("note", id, "First note"); err != nil {
+		t.Fatalf("note error: %v", err)
+	}
+	if _, err := ctx.exec("note", id, "Second note"); err != nil {
+		t.Fatalf("note error: %v", err)
+	}
+
+	output, err := ctx.exec("query", "-r", `.notes[] | .text`)
+	if err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if output != "First note\nSecond note\n" {
+		t.Errorf("query .notes texts = %q", output)
+	}
+
+	tk, _ := ctx.store().Get(id)
+	notes := tk.Notes()
+	if len(notes) != 2 || notes[0].Time.IsZero() || notes[1].Text != "Second note" {
+		t.Errorf("Notes() = %+v", notes)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(tk.Body), "Original description") {
+		t.Errorf("original body not preserved:\n%s", tk.Body)
+	}
+}
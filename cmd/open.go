@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/lo5/tk/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <id>",
+	Short: "Open a ticket's external reference in a browser",
+	Long: `Open the URL for a ticket's external-ref in the default browser.
+
+The URL is built from the external_url template in .tickets/config,
+where {ref} is replaced by the ticket's external-ref:
+
+  external_url: https://github.com/org/repo/issues/{ref}`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpen,
+}
+
+// urlOpener opens a URL outside of tk
+type urlOpener interface {
+	Open(url string) error
+}
+
+// browserOpener opens URLs with the operating system's default handler
+type browserOpener struct{}
+
+func (browserOpener) Open(u string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", u)
+	case "windows":
+		c = exec.Command("cmd", "/c", "start", "", u)
+	default:
+		c = exec.Command("xdg-open", u)
+	}
+	return c.Start()
+}
+
+// opener is replaced in tests to avoid launching a browser
+var opener urlOpener = browserOpener{}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	t, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(store.Dir())
+	if err != nil {
+		return err
+	}
+
+	u, err := externalURL(cfg.ExternalURL, t.ExternalRef)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %w", t.ID, err)
+	}
+
+	if err := opener.Open(u); err != nil {
+		return fmt.Errorf("opening %s: %w", u, err)
+	}
+
+	fmt.Printf("Opened %s\n", u)
+	return nil
+}
+
+// externalURL builds the URL for an external reference from a template
+func externalURL(template, ref string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("ticket has no external-ref")
+	}
+	if template == "" {
+		return "", fmt.Errorf("no external_url configured in %s (e.g. external_url: https://github.com/org/repo/issues/{ref})", config.FileName)
+	}
+	if !strings.Contains(template, "{ref}") {
+		return "", fmt.Errorf("external_url %q must contain {ref}", template)
+	}
+	return strings.ReplaceAll(template, "{ref}", url.PathEscape(ref)), nil
+}
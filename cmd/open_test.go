@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubOpener records URLs instead of launching a browser
+type stubOpener struct {
+	urls []string
+}
+
+func (s *stubOpener) Open(u string) error {
+	s.urls = append(s.urls, u)
+	return nil
+}
+
+// withStubOpener replaces the URL opener for the duration of a test
+func withStubOpener(t *testing.T) *stubOpener {
+	t.Helper()
+	stub := &stubOpener{}
+	old := opener
+	opener = stub
+	t.Cleanup(func() { opener = old })
+	return stub
+}
+
+// TestOpenCommand tests opening external references
+func TestOpenCommand(t *testing.T) {
+	t.Run("constructs URL from template and ref", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		stub := withStubOpener(t)
+
+		id, _ := ctx.exec("new", "Linked issue", "--external-ref", "123")
+		id = strings.TrimSpace(id)
+
+		cfg := "external_url: https://github.com/lo5/tk/issues/{ref}\n"
+		if err := os.WriteFile(filepath.Join(ctx.ticketsDir, "config"), []byte(cfg), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := ctx.exec("open", id)
+		if err != nil {
+			t.Fatalf("open error: %v", err)
+		}
+
+		want := "https://github.com/lo5/tk/issues/123"
+		if len(stub.urls) != 1 || stub.urls[0] != want {
+			t.Errorf("opened %v, want [%s]", stub.urls, want)
+		}
+		if !strings.Contains(output, want) {
+			t.Errorf("output should mention URL, got: %s", output)
+		}
+	})
+
+	t.Run("errors without external-ref", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		stub := withStubOpener(t)
+
+		id, _ := ctx.exec("new", "No ref")
+		id = strings.TrimSpace(id)
+
+		_, err := ctx.exec("open", id)
+		if err == nil || !strings.Contains(err.Error(), "no external-ref") {
+			t.Errorf("expected missing external-ref error, got: %v", err)
+		}
+		if len(stub.urls) != 0 {
+			t.Error("should not open anything")
+		}
+	})
+
+	t.Run("errors without template", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		withStubOpener(t)
+
+		id, _ := ctx.exec("new", "Has ref", "--external-ref", "gh-1")
+		id = strings.TrimSpace(id)
+
+		_, err := ctx.exec("open", id)
+		if err == nil || !strings.Contains(err.Error(), "no external_url configured") {
+			t.Errorf("expected missing template error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lo5/tk/internal/deptree"
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "List unclosed tickets in an order that respects dependencies",
+	Long: `Print an execution plan for the whole backlog: every open or in-progress
+ticket, numbered, after all of the unclosed tickets it depends on. Among
+tickets that could come next, the higher priority (lower number) goes
+first, then the lower ID. Dependencies on closed or missing tickets are
+already satisfied.
+
+If the unclosed tickets contain a dependency cycle there is no such order;
+the cycles are printed as by tk dep check and the exit status is non-zero.`,
+	Args: cobra.NoArgs,
+	RunE: runPlan,
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	graph, err := loadGraph()
+	if err != nil {
+		return err
+	}
+
+	plan, err := topoOrder(graph.Tickets(), true)
+	if err != nil {
+		var cycleErr *deptree.CycleError
+		if !errors.As(err, &cycleErr) {
+			return err
+		}
+		var unclosed []*ticket.Ticket
+		for _, t := range graph.Tickets() {
+			if t.Status != ticket.StatusClosed {
+				unclosed = append(unclosed, t)
+			}
+		}
+		return reportCycles(cmd, deptree.FindCycles(unclosed))
+	}
+
+	for i, t := range plan {
+		fmt.Printf("%3d. %-8s [P%d][%s] - %s\n", i+1, displayID(t.ID), t.Priority, t.Status, t.Title)
+	}
+	return nil
+}
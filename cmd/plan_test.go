@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestPlanCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// deploy needs build and docs; build needs design; closed tickets and
+	// missing deps are satisfied
+	for _, tk := range []*ticket.Ticket{
+		{ID: "p-deploy", Priority: 0, Deps: []string{"p-build", "p-docs"}},
+		{ID: "p-build", Priority: 1, Deps: []string{"p-design", "p-gone"}},
+		{ID: "p-design", Priority: 3, Deps: []string{"p-done"}},
+		{ID: "p-docs", Priority: 2},
+		{ID: "p-urgent", Priority: 0, Status: ticket.StatusInProgress},
+		{ID: "p-done", Priority: 0, Status: ticket.StatusClosed},
+	} {
+		if tk.Status == "" {
+			tk.Status = ticket.StatusOpen
+		}
+		tk.Type, tk.Title = ticket.TypeTask, "Plan "+tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	output, err := ctx.exec("plan")
+	if err != nil {
+		t.Fatalf("plan error: %v", err)
+	}
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			t.Fatalf("unexpected line %q", line)
+		}
+		order = append(order, fields[1])
+	}
+	want := "p-urgent,p-docs,p-design,p-build,p-deploy"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("plan order = %s, want %s\n%s", got, want, output)
+	}
+	if !strings.HasPrefix(output, "  1. p-urgent [P0][in_progress] - Plan p-urgent\n") {
+		t.Errorf("unexpected line format:\n%s", output)
+	}
+
+	// A cycle among unclosed tickets is reported like dep check
+	if _, err := ctx.store().UpdateField("p-design", "deps", "[p-deploy]"); err != nil {
+		t.Fatalf("update deps: %v", err)
+	}
+	output, err = ctx.exec("plan")
+	if err == nil {
+		t.Error("expected non-zero exit when the plan has a cycle")
+	}
+	for _, want := range []string{"p-build -> p-design -> p-deploy -> p-build", "1 dependency cycle(s) found"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q, got:\n%s", want, output)
+		}
+	}
+}
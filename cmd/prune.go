@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -41,23 +42,20 @@ type danglingRefs struct {
 
 func runPrune(cmd *cobra.Command, args []string) error {
 	// 1. Load all tickets
-	allTickets, err := store.List()
+	graph, err := loadGraph()
 	if err != nil {
 		return err
 	}
 
-	if len(allTickets) == 0 {
+	if len(graph.Tickets()) == 0 {
 		fmt.Println("No tickets found.")
 		return nil
 	}
 
-	// 2. Build valid ID set
-	validIDs := buildValidIDSet(allTickets)
+	// 2. Find dangling references
+	dangling := findDanglingRefs(graph)
 
-	// 3. Find dangling references
-	dangling := findDanglingRefs(allTickets, validIDs)
-
-	// 4. Display or fix
+	// 3. Display or fix
 	if len(dangling) == 0 {
 		fmt.Println("No dangling references found.")
 		return nil
@@ -68,41 +66,38 @@ func runPrune(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// 5. Fix and report
+	// 4. Fix and report
 	return fixDanglingRefs(cmd, dangling)
 }
 
-func buildValidIDSet(tickets []*ticket.Ticket) map[string]bool {
-	validIDs := make(map[string]bool)
-	for _, t := range tickets {
-		validIDs[t.ID] = true
-	}
-	return validIDs
-}
-
-func findDanglingRefs(tickets []*ticket.Ticket, validIDs map[string]bool) []danglingRefs {
+func findDanglingRefs(graph *deptree.DependencyGraph) []danglingRefs {
 	var result []danglingRefs
 
-	for _, t := range tickets {
+	valid := func(id string) bool {
+		_, ok := graph.Ticket(id)
+		return ok
+	}
+
+	for _, t := range graph.Tickets() {
 		var dr danglingRefs
 		dr.ticket = t
 
 		// Check deps
 		for _, depID := range t.Deps {
-			if !validIDs[depID] {
+			if !valid(depID) {
 				dr.deps = append(dr.deps, depID)
 			}
 		}
 
 		// Check links
-		for _, linkID := range t.Links {
-			if !validIDs[linkID] {
-				dr.links = append(dr.links, linkID)
+		for _, l := range t.Links {
+			if !valid(ticket.ParseLink(l).ID) {
+				dr.links = append(dr.links, l)
 			}
 		}
 
 		// Check parent
-		if t.Parent != "" && !validIDs[t.Parent] {
+		if t.Parent != "" && !valid(t.Parent) {
 			dr.parent = t.Parent
 		}
 
@@ -1,43 +1,439 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/lo5/tk/internal/config"
 	"github.com/lo5/tk/internal/query"
+	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
 
 var queryCmd = &cobra.Command{
 	Use:   "query [jq-filter]",
 	Short: "Output tickets as JSON",
-	Long: `Output tickets as JSON, one object per line.
-Optionally apply a jq-style filter.
+	Long: `Output tickets as JSON, one object per line (--format ndjson, the
+default). Optionally apply a jq-style filter.
+
+Use --format json, or --slurp, to print a single JSON array instead. For now a bare query
+still prints one object per line; pass --format ndjson to depend on that.
 
 Examples:
   tk query                          # All tickets as JSON
   tk query '.priority == "0"'       # High priority tickets
-  tk query '.status == "open"'      # Open tickets`,
+  tk query '.priority_num <= 1'     # Priorities 0 and 1, compared as numbers
+  tk query '.status == "open"'      # Open tickets
+  tk query --title-match '^WIP'     # Titles matching a regular expression
+  tk query --depends-on abc         # Tickets that depend on abc
+  tk query --older-than 14d '.status == "open"'  # Open for over two weeks
+  tk query --sort priority '.status != "closed"' # Triage view
+
+Use --sort <field> to order the (filtered) tickets by id, priority, created,
+status, type, title or assignee before they are printed in any format;
+prefix the field with - to sort descending, e.g. --sort -created. Ties are
+broken by ID. Without --sort tickets are printed in store order.
+
+Relationship prefilters narrow the tickets before any jq filter runs and
+accept partial IDs; when several are given a ticket must satisfy all of them:
+  --linked-to <id>    tickets linked to the ticket
+  --depends-on <id>   tickets with the ticket as a dependency
+  --child-of <id>     tickets whose parent is the ticket
+
+Saved views name reusable filters. --save-view stores the given filter under
+a name instead of running it; --view applies a saved view and may be repeated
+to AND several views together, or combined with --any-view to OR them. Views
+are applied before the filter argument:
+  tk query --save-view mine '.assignee == "alice"'
+  tk query --save-view urgent '.priority_num <= 1'
+  tk query --view mine --view urgent             # Both
+  tk query --view mine --view urgent --any-view  # Either
+
+Use --fail-if-any to turn a query into a CI gate: nothing is printed and the
+exit status is non-zero when at least one ticket matches.
+
+Use --assert <name> to run named policy checks. Each --assert pairs with a
+filter argument, in order; an assertion fails when any ticket matches its
+filter. Every assertion is reported, the offending tickets are listed under a
+failure, and the exit status is non-zero when any assertion failed:
+  tk query --assert 'no open criticals' '.priority == "0" and .status != "closed"'
+  tk query --assert 'bugs assigned' '.type == "bug" and .assignee == null' \
+           --assert 'work has an owner' '.status == "in_progress" and .assignee == null'
+
+Use --changed-since <cache-file> on large stores to re-parse only tickets
+modified since the previous run that used the same cache file.
+
+Use --raw-output (-r) to print the values a jq program produces, like jq -r:
+strings are printed unquoted, one per line, and null results are skipped.
+  tk query -r '.id'                 # Bare ticket IDs
+  tk query -r 'select(.status == "open") | .assignee'
+
+Use --warn-missing <field> to print a warning on stderr listing tickets that
+lack the field (e.g. older files without an assignee), since a filter on that
+field silently drops them.
+
+Use --distinct <field> to print each unique value of a field across the
+(filtered) tickets, sorted; array fields such as deps list each element.
+  tk query --distinct assignee
+  tk query --distinct type '.status == "open"'
+
+Use --template to render each (filtered) ticket through a Go text/template.
+The fields of the JSON output are available, plus title:
+  tk query --template '{{.id}} {{.title}} (P{{.priority}})'
+
+Use --format csv to write a header row and one CSV row per (filtered)
+ticket with the columns id, status, type, priority, assignee, title and
+created, for spreadsheets. JSON remains the default.
+  tk query --format csv '.status == "open"' > open.csv
+
+Add --flatten <deps|links|tags> to write one row per element of that array
+instead, with the element in a last column named after the field and the
+ticket's columns repeated. Tickets with an empty array are skipped unless
+--keep-empty is given, which writes them once with an empty element:
+  tk query --format csv --flatten deps > deps.csv
+
+Use --format table for an aligned table with the columns ID, PRI, STATUS,
+TYPE and TITLE. Titles are truncated to the terminal width ($COLUMNS, or 80).
+
+Use --count to print just the number of (filtered) tickets, or --group-by
+<field> to print each value of a field with its ticket count, most common
+first. Array fields such as tags count each element; tickets with no value
+are counted as (none). Notes can't be grouped.
+  tk query --count '.status == "open"'
+  tk query --group-by assignee '.status != "closed"'
+
+Use --created-histogram <day|week|month> to print, after any filter, how many
+tickets were created in each time bucket, oldest first.
+  tk query --created-histogram week '.type == "bug"'`,
 	RunE: runQuery,
 }
 
+var (
+	queryTitleMatch   string
+	queryOlderThan    string
+	queryChangedSince string
+	queryFailIfAny    bool
+	queryWarnMissing  []string
+	queryRawOutput    bool
+	queryHistogram    string
+	queryLinkedTo     string
+	queryDependsOn    string
+	queryChildOf      string
+	queryDistinct     string
+	queryTemplate     string
+	queryViews        []string
+	queryAnyView      bool
+	querySaveView     string
+	queryAsserts      []string
+	queryFormat       string
+	queryFlatten      string
+	queryKeepEmpty    bool
+	queryCount        bool
+	queryGroupBy      string
+	querySort         string
+	querySlurp        bool
+)
+
+// queryOutputFormat is a --format value of query
+type queryOutputFormat string
+
+const (
+	formatNDJSON queryOutputFormat = "ndjson" // One JSON object per line
+	formatJSON   queryOutputFormat = "json"   // A single JSON array
+	formatCSV    queryOutputFormat = "csv"
+	formatTable  queryOutputFormat = "table"
+)
+
+// queryFormats lists the valid --format values, default first
+var queryFormats = []queryOutputFormat{formatNDJSON, formatJSON, formatCSV, formatTable}
+
+// querySortFields compares two tickets by each --sort field, returning a
+// negative number, zero or a positive number
+var querySortFields = map[string]func(a, b *ticket.Ticket) int{
+	"id":       func(a, b *ticket.Ticket) int { return strings.Compare(a.ID, b.ID) },
+	"priority": func(a, b *ticket.Ticket) int { return a.Priority - b.Priority },
+	"created":  func(a, b *ticket.Ticket) int { return a.Created.Compare(b.Created) },
+	"status":   func(a, b *ticket.Ticket) int { return statusRank(a.Status) - statusRank(b.Status) },
+	"type":     func(a, b *ticket.Ticket) int { return strings.Compare(string(a.Type), string(b.Type)) },
+	"title":    func(a, b *ticket.Ticket) int { return strings.Compare(a.Title, b.Title) },
+	"assignee": func(a, b *ticket.Ticket) int { return strings.Compare(a.Assignee, b.Assignee) },
+}
+
+// statusRank returns the position of a status in ValidStatuses, i.e. its
+// place in the workflow, with unknown statuses last
+func statusRank(s ticket.Status) int {
+	for i, v := range ticket.ValidStatuses {
+		if s == v {
+			return i
+		}
+	}
+	return len(ticket.ValidStatuses)
+}
+
+// parseQuerySort turns a --sort value such as -created into an ordering on
+// tickets. Ties are broken by ascending ID so the output is stable.
+func parseQuerySort(value string) (func(a, b *ticket.Ticket) bool, error) {
+	field := strings.TrimPrefix(strings.TrimPrefix(value, "-"), ".")
+	descending := strings.HasPrefix(value, "-")
+
+	compare, ok := querySortFields[field]
+	if !ok {
+		names := make([]string, 0, len(querySortFields))
+		for name := range querySortFields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unsupported --sort field %q (use %s)", field, strings.Join(names, ", "))
+	}
+
+	return func(a, b *ticket.Ticket) bool {
+		c := compare(a, b)
+		if descending {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return a.ID < b.ID
+	}, nil
+}
+
+// parseQueryFormat validates a --format value
+func parseQueryFormat(value string) (queryOutputFormat, error) {
+	names := make([]string, len(queryFormats))
+	for i, f := range queryFormats {
+		if queryOutputFormat(value) == f {
+			return f, nil
+		}
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unsupported --format %q (use %s)", value, strings.Join(names, ", "))
+}
+
 func init() {
 	rootCmd.AddCommand(queryCmd)
+	queryCmd.Flags().StringVar(&queryTitleMatch, "title-match", "", "Only include tickets whose title matches this regular expression")
+	queryCmd.Flags().StringVar(&queryOlderThan, "older-than", "", "Only include tickets created more than this long ago (e.g. 14d, 2w, 36h)")
+	queryCmd.Flags().BoolVar(&queryFailIfAny, "fail-if-any", false, "Print nothing and exit non-zero if any ticket matches")
+	queryCmd.Flags().StringVar(&queryChangedSince, "changed-since", "", "Cache file for incremental parsing of tickets changed since the last run")
+	queryCmd.Flags().BoolVarP(&queryRawOutput, "raw-output", "r", false, "Print the program's output values, strings unquoted")
+	queryCmd.Flags().StringVar(&queryLinkedTo, "linked-to", "", "Only include tickets linked to this ticket")
+	queryCmd.Flags().StringVar(&queryDependsOn, "depends-on", "", "Only include tickets that depend on this ticket")
+	queryCmd.Flags().StringVar(&queryChildOf, "child-of", "", "Only include tickets whose parent is this ticket")
+	queryCmd.Flags().StringArrayVar(&queryViews, "view", nil, "Apply a saved view (repeatable; all must match)")
+	queryCmd.Flags().BoolVar(&queryAnyView, "any-view", false, "Match tickets in any of the --view views instead of all")
+	queryCmd.Flags().StringVar(&querySaveView, "save-view", "", "Save the filter as a named view instead of running it")
+	queryCmd.Flags().StringArrayVar(&queryAsserts, "assert", nil, "Name an assertion that fails if any ticket matches its filter (repeatable)")
+	queryCmd.Flags().StringVar(&queryTemplate, "template", "", "Render each ticket with a Go text/template")
+	queryCmd.Flags().StringVar(&queryFormat, "format", string(formatNDJSON), "Output format (ndjson|json|csv|table)")
+	queryCmd.Flags().BoolVar(&querySlurp, "slurp", false, "Print one JSON array of all matches, like --format json")
+	queryCmd.Flags().StringVar(&queryFlatten, "flatten", "", "With --format csv, write one row per element of this array field (deps|links|tags)")
+	queryCmd.Flags().BoolVar(&queryKeepEmpty, "keep-empty", false, "With --flatten, keep tickets whose array is empty")
+	queryCmd.Flags().StringVar(&queryDistinct, "distinct", "", "Print the sorted unique values of this field")
+	queryCmd.Flags().BoolVar(&queryCount, "count", false, "Print the number of matching tickets")
+	queryCmd.Flags().StringVar(&queryGroupBy, "group-by", "", "Print the ticket count per value of this field")
+	queryCmd.Flags().StringVar(&querySort, "sort", "", "Sort tickets by this field, descending with a - prefix (e.g. priority, -created)")
+	queryCmd.Flags().StringVar(&queryHistogram, "created-histogram", "", "Print ticket counts per creation day, week or month")
+	queryCmd.Flags().StringSliceVar(&queryWarnMissing, "warn-missing", nil, "Warn on stderr about tickets missing this field (repeatable)")
 }
 
 func runQuery(cmd *cobra.Command, args []string) error {
-	tickets, err := store.List()
+	if querySaveView != "" {
+		return saveView(cmd, args)
+	}
+	if queryAnyView && len(queryViews) < 2 {
+		return fmt.Errorf("--any-view needs at least two --view flags")
+	}
+	var viewFilters []string
+	if len(queryViews) > 0 {
+		cfg, err := config.Load(store.Dir())
+		if err != nil {
+			return err
+		}
+		for _, name := range queryViews {
+			filter, err := cfg.View(name)
+			if err != nil {
+				return err
+			}
+			viewFilters = append(viewFilters, filter)
+		}
+	}
+
+	var titleRe *regexp.Regexp
+	if queryTitleMatch != "" {
+		re, err := regexp.Compile(queryTitleMatch)
+		if err != nil {
+			return fmt.Errorf("invalid --title-match regex: %w", err)
+		}
+		titleRe = re
+	}
+	var less func(a, b *ticket.Ticket) bool
+	if querySort != "" {
+		l, err := parseQuerySort(querySort)
+		if err != nil {
+			return err
+		}
+		less = l
+	}
+	var cutoff time.Time
+	if queryOlderThan != "" {
+		age, err := parseAge(queryOlderThan)
+		if err != nil {
+			return err
+		}
+		cutoff = time.Now().Add(-age)
+	}
+	if queryHistogram != "" && !query.ValidBucket(queryHistogram) {
+		return fmt.Errorf("invalid --created-histogram %q (use day, week or month)", queryHistogram)
+	}
+	if queryHistogram != "" && queryRawOutput {
+		return fmt.Errorf("--created-histogram cannot be combined with --raw-output")
+	}
+	if queryDistinct != "" && (queryRawOutput || queryHistogram != "") {
+		return fmt.Errorf("--distinct cannot be combined with --raw-output or --created-histogram")
+	}
+	format, err := parseQueryFormat(queryFormat)
+	if err != nil {
+		return err
+	}
+	if querySlurp {
+		if format != formatNDJSON && format != formatJSON {
+			return fmt.Errorf("--slurp cannot be combined with --format %s", format)
+		}
+		format = formatJSON
+	}
+	if format != formatNDJSON && (queryRawOutput || queryHistogram != "" || queryDistinct != "" || queryTemplate != "" || len(queryAsserts) > 0) {
+		return fmt.Errorf("--format %s cannot be combined with other output modes", format)
+	}
+	if queryFlatten != "" {
+		queryFlatten = strings.TrimPrefix(queryFlatten, ".")
+		if format != formatCSV {
+			return fmt.Errorf("--flatten requires --format csv")
+		}
+		if !isFlattenField(queryFlatten) {
+			return fmt.Errorf("invalid --flatten %q (use %s)", queryFlatten, strings.Join(flattenFields, ", "))
+		}
+	} else if queryKeepEmpty {
+		return fmt.Errorf("--keep-empty requires --flatten")
+	}
+	if queryCount || queryGroupBy != "" {
+		if queryCount && queryGroupBy != "" {
+			return fmt.Errorf("--count cannot be combined with --group-by")
+		}
+		if format != formatNDJSON || queryRawOutput || queryFailIfAny || queryHistogram != "" || queryDistinct != "" || queryTemplate != "" || len(queryAsserts) > 0 {
+			return fmt.Errorf("--count and --group-by cannot be combined with other output modes")
+		}
+	}
+	if len(queryAsserts) > 0 {
+		if len(args) != len(queryAsserts) {
+			return fmt.Errorf("--assert needs one filter per assertion: got %d name(s) and %d filter(s)", len(queryAsserts), len(args))
+		}
+		if queryRawOutput || queryFailIfAny || queryHistogram != "" || queryDistinct != "" || queryTemplate != "" {
+			return fmt.Errorf("--assert cannot be combined with other output modes")
+		}
+		for i, expr := range args {
+			if _, err := query.Compile(expr); err != nil {
+				return fmt.Errorf("assertion %q: %w", queryAsserts[i], err)
+			}
+		}
+	}
+	var tmpl *template.Template
+	if queryTemplate != "" {
+		if queryRawOutput || queryHistogram != "" || queryDistinct != "" {
+			return fmt.Errorf("--template cannot be combined with --raw-output, --distinct or --created-histogram")
+		}
+		t, err := template.New("query").Parse(queryTemplate)
+		if err != nil {
+			return fmt.Errorf("invalid --template: %w", err)
+		}
+		tmpl = t
+	}
+
+	var tickets []*ticket.Ticket
+	if queryChangedSince != "" {
+		tickets, err = listIncremental(queryChangedSince)
+	} else {
+		tickets, err = store.List()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Prefilter by title before any jq filter runs
+	if titleRe != nil {
+		var matched []*ticket.Ticket
+		for _, t := range tickets {
+			if titleRe.MatchString(t.Title) {
+				matched = append(matched, t)
+			}
+		}
+		tickets = matched
+	}
+
+	// Prefilter by age
+	if !cutoff.IsZero() {
+		var old []*ticket.Ticket
+		for _, t := range tickets {
+			if t.Created.Before(cutoff) {
+				old = append(old, t)
+			}
+		}
+		tickets = old
+	}
+
+	tickets, err = filterByRelation(tickets)
 	if err != nil {
 		return err
 	}
 
+	// Filters keep the order of their input, so sorting here orders the
+	// filtered output
+	if less != nil {
+		sort.SliceStable(tickets, func(i, j int) bool {
+			return less(tickets[i], tickets[j])
+		})
+	}
+
 	// Convert all tickets to JSON
 	var jsonLines []string
+	titles := make(map[string]string)
 	for _, t := range tickets {
 		line, err := query.ToJSON(t)
 		if err != nil {
 			continue
 		}
 		jsonLines = append(jsonLines, line)
+		titles[t.ID] = t.Title
+	}
+
+	// Report schema drift before the filter drops tickets lacking a field
+	for _, field := range queryWarnMissing {
+		field = strings.TrimPrefix(field, ".")
+		if ids := query.MissingField(jsonLines, field); len(ids) > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: field '%s' missing on %d ticket(s): %s\n", field, len(ids), strings.Join(ids, ", "))
+		}
+	}
+
+	jsonLines, err = applyViews(jsonLines, viewFilters)
+	if err != nil {
+		return err
+	}
+
+	if len(queryAsserts) > 0 {
+		return runAssertions(cmd, jsonLines, titles, queryAsserts, args)
+	}
+
+	if queryRawOutput && len(args) > 0 {
+		return printRawOutput(cmd, jsonLines, args[0])
 	}
 
 	// Apply filter if provided
@@ -49,6 +445,62 @@ func runQuery(cmd *cobra.Command, args []string) error {
 		jsonLines = filtered
 	}
 
+	if queryFailIfAny {
+		if len(jsonLines) > 0 {
+			return failSilently(cmd, exitError)
+		}
+		return nil
+	}
+
+	if queryCount {
+		fmt.Println(len(jsonLines))
+		return nil
+	}
+
+	if queryGroupBy != "" {
+		groups, err := query.GroupBy(jsonLines, strings.TrimPrefix(queryGroupBy, "."))
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			fmt.Printf("%s %d\n", g.Value, g.Count)
+		}
+		return nil
+	}
+
+	if tmpl != nil {
+		return printTemplate(jsonLines, titles, tmpl)
+	}
+
+	switch format {
+	case formatJSON:
+		printJSONArray(jsonLines)
+		return nil
+	case formatCSV:
+		return printCSV(cmd, jsonLines, titles, queryFlatten, queryKeepEmpty)
+	case formatTable:
+		printTable(cmd, jsonLines, titles, terminalWidth())
+		return nil
+	}
+
+	if queryDistinct != "" {
+		for _, v := range query.Distinct(jsonLines, strings.TrimPrefix(queryDistinct, ".")) {
+			fmt.Println(v)
+		}
+		return nil
+	}
+
+	if queryHistogram != "" {
+		buckets, err := query.CreatedHistogram(jsonLines, queryHistogram)
+		if err != nil {
+			return err
+		}
+		for _, b := range buckets {
+			fmt.Printf("%s %d\n", b.Label, b.Count)
+		}
+		return nil
+	}
+
 	// Print results
 	for _, line := range jsonLines {
 		fmt.Println(line)
@@ -56,3 +508,311 @@ func runQuery(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// saveView stores the filter argument under the --save-view name
+func saveView(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("--save-view requires a filter")
+	}
+	if _, err := query.Compile(args[0]); err != nil {
+		return err
+	}
+	if err := config.SaveView(store.Dir(), querySaveView, args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved view %s\n", querySaveView)
+	return nil
+}
+
+// applyViews keeps the lines matching every view filter, or any of them
+// with --any-view
+func applyViews(jsonLines []string, filters []string) ([]string, error) {
+	if queryAnyView {
+		return query.FilterAny(jsonLines, filters)
+	}
+	for _, filter := range filters {
+		filtered, err := query.Filter(jsonLines, filter)
+		if err != nil {
+			return nil, err
+		}
+		jsonLines = filtered
+	}
+	return jsonLines, nil
+}
+
+// runAssertions checks each named filter against the tickets, listing the
+// offenders of every assertion that matches at least one ticket
+func runAssertions(cmd *cobra.Command, jsonLines []string, titles map[string]string, names, exprs []string) error {
+	out := cmd.OutOrStdout()
+	failed := 0
+	for i, name := range names {
+		offenders, err := query.Filter(jsonLines, exprs[i])
+		if err != nil {
+			return fmt.Errorf("assertion %q: %w", name, err)
+		}
+		if len(offenders) == 0 {
+			fmt.Fprintf(out, "PASS %s\n", name)
+			continue
+		}
+		failed++
+		fmt.Fprintf(out, "FAIL %s (%d ticket(s))\n", name, len(offenders))
+		for _, line := range offenders {
+			var t struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal([]byte(line), &t); err != nil {
+				continue
+			}
+			fmt.Fprintf(out, "  %s %s\n", t.ID, titles[t.ID])
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%d of %d assertion(s) failed\n", failed, len(names))
+		return failSilently(cmd, exitError)
+	}
+	return nil
+}
+
+// filterByRelation applies the --linked-to, --depends-on and --child-of
+// prefilters, resolving their partial IDs
+func filterByRelation(tickets []*ticket.Ticket) ([]*ticket.Ticket, error) {
+	relations := []struct {
+		partial string
+		related func(t *ticket.Ticket, id string) bool
+	}{
+		{queryLinkedTo, func(t *ticket.Ticket, id string) bool {
+			_, ok := t.FindLink(id)
+			return ok
+		}},
+		{queryDependsOn, func(t *ticket.Ticket, id string) bool {
+			for _, dep := range t.Deps {
+				if dep == id {
+					return true
+				}
+			}
+			return false
+		}},
+		{queryChildOf, func(t *ticket.Ticket, id string) bool {
+			return t.Parent == id
+		}},
+	}
+
+	for _, rel := range relations {
+		if rel.partial == "" {
+			continue
+		}
+		id, err := store.ResolveID(rel.partial)
+		if err != nil {
+			return nil, err
+		}
+		var matched []*ticket.Ticket
+		for _, t := range tickets {
+			if rel.related(t, id) {
+				matched = append(matched, t)
+			}
+		}
+		tickets = matched
+	}
+	return tickets, nil
+}
+
+// printTemplate renders each JSON ticket through the template, one per line.
+// Titles are not part of the JSON output, so they are added from titles.
+func printTemplate(jsonLines []string, titles map[string]string, tmpl *template.Template) error {
+	for _, line := range jsonLines {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &data); err != nil {
+			continue
+		}
+		id, _ := data["id"].(string)
+		if _, ok := data["title"]; !ok {
+			data["title"] = titles[id]
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("rendering template for %s: %w", id, err)
+		}
+		fmt.Println(buf.String())
+	}
+	return nil
+}
+
+// printJSONArray prints the JSON tickets as a single array, one element
+// per line
+func printJSONArray(jsonLines []string) {
+	if len(jsonLines) == 0 {
+		fmt.Println("[]")
+		return
+	}
+	fmt.Println("[\n" + strings.Join(jsonLines, ",\n") + "\n]")
+}
+
+// csvColumns are the JSON fields written by --format csv, in order. The
+// title comes from the ticket rather than the JSON output.
+var csvColumns = []string{"id", "status", "type", "priority", "assignee", "title", "created"}
+
+// flattenFields are the array fields query --flatten can expand
+var flattenFields = []string{"deps", "links", "tags"}
+
+// isFlattenField reports whether field can be expanded by --flatten
+func isFlattenField(field string) bool {
+	for _, f := range flattenFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// printCSV writes the JSON tickets as CSV with a header row. With a flatten
+// field, each ticket gets one row per element of that array, in an extra
+// column; tickets with an empty array get a single row if keepEmpty is set
+// and none otherwise.
+func printCSV(cmd *cobra.Command, jsonLines []string, titles map[string]string, flatten string, keepEmpty bool) error {
+	w := csv.NewWriter(cmd.OutOrStdout())
+	header := csvColumns
+	if flatten != "" {
+		header = append(append([]string{}, csvColumns...), flatten)
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, line := range jsonLines {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &data); err != nil {
+			continue
+		}
+		id, _ := data["id"].(string)
+		row := make([]string, len(csvColumns))
+		for i, col := range csvColumns {
+			if col == "title" {
+				row[i] = titles[id]
+				continue
+			}
+			row[i], _ = data[col].(string)
+		}
+
+		if flatten == "" {
+			if err := w.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+
+		elements, _ := data[flatten].([]interface{})
+		if len(elements) == 0 && keepEmpty {
+			elements = []interface{}{""}
+		}
+		for _, e := range elements {
+			if err := w.Write(append(append([]string{}, row...), fmt.Sprint(e))); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// terminalWidth returns the width of the terminal from $COLUMNS, or 80
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// printTable writes the JSON tickets as an aligned table with a header and
+// underline, truncating titles so rows fit within width
+func printTable(cmd *cobra.Command, jsonLines []string, titles map[string]string, width int) {
+	headers := []string{"ID", "PRI", "STATUS", "TYPE", "TITLE"}
+	var rows [][]string
+	for _, line := range jsonLines {
+		var t struct {
+			ID       string `json:"id"`
+			Priority string `json:"priority"`
+			Status   string `json:"status"`
+			Type     string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(line), &t); err != nil {
+			continue
+		}
+		rows = append(rows, []string{displayID(t.ID), "P" + t.Priority, t.Status, t.Type, titles[t.ID]})
+	}
+
+	// Size every column but the title to its widest cell
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i := 0; i < len(row)-1; i++ {
+			if len(row[i]) > widths[i] {
+				widths[i] = len(row[i])
+			}
+		}
+	}
+	titleWidth := width
+	for i := 0; i < len(widths)-1; i++ {
+		titleWidth -= widths[i] + 2
+	}
+	if titleWidth < len("TITLE") {
+		titleWidth = len("TITLE")
+	}
+	widths[len(widths)-1] = titleWidth
+
+	out := cmd.OutOrStdout()
+	underline := make([]string, len(headers))
+	for i, h := range headers {
+		underline[i] = strings.Repeat("-", len(h))
+	}
+	for _, row := range append([][]string{headers, underline}, rows...) {
+		var cells []string
+		for i, cell := range row {
+			if i == len(row)-1 {
+				cells = append(cells, truncate(cell, widths[i]))
+				continue
+			}
+			cells = append(cells, fmt.Sprintf("%-*s", widths[i], cell))
+		}
+		fmt.Fprintln(out, strings.Join(cells, "  "))
+	}
+}
+
+// truncate shortens s to at most width runes, marking the cut with "..."
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+// printRawOutput evaluates the program against each ticket and prints the
+// resulting values jq -r style
+func printRawOutput(cmd *cobra.Command, jsonLines []string, program string) error {
+	values, err := query.Eval(jsonLines, program)
+	if err != nil {
+		return err
+	}
+
+	if queryFailIfAny {
+		if len(values) > 0 {
+			return failSilently(cmd, exitError)
+		}
+		return nil
+	}
+
+	for _, v := range values {
+		line, err := query.FormatRaw(v)
+		if err != nil {
+			return err
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
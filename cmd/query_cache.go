@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// queryCache is the file written by query --changed-since. It records each
+// ticket as parsed on the last run, along with the size and modification
+// time its file had then.
+type queryCache struct {
+	Files map[string]cachedTicket `json:"files"`
+}
+
+// cachedTicket is a parsed ticket and the file it was parsed from
+type cachedTicket struct {
+	ticket.FileStat
+	Ticket *ticket.Ticket `json:"ticket"`
+}
+
+// listIncremental returns all tickets, re-parsing only those whose file
+// changed size or modification time since the cache at path was written.
+// Comparing for equality rather than against the time of the last run
+// catches edits made within the filesystem's timestamp granularity of that
+// run, unless they also keep the size. Falls back to a full scan when the
+// cache is missing or unreadable.
+func listIncremental(path string) ([]*ticket.Ticket, error) {
+	stats, err := store.FileStats()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := loadQueryCache(path)
+
+	ids := make([]string, 0, len(stats))
+	for id := range stats {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	next := queryCache{Files: make(map[string]cachedTicket)}
+	var tickets []*ticket.Ticket
+	for _, id := range ids {
+		stat := stats[id]
+		cached, ok := cache.Files[id]
+		t := cached.Ticket
+		if !ok || t == nil || !cached.ModTime.Equal(stat.ModTime) || cached.Size != stat.Size {
+			t, err = store.Get(id)
+			if err != nil {
+				// Skip malformed tickets, as List does
+				continue
+			}
+		}
+		next.Files[id] = cachedTicket{FileStat: stat, Ticket: t}
+		tickets = append(tickets, t)
+	}
+
+	if err := saveQueryCache(path, next); err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// loadQueryCache reads the cache, returning an empty cache if it is unusable
+func loadQueryCache(path string) queryCache {
+	var cache queryCache
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &cache) != nil || cache.Files == nil {
+		return queryCache{}
+	}
+	return cache
+}
+
+// saveQueryCache writes the cache atomically
+func saveQueryCache(path string, cache queryCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("encoding query cache: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing query cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming query cache: %w", err)
+	}
+	return nil
+}
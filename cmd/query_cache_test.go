@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestQueryChangedSince tests incremental parsing for query --changed-since
+func TestQueryChangedSince(t *testing.T) {
+	t.Run("only changed files are re-parsed", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		var ids []string
+		for _, title := range []string{"First", "Second", "Third"} {
+			id, _ := ctx.exec("new", title)
+			ids = append(ids, strings.TrimSpace(id))
+		}
+
+		cachePath := filepath.Join(t.TempDir(), "query-cache")
+
+		// First run has no cache and parses everything
+		output, err := ctx.exec("query", "--changed-since", cachePath)
+		if err != nil {
+			t.Fatalf("query --changed-since error: %v", err)
+		}
+		if got := len(strings.Split(strings.TrimSpace(output), "\n")); got != 3 {
+			t.Fatalf("expected 3 results, got %d", got)
+		}
+		if _, err := os.Stat(cachePath); err != nil {
+			t.Fatalf("cache file should be written: %v", err)
+		}
+
+		// Edit two tickets, keeping their modification times as if the edits
+		// fell in the same timestamp tick as the run
+		modTimes, _ := ctx.store().ModTimes()
+		edit := func(id, from, to string) {
+			_, content, err := ctx.store().ReadRaw(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := ctx.store().WriteRaw(id, strings.Replace(content, from, to, 1)); err != nil {
+				t.Fatal(err)
+			}
+			if err := ctx.store().SetModTime(id, modTimes[id]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		edit(ids[0], "# First", "# Fir5t")          // Same size: the cached ticket is used
+		edit(ids[1], "# Second", "# Second edited") // Size changed: re-parsed
+
+		tickets, err := listIncremental(cachePath)
+		if err != nil {
+			t.Fatalf("listIncremental error: %v", err)
+		}
+		if len(tickets) != 3 {
+			t.Fatalf("got %d tickets, want 3", len(tickets))
+		}
+		titles := make(map[string]string)
+		for _, tk := range tickets {
+			titles[tk.ID] = tk.Title
+		}
+		if titles[ids[0]] != "First" {
+			t.Errorf("unchanged file should come from the cache, got title %q", titles[ids[0]])
+		}
+		if titles[ids[1]] != "Second edited" {
+			t.Errorf("file with a new size should be re-parsed, got title %q", titles[ids[1]])
+		}
+
+		// A new modification time alone is enough
+		ctx.store().SetModTime(ids[0], modTimes[ids[0]].Add(time.Second))
+		tickets, _ = listIncremental(cachePath)
+		for _, tk := range tickets {
+			if tk.ID == ids[0] && tk.Title != "Fir5t" {
+				t.Errorf("file with a new modification time should be re-parsed, got title %q", tk.Title)
+			}
+		}
+	})
+
+	t.Run("falls back to full scan without cache", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "First")
+		ctx.exec("new", "Second")
+		ctx.exec("query")
+
+		cachePath := filepath.Join(t.TempDir(), "query-cache")
+		os.WriteFile(cachePath, []byte("not json"), 0644)
+
+		tickets, err := listIncremental(cachePath)
+		if err != nil {
+			t.Fatalf("listIncremental error: %v", err)
+		}
+		if len(tickets) != 2 {
+			t.Errorf("got %d tickets, want 2", len(tickets))
+		}
+		if cache := loadQueryCache(cachePath); len(cache.Files) != 2 {
+			t.Errorf("cache should be rewritten with 2 files, got %d", len(cache.Files))
+		}
+	})
+
+	t.Run("deleted tickets drop out", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		keep, _ := ctx.exec("new", "Keep")
+		keep = strings.TrimSpace(keep)
+		gone, _ := ctx.exec("new", "Gone")
+		gone = strings.TrimSpace(gone)
+
+		cachePath := filepath.Join(t.TempDir(), "query-cache")
+		ctx.exec("query", "--changed-since", cachePath)
+		ctx.exec("rm", gone)
+
+		output, err := ctx.exec("query", "--changed-since", cachePath)
+		if err != nil {
+			t.Fatalf("query error: %v", err)
+		}
+		if !strings.Contains(output, keep) || strings.Contains(output, gone) {
+			t.Errorf("expected only %s, got: %s", keep, output)
+		}
+	})
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
 )
 
 // TestQueryCommand tests the query command
@@ -520,3 +527,864 @@ func TestQueryCommand(t *testing.T) {
 		}
 	})
 }
+
+// TestQueryTitleMatch tests the --title-match regex prefilter
+func TestQueryTitleMatch(t *testing.T) {
+	t.Run("selects titles matching regex", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		wip, _ := ctx.exec("new", "WIP refactor parser")
+		wip = strings.TrimSpace(wip)
+		other, _ := ctx.exec("new", "Refactor WIP leftovers")
+		other = strings.TrimSpace(other)
+
+		output, err := ctx.exec("query", "--title-match", "^WIP")
+		if err != nil {
+			t.Fatalf("query --title-match error: %v", err)
+		}
+
+		if !strings.Contains(output, wip) {
+			t.Errorf("should include ticket titled with WIP prefix, got: %s", output)
+		}
+		if strings.Contains(output, other) {
+			t.Errorf("should exclude ticket without WIP prefix, got: %s", output)
+		}
+	})
+
+	t.Run("composes with jq filter", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		high, _ := ctx.exec("new", "WIP urgent", "--priority", "0")
+		high = strings.TrimSpace(high)
+		low, _ := ctx.exec("new", "WIP later", "--priority", "3")
+		low = strings.TrimSpace(low)
+
+		output, err := ctx.exec("query", "--title-match", "^WIP", `.priority == "0"`)
+		if err != nil {
+			t.Fatalf("query error: %v", err)
+		}
+
+		if !strings.Contains(output, high) || strings.Contains(output, low) {
+			t.Errorf("expected only %s, got: %s", high, output)
+		}
+	})
+
+	t.Run("invalid regex errors", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Some ticket")
+
+		_, err := ctx.exec("query", "--title-match", "([a-z")
+		if err == nil {
+			t.Fatal("expected error for invalid regex")
+		}
+		if !strings.Contains(err.Error(), "invalid --title-match regex") {
+			t.Errorf("error should mention the invalid regex, got: %v", err)
+		}
+	})
+}
+
+// TestQueryFailIfAny tests the --fail-if-any CI gate
+func TestQueryFailIfAny(t *testing.T) {
+	t.Run("match exits non-zero silently", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Critical", "--priority", "0")
+
+		output, err := ctx.exec("query", "--fail-if-any", `.priority == "0" and .status != "closed"`)
+		if err == nil {
+			t.Fatal("expected failure when a ticket matches")
+		}
+		var silent silentExit
+		if !errors.As(err, &silent) || silent.code == 0 {
+			t.Errorf("expected non-zero silent exit, got: %v", err)
+		}
+		if strings.TrimSpace(output) != "" {
+			t.Errorf("should print nothing, got: %q", output)
+		}
+	})
+
+	t.Run("no match exits zero", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Normal", "--priority", "2")
+
+		output, err := ctx.exec("query", "--fail-if-any", `.priority == "0"`)
+		if err != nil {
+			t.Fatalf("expected success when nothing matches, got: %v", err)
+		}
+		if strings.TrimSpace(output) != "" {
+			t.Errorf("should print nothing, got: %q", output)
+		}
+	})
+
+	t.Run("later errors are not silenced", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Critical", "--priority", "0")
+		ctx.exec("query", "--fail-if-any")
+		queryFailIfAny = false
+
+		output, err := ctx.exec("query", "--title-match", "([")
+		if err == nil || !strings.Contains(output, "Error:") {
+			t.Errorf("expected visible error after a silent failure, got: %q (%v)", output, err)
+		}
+	})
+}
+
+func TestQueryWarnMissing(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	withID, _ := ctx.exec("new", "Assigned", "--assignee", "alice")
+	withID = strings.TrimSpace(withID)
+	withoutID, _ := ctx.exec("new", "Legacy")
+	withoutID = strings.TrimSpace(withoutID)
+
+	// Simulate an older file written without an assignee
+	legacy, err := ctx.store().Get(withoutID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy.Assignee = ""
+	if err := ctx.store().Update(legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := ctx.exec("query", "--warn-missing", "assignee", `.assignee == "alice"`)
+	if err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if !strings.Contains(output, "Warning: field 'assignee' missing on 1 ticket(s): "+withoutID) {
+		t.Errorf("expected missing-field warning for %s, got: %s", withoutID, output)
+	}
+	if !strings.Contains(output, `"id":"`+withID+`"`) {
+		t.Errorf("expected filtered result for %s, got: %s", withID, output)
+	}
+}
+
+func TestQueryRawOutput(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	a, _ := ctx.exec("new", "First", "--assignee", "alice")
+	a = strings.TrimSpace(a)
+	b, _ := ctx.exec("new", "Second", "--assignee", "bob")
+	b = strings.TrimSpace(b)
+
+	t.Run("bare ids", func(t *testing.T) {
+		output, err := ctx.exec("query", "--raw-output", ".id")
+		if err != nil {
+			t.Fatalf("query -r error: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got: %q", output)
+		}
+		for _, id := range []string{a, b} {
+			found := false
+			for _, line := range lines {
+				if line == id {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected bare ID %s in output, got: %q", id, output)
+			}
+		}
+	})
+
+	t.Run("bare names", func(t *testing.T) {
+		output, err := ctx.exec("query", "-r", ".assignee")
+		if err != nil {
+			t.Fatalf("query -r error: %v", err)
+		}
+		if strings.Contains(output, `"`) {
+			t.Errorf("expected unquoted values, got: %q", output)
+		}
+		if !strings.Contains(output, "alice\n") || !strings.Contains(output, "bob\n") {
+			t.Errorf("expected bare assignees, got: %q", output)
+		}
+	})
+
+	t.Run("non-string values as JSON", func(t *testing.T) {
+		output, err := ctx.exec("query", "-r", ".deps")
+		if err != nil {
+			t.Fatalf("query -r error: %v", err)
+		}
+		if strings.TrimSpace(output) != "[]\n[]" {
+			t.Errorf("expected JSON arrays, got: %q", output)
+		}
+	})
+}
+
+func TestQueryExtraFields(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	inSprint, _ := ctx.exec("new", "In sprint")
+	inSprint = strings.TrimSpace(inSprint)
+	other, _ := ctx.exec("new", "Backlog")
+	other = strings.TrimSpace(other)
+
+	if _, err := ctx.store().UpdateField(inSprint, "sprint", "s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := ctx.exec("query", `.sprint == "s1"`)
+	if err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if !strings.Contains(output, inSprint) || strings.Contains(output, other) {
+		t.Errorf("expected only %s, got: %s", inSprint, output)
+	}
+}
+
+func TestQueryCreatedHistogram(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// Two tickets in ISO week 2 and one in week 3 of 2026
+	created := map[string]string{
+		"h-1": "2026-01-05T09:00:00Z",
+		"h-2": "2026-01-09T15:00:00Z",
+		"h-3": "2026-01-13T11:00:00Z",
+	}
+	for id, c := range created {
+		ts, _ := time.Parse(time.RFC3339, c)
+		if err := ctx.store().Create(&ticket.Ticket{ID: id, Status: "open", Type: "task", Title: "Histogram", Created: ts}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	output, err := ctx.exec("query", "--created-histogram", "week")
+	if err != nil {
+		t.Fatalf("query --created-histogram error: %v", err)
+	}
+	if want := "2026-W02 2\n2026-W03 1\n"; output != want {
+		t.Errorf("output = %q, want %q", output, want)
+	}
+
+	output, err = ctx.exec("query", "--created-histogram", "week", `.id != "h-1"`)
+	if err != nil {
+		t.Fatalf("filtered histogram error: %v", err)
+	}
+	if want := "2026-W02 1\n2026-W03 1\n"; output != want {
+		t.Errorf("filtered output = %q, want %q", output, want)
+	}
+
+	if _, err := ctx.exec("query", "--created-histogram", "year"); err == nil {
+		t.Error("expected error for invalid bucket")
+	}
+}
+
+func TestQueryRelationFilters(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	hub, _ := ctx.exec("new", "Hub")
+	hub = strings.TrimSpace(hub)
+	linked, _ := ctx.exec("new", "Linked")
+	linked = strings.TrimSpace(linked)
+	dependant, _ := ctx.exec("new", "Dependant")
+	dependant = strings.TrimSpace(dependant)
+	// Created last since --parent persists across exec calls
+	ctx.exec("new", "Unrelated")
+	child, _ := ctx.exec("new", "Child", "--parent", hub)
+	child = strings.TrimSpace(child)
+
+	ctx.exec("link", hub, linked)
+	ctx.exec("dep", dependant, hub)
+	ctx.exec("dep", child, hub)
+
+	ids := func(output string) []string {
+		var got []string
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &obj); err == nil {
+				got = append(got, obj["id"].(string))
+			}
+		}
+		return got
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--linked-to", hub}, []string{linked}},
+		{[]string{"--depends-on", hub}, []string{dependant, child}},
+		{[]string{"--child-of", hub}, []string{child}},
+		{[]string{"--depends-on", hub, "--child-of", hub}, []string{child}},
+		{[]string{"--depends-on", hub, `.parent == null`}, []string{dependant}},
+	}
+	// Flag values persist across exec calls, so clear them before each query
+	reset := func() { queryLinkedTo, queryDependsOn, queryChildOf = "", "", "" }
+
+	for _, tt := range tests {
+		reset()
+		output, err := ctx.exec(append([]string{"query"}, tt.args...)...)
+		if err != nil {
+			t.Fatalf("query %v error: %v", tt.args, err)
+		}
+		got := ids(output)
+		if len(got) != len(tt.want) {
+			t.Errorf("query %v = %v, want %v", tt.args, got, tt.want)
+			continue
+		}
+		for _, id := range tt.want {
+			if !strings.Contains(output, `"id":"`+id+`"`) {
+				t.Errorf("query %v = %v, want %v", tt.args, got, tt.want)
+			}
+		}
+	}
+
+	// Partial IDs resolve
+	reset()
+	output, _ := ctx.exec("query", "--child-of", hub[len(hub)-4:])
+	if got := ids(output); len(got) != 1 || got[0] != child {
+		t.Errorf("partial --child-of = %v, want [%s]", got, child)
+	}
+
+	reset()
+	if _, err := ctx.exec("query", "--linked-to", "does-not-exist"); err == nil {
+		t.Error("expected error for unknown ticket")
+	}
+}
+
+func TestQueryDistinct(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// Type persists across exec calls, so set it explicitly on each ticket
+	ctx.exec("new", "First bug", "--type", "bug")
+	ctx.exec("new", "Second bug", "--type", "bug")
+	ctx.exec("new", "A feature", "--type", "feature")
+
+	output, err := ctx.exec("query", "--distinct", "type")
+	if err != nil {
+		t.Fatalf("query --distinct error: %v", err)
+	}
+	if output != "bug\nfeature\n" {
+		t.Errorf("output = %q, want %q", output, "bug\nfeature\n")
+	}
+
+	output, _ = ctx.exec("query", "--distinct", ".type", `.type != "bug"`)
+	if output != "feature\n" {
+		t.Errorf("filtered output = %q, want %q", output, "feature\n")
+	}
+}
+
+func TestQueryTemplate(t *testing.T) {
+	t.Run("renders a custom line per ticket", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Templated", "--priority", "1")
+		id = strings.TrimSpace(id)
+
+		output, err := ctx.exec("query", "--template", "{{.id}} {{.title}} ({{.priority}})")
+		if err != nil {
+			t.Fatalf("query --template error: %v", err)
+		}
+		if want := id + " Templated (1)\n"; output != want {
+			t.Errorf("output = %q, want %q", output, want)
+		}
+	})
+
+	t.Run("bad template errors before processing", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Ticket")
+
+		output, err := ctx.exec("query", "--template", "{{.id")
+		if err == nil {
+			t.Fatal("expected error for malformed template")
+		}
+		if !strings.Contains(output, "invalid --template") {
+			t.Errorf("expected clear error, got: %s", output)
+		}
+	})
+}
+
+func TestQueryViews(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// Type and priority persist across exec calls, so set both on each ticket
+	ctx.exec("new", "Urgent bug", "--type", "bug", "--priority", "0")
+	ctx.exec("new", "Minor bug", "--type", "bug", "--priority", "3")
+	ctx.exec("new", "Urgent feature", "--type", "feature", "--priority", "0")
+	ctx.exec("new", "Minor feature", "--type", "feature", "--priority", "3")
+
+	if _, err := ctx.exec("query", "--save-view", "bugs", `.type == "bug"`); err != nil {
+		t.Fatalf("query --save-view error: %v", err)
+	}
+	resetFlags(queryCmd)
+	if _, err := ctx.exec("query", "--save-view", "urgent", `.priority == "0"`); err != nil {
+		t.Fatalf("query --save-view error: %v", err)
+	}
+
+	titles := func(args ...string) []string {
+		t.Helper()
+		resetFlags(queryCmd)
+		output, err := ctx.exec(append([]string{"query", "--template", "{{.title}}"}, args...)...)
+		if err != nil {
+			t.Fatalf("query %v error: %v", args, err)
+		}
+		got := strings.Split(strings.TrimSpace(output), "\n")
+		sort.Strings(got)
+		return got
+	}
+
+	if got, want := titles("--view", "bugs", "--view", "urgent"), []string{"Urgent bug"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AND views = %v, want %v", got, want)
+	}
+	if got, want := titles("--view", "bugs", "--view", "urgent", "--any-view"), []string{"Minor bug", "Urgent bug", "Urgent feature"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OR views = %v, want %v", got, want)
+	}
+	if got, want := titles("--view", "bugs", `.priority == "3"`), []string{"Minor bug"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("view plus filter = %v, want %v", got, want)
+	}
+
+	resetFlags(queryCmd)
+	if _, err := ctx.exec("query", "--view", "nope"); err == nil || !strings.Contains(err.Error(), `unknown view "nope"`) {
+		t.Errorf("unknown view error = %v", err)
+	}
+}
+
+// TestQueryAssert tests named --assert policy checks
+func TestQueryAssert(t *testing.T) {
+	t.Run("violated assertion lists offenders", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Critical", "--priority", "0")
+		id = strings.TrimSpace(id)
+		ctx.exec("new", "Normal", "--priority", "2")
+
+		output, err := ctx.exec("query",
+			"--assert", "no open criticals", `.priority == "0" and .status != "closed"`,
+			"--assert", "no backlog", `.priority == "4"`)
+		var silent silentExit
+		if !errors.As(err, &silent) || silent.code == 0 {
+			t.Fatalf("expected non-zero silent exit, got: %v", err)
+		}
+		if !strings.Contains(output, "FAIL no open criticals (1 ticket(s))") {
+			t.Errorf("should report the failed assertion, got:\n%s", output)
+		}
+		if !strings.Contains(output, "  "+id+" Critical") {
+			t.Errorf("should list the offender, got:\n%s", output)
+		}
+		if !strings.Contains(output, "PASS no backlog") {
+			t.Errorf("should report the passing assertion, got:\n%s", output)
+		}
+		if !strings.Contains(output, "1 of 2 assertion(s) failed") {
+			t.Errorf("should summarize failures, got:\n%s", output)
+		}
+	})
+
+	t.Run("satisfied assertion passes", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "Normal", "--priority", "2")
+
+		output, err := ctx.exec("query", "--assert", "no open criticals", `.priority == "0"`)
+		if err != nil {
+			t.Fatalf("expected success, got: %v", err)
+		}
+		if strings.TrimSpace(output) != "PASS no open criticals" {
+			t.Errorf("unexpected output: %q", output)
+		}
+	})
+
+	t.Run("names and filters must pair up", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		_, err := ctx.exec("query", "--assert", "a", "--assert", "b", `.priority == "0"`)
+		if err == nil || !strings.Contains(err.Error(), "one filter per assertion") {
+			t.Errorf("expected pairing error, got: %v", err)
+		}
+	})
+}
+
+// TestQueryFormatCSV tests --format csv output
+func TestQueryFormatCSV(t *testing.T) {
+	t.Run("header and quoted rows", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", `Fix "login", again`, "--priority", "1", "-a", "alice")
+		id = strings.TrimSpace(id)
+		ctx.exec("new", "Other", "--priority", "3", "-a", "bob")
+
+		output, err := ctx.exec("query", "--format", "csv", `.priority == "1"`)
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+
+		records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+		if err != nil {
+			t.Fatalf("output is not valid CSV: %v\n%s", err, output)
+		}
+		if len(records) != 2 {
+			t.Fatalf("expected header and one row, got %d records:\n%s", len(records), output)
+		}
+		if got := strings.Join(records[0], ","); got != "id,status,type,priority,assignee,title,created" {
+			t.Errorf("header = %q", got)
+		}
+		row := records[1]
+		if row[0] != id || row[1] != "open" || row[2] != "task" || row[3] != "1" || row[4] != "alice" {
+			t.Errorf("unexpected row: %q", row)
+		}
+		if row[5] != `Fix "login", again` {
+			t.Errorf("title = %q, want it unquoted after parsing", row[5])
+		}
+		if !strings.Contains(output, `"Fix ""login"", again"`) {
+			t.Errorf("title should be CSV-quoted, got:\n%s", output)
+		}
+		if row[6] == "" {
+			t.Error("created should be set")
+		}
+	})
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		_, err := ctx.exec("query", "--format", "xml")
+		if err == nil || !strings.Contains(err.Error(), "unsupported --format") {
+			t.Errorf("expected format error, got: %v", err)
+		}
+	})
+}
+
+// TestQueryFormatTable tests --format table output
+func TestQueryFormatTable(t *testing.T) {
+	t.Run("aligned columns with header", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		t.Setenv("COLUMNS", "60")
+
+		id, _ := ctx.exec("new", "A rather long title that will not fit in sixty columns at all", "--priority", "0")
+		id = strings.TrimSpace(id)
+		ctx.exec("new", "Other", "--priority", "3")
+
+		output, err := ctx.exec("query", "--format", "table", `.priority == "0"`)
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("expected header, underline and one row, got:\n%s", output)
+		}
+		if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "PRI  STATUS  TYPE  TITLE") {
+			t.Errorf("unexpected header: %q", lines[0])
+		}
+		if !strings.HasPrefix(lines[1], "--") || strings.Trim(lines[1], "- ") != "" {
+			t.Errorf("unexpected underline: %q", lines[1])
+		}
+		if !strings.HasPrefix(lines[2], id) || !strings.Contains(lines[2], "P0   open    task  A rather") {
+			t.Errorf("unexpected row: %q", lines[2])
+		}
+		if len([]rune(lines[2])) != 60 || !strings.HasSuffix(lines[2], "...") {
+			t.Errorf("row should be truncated to 60 columns, got %d: %q", len([]rune(lines[2])), lines[2])
+		}
+		if strings.Index(lines[0], "TITLE") != strings.Index(lines[2], "A rather") {
+			t.Errorf("title column misaligned:\n%s", output)
+		}
+	})
+
+	t.Run("width falls back to 80", func(t *testing.T) {
+		t.Setenv("COLUMNS", "")
+		if w := terminalWidth(); w != 80 {
+			t.Errorf("terminalWidth() = %d, want 80", w)
+		}
+	})
+}
+
+// TestQueryFormatJSON tests that ndjson is the default and json is an array
+func TestQueryFormatJSON(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	ctx.exec("new", "First")
+	ctx.exec("new", "Second")
+
+	bare, err := ctx.exec("query")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	ndjson, err := ctx.exec("query", "--format", "ndjson")
+	if err != nil {
+		t.Fatalf("query --format ndjson failed: %v", err)
+	}
+	if bare != ndjson {
+		t.Errorf("bare query and ndjson differ:\n%s\nvs\n%s", bare, ndjson)
+	}
+	if lines := strings.Split(strings.TrimSpace(ndjson), "\n"); len(lines) != 2 {
+		t.Errorf("ndjson should have one line per ticket, got:\n%s", ndjson)
+	}
+
+	array, err := ctx.exec("query", "--format", "json")
+	if err != nil {
+		t.Fatalf("query --format json failed: %v", err)
+	}
+	var tickets []map[string]interface{}
+	if err := json.Unmarshal([]byte(array), &tickets); err != nil {
+		t.Fatalf("json output is not an array: %v\n%s", err, array)
+	}
+	if len(tickets) != 2 {
+		t.Errorf("expected 2 tickets in the array, got %d", len(tickets))
+	}
+
+	empty, _ := ctx.exec("query", "--format", "json", `.priority == "9"`)
+	if strings.TrimSpace(empty) != "[]" {
+		t.Errorf("no matches should print [], got %q", empty)
+	}
+}
+
+// TestQuerySlurp tests that --slurp prints the same array as --format json
+func TestQuerySlurp(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	ctx.exec("new", "First", "-p", "1")
+	ctx.exec("new", "Second", "-p", "3")
+
+	array, err := ctx.exec("query", "--format", "json")
+	if err != nil {
+		t.Fatalf("query --format json failed: %v", err)
+	}
+	slurped, err := ctx.exec("query", "--format", "ndjson", "--slurp")
+	if err != nil {
+		t.Fatalf("query --slurp failed: %v", err)
+	}
+	if slurped != array {
+		t.Errorf("--slurp and --format json differ:\n%s\nvs\n%s", slurped, array)
+	}
+
+	var tickets []map[string]interface{}
+	if err := json.Unmarshal([]byte(slurped), &tickets); err != nil {
+		t.Fatalf("--slurp output is not an array: %v\n%s", err, slurped)
+	}
+	if len(tickets) != 2 {
+		t.Fatalf("expected 2 tickets in the array, got %d", len(tickets))
+	}
+	if _, ok := tickets[0]["priority"].(string); !ok {
+		t.Errorf("priority should stay a string, got %#v", tickets[0]["priority"])
+	}
+	for _, field := range []string{"deps", "links", "tags"} {
+		if list, ok := tickets[0][field].([]interface{}); !ok || len(list) != 0 {
+			t.Errorf("%s should be an empty array, got %#v", field, tickets[0][field])
+		}
+	}
+
+	empty, _ := ctx.exec("query", "--slurp", `.priority == "9"`)
+	if strings.TrimSpace(empty) != "[]" {
+		t.Errorf("no matches should print [], got %q", empty)
+	}
+
+	if _, err := ctx.exec("query", "--slurp", "--format", "csv"); err == nil {
+		t.Error("--slurp with --format csv should fail")
+	}
+}
+
+// TestQueryCount tests --count and --group-by
+func TestQueryCount(t *testing.T) {
+	t.Run("count matching tickets", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "One")
+		ctx.exec("new", "Two")
+		closed, _ := ctx.exec("new", "Three")
+		ctx.exec("close", strings.TrimSpace(closed))
+
+		output, err := ctx.exec("query", "--count", `.status == "open"`)
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if strings.TrimSpace(output) != "2" {
+			t.Errorf("count = %q, want 2", output)
+		}
+	})
+
+	t.Run("group by status", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("new", "One")
+		started, _ := ctx.exec("new", "Two")
+		ctx.exec("start", strings.TrimSpace(started))
+		ctx.exec("new", "Three")
+		ctx.exec("new", "Four")
+
+		output, err := ctx.exec("query", "--group-by", "status")
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if output != "open 3\nin_progress 1\n" {
+			t.Errorf("unexpected groups:\n%s", output)
+		}
+
+		if _, err := ctx.exec("query", "--group-by", "bogus"); err == nil {
+			t.Error("expected error for unknown field")
+		}
+	})
+}
+
+func TestQueryOlderThan(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, tk := range []*ticket.Ticket{
+		{ID: "old-open", Status: ticket.StatusOpen, Created: now.Add(-30 * 24 * time.Hour)},
+		{ID: "old-closed", Status: ticket.StatusClosed, Created: now.Add(-30 * 24 * time.Hour)},
+		{ID: "new-open", Status: ticket.StatusOpen, Created: now.Add(-2 * 24 * time.Hour)},
+	} {
+		tk.Type = ticket.TypeTask
+		tk.Title = "Aging " + tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	output, err := ctx.exec("query", "-r", "--older-than", "14d", `select(.status == "open") | .id`)
+	if err != nil {
+		t.Fatalf("query --older-than error: %v", err)
+	}
+	if output != "old-open\n" {
+		t.Errorf("expected only old-open, got %q", output)
+	}
+
+	if _, err := ctx.exec("query", "--older-than", "soon"); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
+
+func TestQueryFlatten(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, tk := range []*ticket.Ticket{
+		{ID: "f-a"},
+		{ID: "f-b"},
+		{ID: "f-two", Deps: []string{"f-a", "f-b"}},
+	} {
+		tk.Status, tk.Type, tk.Title = ticket.StatusOpen, ticket.TypeTask, "Flatten "+tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	records := func(args ...string) [][]string {
+		t.Helper()
+		output, err := ctx.exec(append([]string{"query", "--format", "csv", "--flatten", "deps"}, args...)...)
+		if err != nil {
+			t.Fatalf("query --flatten error: %v", err)
+		}
+		recs, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+		if err != nil {
+			t.Fatalf("output is not valid CSV: %v\n%s", err, output)
+		}
+		return recs
+	}
+
+	recs := records(`.id == "f-two"`)
+	if got := strings.Join(recs[0], ","); got != "id,status,type,priority,assignee,title,created,deps" {
+		t.Errorf("header = %q", got)
+	}
+	if len(recs) != 3 || recs[1][0] != "f-two" || recs[1][7] != "f-a" || recs[2][0] != "f-two" || recs[2][7] != "f-b" {
+		t.Errorf("expected a row per dep of f-two, got %q", recs)
+	}
+
+	if recs := records(`.id == "f-a"`); len(recs) != 1 {
+		t.Errorf("a ticket without deps should yield no rows, got %q", recs)
+	}
+
+	recs = records("--keep-empty", `.id == "f-a"`)
+	if len(recs) != 2 || recs[1][0] != "f-a" || recs[1][7] != "" {
+		t.Errorf("--keep-empty should yield one row with an empty dep, got %q", recs)
+	}
+
+	if _, err := ctx.exec("query", "--format", "csv", "--flatten", "status"); err == nil {
+		t.Error("expected error for a non-array field")
+	}
+}
+
+func TestQuerySort(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, tk := range []*ticket.Ticket{
+		{ID: "s-a", Priority: 2, Status: ticket.StatusClosed, Created: now.Add(-3 * time.Hour)},
+		{ID: "s-b", Priority: 0, Status: ticket.StatusOpen, Created: now.Add(-1 * time.Hour)},
+		{ID: "s-c", Priority: 2, Status: ticket.StatusInProgress, Created: now.Add(-2 * time.Hour)},
+		{ID: "s-d", Priority: 1, Status: ticket.StatusOpen, Created: now.Add(-4 * time.Hour)},
+	} {
+		tk.Type = ticket.TypeTask
+		tk.Title = "Sort " + tk.ID
+		if err := ctx.store().Create(tk); err != nil {
+			t.Fatalf("create %s: %v", tk.ID, err)
+		}
+	}
+
+	ids := func(args ...string) string {
+		t.Helper()
+		output, err := ctx.exec(append([]string{"query", "-r"}, args...)...)
+		if err != nil {
+			t.Fatalf("query %v error: %v", args, err)
+		}
+		return strings.Join(strings.Fields(output), ",")
+	}
+
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{"priority", "s-b,s-d,s-a,s-c"},
+		{"-priority", "s-a,s-c,s-d,s-b"},
+		{"created", "s-d,s-a,s-c,s-b"},
+		{"-created", "s-b,s-c,s-a,s-d"},
+		{"id", "s-a,s-b,s-c,s-d"},
+		{"status", "s-b,s-d,s-c,s-a"},
+	}
+	for _, tt := range tests {
+		if got := ids("--sort", tt.sort, ".id"); got != tt.want {
+			t.Errorf("--sort %s = %s, want %s", tt.sort, got, tt.want)
+		}
+	}
+
+	// Sorting applies after the filter
+	if got := ids("--sort", "priority", `select(.status == "open") | .id`); got != "s-b,s-d" {
+		t.Errorf("--sort with filter = %s, want s-b,s-d", got)
+	}
+
+	// And before rendering in other formats
+	queryRawOutput = false
+	output, err := ctx.exec("query", "--sort", "-priority", "--format", "csv")
+	if err != nil {
+		t.Fatalf("query --format csv error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[1], "s-a,") || !strings.HasPrefix(lines[4], "s-b,") {
+		t.Errorf("csv not sorted by -priority:\n%s", output)
+	}
+
+	if _, err := ctx.exec("query", "--sort", "colour"); err == nil || !strings.Contains(err.Error(), "unsupported --sort field") {
+		t.Errorf("expected an error for an unknown sort field, got %v", err)
+	}
+}
@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strings"
 
-	"github.com/lo5/tk/internal/ticket"
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/spf13/cobra"
 )
 
@@ -12,47 +13,29 @@ var readyCmd = &cobra.Command{
 	Use:   "ready",
 	Short: "List ready tickets",
 	Long: `List open/in-progress tickets with all dependencies resolved.
-Sorted by priority (ascending, 0=highest), then by ID.`,
+Sorted by priority (ascending, 0=highest), then by ID.
+
+Use --assume-closed X,Y to see what would become ready if those tickets were
+closed. Nothing is changed.`,
 	RunE: runReady,
 }
 
+var readyAssume []string
+
 func init() {
 	rootCmd.AddCommand(readyCmd)
+	readyCmd.Flags().StringSliceVar(&readyAssume, "assume-closed", nil, "Treat these tickets as closed (what-if, nothing is changed)")
 }
 
 func runReady(cmd *cobra.Command, args []string) error {
-	tickets, err := store.List()
+	graph, err := loadGraph()
 	if err != nil {
 		return err
 	}
-
-	// Build status map
-	statusMap := make(map[string]ticket.Status)
-	for _, t := range tickets {
-		statusMap[t.ID] = t.Status
-	}
-
-	// Filter ready tickets
-	var ready []*ticket.Ticket
-	for _, t := range tickets {
-		// Must be open or in_progress
-		if t.Status != ticket.StatusOpen && t.Status != ticket.StatusInProgress {
-			continue
-		}
-
-		// All deps must be closed
-		allDepsResolved := true
-		for _, dep := range t.Deps {
-			if statusMap[dep] != ticket.StatusClosed {
-				allDepsResolved = false
-				break
-			}
-		}
-
-		if allDepsResolved {
-			ready = append(ready, t)
-		}
+	if err := assumeClosed(cmd, graph, readyAssume); err != nil {
+		return err
 	}
+	ready := graph.Ready()
 
 	// Sort by priority, then by ID
 	sort.Slice(ready, func(i, j int) bool {
@@ -64,8 +47,23 @@ func runReady(cmd *cobra.Command, args []string) error {
 
 	// Print
 	for _, t := range ready {
-		fmt.Printf("%-8s [P%d][%s] - %s\n", t.ID, t.Priority, t.Status, t.Title)
+		fmt.Printf("%-8s [P%d][%s] - %s\n", displayID(t.ID), t.Priority, t.Status, t.Title)
 	}
 
 	return nil
 }
+
+// assumeClosed resolves partial IDs and marks them closed in the graph for a
+// what-if listing, noting the assumption on stderr
+func assumeClosed(cmd *cobra.Command, graph *deptree.DependencyGraph, partials []string) error {
+	if len(partials) == 0 {
+		return nil
+	}
+	ids, err := store.ResolveIDs(partials)
+	if err != nil {
+		return err
+	}
+	graph.AssumeClosed(ids)
+	fmt.Fprintf(cmd.ErrOrStderr(), "Assuming closed: %s\n", strings.Join(ids, ", "))
+	return nil
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"strings"
 	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
 )
 
 // TestReadyCommand tests the ready command
@@ -483,3 +485,65 @@ func TestReadyInProgressStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestAssumeClosed(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	blocker, _ := ctx.exec("new", "Blocker")
+	blocker = strings.TrimSpace(blocker)
+	dependant, _ := ctx.exec("new", "Dependant")
+	dependant = strings.TrimSpace(dependant)
+	ctx.exec("dep", dependant, blocker)
+
+	output, _ := ctx.exec("ready")
+	if strings.Contains(output, dependant) {
+		t.Fatalf("dependant should not be ready yet, got:\n%s", output)
+	}
+
+	output, err := ctx.exec("ready", "--assume-closed", blocker)
+	if err != nil {
+		t.Fatalf("ready --assume-closed error: %v", err)
+	}
+	if !strings.Contains(output, dependant) {
+		t.Errorf("dependant should be ready when its blocker is assumed closed, got:\n%s", output)
+	}
+	if strings.Contains(output, blocker+" ") {
+		t.Errorf("assumed-closed ticket should not be listed as ready, got:\n%s", output)
+	}
+
+	output, _ = ctx.exec("blocked", "--assume-closed", blocker)
+	if strings.Contains(output, dependant) {
+		t.Errorf("dependant should not be blocked when its blocker is assumed closed, got:\n%s", output)
+	}
+
+	if tk, _ := ctx.store().Get(blocker); tk.Status != ticket.StatusOpen {
+		t.Errorf("blocker status = %v, want it unchanged", tk.Status)
+	}
+}
+
+func TestReadyMatchesBlockedFormat(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	for _, id := range []string{"tk-a1b2", "tk-c3d4"} {
+		if err := ctx.store().Create(&ticket.Ticket{ID: id, Status: "open", Type: "task", Priority: 1, Title: "Fixture"}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	ctx.exec("dep", "tk-c3d4", "tk-a1b2")
+
+	ready, err := ctx.exec("--short-ids", "ready")
+	if err != nil {
+		t.Fatalf("ready error: %v", err)
+	}
+	blocked, _ := ctx.exec("--short-ids", "blocked")
+
+	// Both list short IDs in the same "id [P#][status] - title" layout
+	if want := "2        [P1][open] - Fixture\n"; ready != want {
+		t.Errorf("ready = %q, want %q", ready, want)
+	}
+	if want := "4        [P1][open] - Fixture <- [2]\n"; blocked != want {
+		t.Errorf("blocked = %q, want %q", blocked, want)
+	}
+}
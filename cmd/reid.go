@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var reidCmd = &cobra.Command{
+	Use:     "reid <id> [new-id] [--auto]",
+	Aliases: []string{"mv"},
+	Short:   "Change a ticket's ID and update references to it",
+	Long: `Change a ticket's ID. The file is renamed and every deps, links and
+parent reference to the old ID in other tickets is rewritten. Refuses if
+the new ID is already taken. Also available as 'tk mv <old> <new>'.
+
+Use --auto instead of giving a new ID to generate one from the ticket's
+current title, e.g. after a rename: the prefix is the first letter of each
+word of the title, followed by a fresh random suffix. Add --keep-suffix to
+reuse the old ID's suffix instead.
+  tk rename abc-1x2y Fix login redirect
+  tk reid abc-1x2y --auto               # flr-8k3p
+  tk reid abc-1x2y --auto --keep-suffix # flr-1x2y`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runReid,
+}
+
+var (
+	reidAuto       bool
+	reidKeepSuffix bool
+)
+
+func init() {
+	rootCmd.AddCommand(reidCmd)
+	reidCmd.Flags().BoolVar(&reidAuto, "auto", false, "Generate the new ID from the ticket's title")
+	reidCmd.Flags().BoolVar(&reidKeepSuffix, "keep-suffix", false, "With --auto, keep the old ID's suffix instead of generating one")
+}
+
+func runReid(cmd *cobra.Command, args []string) error {
+	if reidAuto == (len(args) == 2) {
+		return fmt.Errorf("give either a new ID or --auto")
+	}
+	if reidKeepSuffix && !reidAuto {
+		return fmt.Errorf("--keep-suffix requires --auto")
+	}
+
+	t, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	var newID string
+	if reidAuto {
+		newID, err = titleID(t)
+		if err != nil {
+			return err
+		}
+	} else {
+		newID = args[1]
+		if err := validateNewID(newID); err != nil {
+			return err
+		}
+	}
+
+	if newID == t.ID {
+		fmt.Printf("%s already has ID %s\n", t.ID, newID)
+		return nil
+	}
+
+	updated, err := reidTicket(t, newID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Renamed %s -> %s (%d reference(s) updated)\n", t.ID, newID, updated)
+	return nil
+}
+
+// titleID generates an unused ID for the ticket from its title, keeping the
+// old suffix with --keep-suffix
+func titleID(t *ticket.Ticket) (string, error) {
+	prefix := ticket.TitlePrefix(t.Title)
+	if prefix == "" {
+		return "", fmt.Errorf("cannot generate an ID from title %q", t.Title)
+	}
+
+	if reidKeepSuffix {
+		suffix := t.ID
+		if i := strings.LastIndex(t.ID, "-"); i >= 0 {
+			suffix = t.ID[i+1:]
+		}
+		id := prefix + "-" + suffix
+		if id != t.ID && idExists(id) {
+			return "", fmt.Errorf("ticket %s already exists", id)
+		}
+		return id, nil
+	}
+
+	// Retry on collisions, as for new
+	maxRetries := 10
+	for i := 0; i < maxRetries; i++ {
+		id := ticket.GenerateTitleID(t.Title)
+		if !idExists(id) {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate unique ticket ID after %d attempts", maxRetries)
+}
+
+// validateNewID checks that an explicit new ID is usable and not taken
+func validateNewID(id string) error {
+	if id == "" || strings.ContainsAny(id, " \t\n/\\:,[]") {
+		return fmt.Errorf("invalid ticket ID %q", id)
+	}
+	if idExists(id) {
+		return fmt.Errorf("ticket %s already exists", id)
+	}
+	return nil
+}
+
+// idExists reports whether a ticket has exactly this ID
+func idExists(id string) bool {
+	resolved, err := store.ResolveID(id)
+	return err == nil && resolved == id
+}
+
+// reidTicket moves a ticket to a new ID, keeping its file content, and
+// rewrites references to it in other tickets. Returns the number of tickets
+// whose references were updated.
+func reidTicket(t *ticket.Ticket, newID string) (int, error) {
+	oldID := t.ID
+	_, content, err := store.ReadRaw(oldID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Create puts the file where the store expects the new ID; the raw
+	// content then replaces it so the original formatting is kept
+	moved := *t
+	moved.ID = newID
+	if err := store.Create(&moved); err != nil {
+		return 0, fmt.Errorf("creating %s: %w", newID, err)
+	}
+	if err := store.WriteRaw(newID, ticket.UpdateField(content, "id", newID)); err != nil {
+		return 0, err
+	}
+	if err := store.Delete(oldID); err != nil {
+		return 0, err
+	}
+
+	return rewriteReferences(oldID, newID)
+}
+
+// rewriteReferences replaces oldID with newID in the deps, links and parent
+// of every ticket, returning how many tickets changed
+func rewriteReferences(oldID, newID string) (int, error) {
+	tickets, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, t := range tickets {
+		fields := make(map[string]string)
+
+		deps := append([]string{}, t.Deps...)
+		changed := false
+		for i, dep := range deps {
+			if dep == oldID {
+				deps[i] = newID
+				changed = true
+			}
+		}
+		if changed {
+			fields["deps"] = formatDepsArray(deps)
+		}
+
+		links := append([]string{}, t.Links...)
+		changed = false
+		for i, entry := range links {
+			if link := ticket.ParseLink(entry); link.ID == oldID {
+				link.ID = newID
+				links[i] = link.String()
+				changed = true
+			}
+		}
+		if changed {
+			fields["links"] = formatLinksArray(links)
+		}
+
+		if t.Parent == oldID {
+			fields["parent"] = newID
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+		if _, err := store.UpdateFields(t.ID, fields); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
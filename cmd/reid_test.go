@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// TestReidAuto tests regenerating an ID from the title after a rename
+func TestReidAuto(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Old title")
+	id = strings.TrimSpace(id)
+	dependant, _ := ctx.exec("new", "Dependant")
+	dependant = strings.TrimSpace(dependant)
+	linked, _ := ctx.exec("new", "Linked")
+	linked = strings.TrimSpace(linked)
+	child, _ := ctx.exec("new", "Child", "--parent", id)
+	child = strings.TrimSpace(child)
+
+	ctx.exec("dep", dependant, id)
+	ctx.exec("link", id, linked, "--rel", "duplicates")
+	ctx.exec("rename", id, "Fix login redirect")
+
+	output, err := ctx.exec("reid", id, "--auto", "--keep-suffix")
+	if err != nil {
+		t.Fatalf("reid failed: %v", err)
+	}
+	newID := "flr-" + id[strings.LastIndex(id, "-")+1:]
+	if !strings.Contains(output, id+" -> "+newID+" (3 reference(s) updated)") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	s := ctx.store()
+	tk, err := s.Get(newID)
+	if err != nil {
+		t.Fatalf("new ID not found: %v", err)
+	}
+	if tk.Title != "Fix login redirect" || len(tk.Links) != 1 || tk.Links[0] != linked+":duplicates" {
+		t.Errorf("ticket content not kept: %+v", tk)
+	}
+	if _, err := s.ResolveID(id); err == nil {
+		t.Errorf("old ID %s should be gone", id)
+	}
+
+	if d, _ := s.Get(dependant); len(d.Deps) != 1 || d.Deps[0] != newID {
+		t.Errorf("dependant deps = %v, want [%s]", d.Deps, newID)
+	}
+	if l, _ := s.Get(linked); len(l.Links) != 1 || l.Links[0] != newID+":duplicated-by" {
+		t.Errorf("linked links = %v, want [%s:duplicated-by]", l.Links, newID)
+	}
+	if c, _ := s.Get(child); c.Parent != newID {
+		t.Errorf("child parent = %s, want %s", c.Parent, newID)
+	}
+}
+
+// TestReidAutoFreshSuffix tests that --auto without --keep-suffix generates a
+// new suffix
+func TestReidAutoFreshSuffix(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Write the docs")
+	id = strings.TrimSpace(id)
+
+	if _, err := ctx.exec("reid", id, "--auto"); err != nil {
+		t.Fatalf("reid failed: %v", err)
+	}
+	tickets, _ := ctx.store().List()
+	if len(tickets) != 1 || !strings.HasPrefix(tickets[0].ID, "wtd-") {
+		t.Errorf("expected a single wtd- ticket, got %v", tickets)
+	}
+}
+
+// TestReidExplicitID tests giving the new ID and its validation
+func TestReidExplicitID(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "First")
+	id = strings.TrimSpace(id)
+	other, _ := ctx.exec("new", "Second")
+	other = strings.TrimSpace(other)
+
+	if _, err := ctx.exec("reid", id, other); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected collision error, got: %v", err)
+	}
+	if _, err := ctx.exec("reid", id); err == nil {
+		t.Error("expected error without a new ID or --auto")
+	}
+	if _, err := ctx.exec("reid", id, "new-1"); err != nil {
+		t.Fatalf("reid failed: %v", err)
+	}
+	if _, err := ctx.store().Get("new-1"); err != nil {
+		t.Errorf("ticket should exist under new-1: %v", err)
+	}
+}
+
+// TestMvNoDanglingReferences - Critical: no reference to the old ID survives
+func TestMvNoDanglingReferences(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// A is depended on, linked to and parent of other tickets
+	idA, _ := ctx.exec("new", "Ticket A")
+	idA = strings.TrimSpace(idA)
+	idB, _ := ctx.exec("new", "Ticket B")
+	idB = strings.TrimSpace(idB)
+	idC, _ := ctx.exec("new", "Ticket C", "--parent", idA)
+	idC = strings.TrimSpace(idC)
+
+	ctx.exec("dep", idB, idA)
+	ctx.exec("link", idA, idB, idC)
+
+	if _, err := ctx.exec("mv", idA, idB); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("mv onto an existing ID should fail, got: %v", err)
+	}
+	if _, err := ctx.store().Get(idA); err != nil {
+		t.Fatalf("refused mv should leave %s in place: %v", idA, err)
+	}
+
+	if _, err := ctx.exec("mv", idA, "moved-1"); err != nil {
+		t.Fatalf("mv failed: %v", err)
+	}
+	if _, err := ctx.store().Get("moved-1"); err != nil {
+		t.Fatalf("ticket should exist under moved-1: %v", err)
+	}
+	if _, err := ctx.store().Get(idA); err == nil {
+		t.Errorf("ticket should no longer exist under %s", idA)
+	}
+
+	allTickets, _ := ctx.store().List()
+	for _, tkt := range allTickets {
+		for _, depID := range tkt.Deps {
+			if depID == idA {
+				t.Errorf("ticket %s still has old ID %s in deps", tkt.ID, idA)
+			}
+		}
+		for _, entry := range tkt.Links {
+			if ticket.ParseLink(entry).ID == idA {
+				t.Errorf("ticket %s still has old ID %s in links", tkt.ID, idA)
+			}
+		}
+		if tkt.Parent == idA {
+			t.Errorf("ticket %s still has old ID %s as parent", tkt.ID, idA)
+		}
+	}
+
+	ticketB, _ := ctx.store().Get(idB)
+	if len(ticketB.Deps) != 1 || ticketB.Deps[0] != "moved-1" {
+		t.Errorf("B.Deps = %v, want [moved-1]", ticketB.Deps)
+	}
+	ticketC, _ := ctx.store().Get(idC)
+	if ticketC.Parent != "moved-1" {
+		t.Errorf("C.Parent = %q, want moved-1", ticketC.Parent)
+	}
+	moved, _ := ctx.store().Get("moved-1")
+	if len(moved.Links) != 2 {
+		t.Errorf("moved ticket should keep its links, got %v", moved.Links)
+	}
+}
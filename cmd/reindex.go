@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the ticket ID index",
+	Long: `Rebuild .tickets/.index, which lists every ticket file so that partial
+IDs resolve without scanning the directory. Once created, the index is kept
+up to date by tk itself; it is ignored whenever the directory has changed
+behind its back, so resolution falls back to a full scan and never gives a
+different answer. Delete the file to stop using an index.`,
+	Args: cobra.NoArgs,
+	RunE: runReindex,
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	indexer, ok := store.(ticket.Indexer)
+	if !ok {
+		return fmt.Errorf("the ticket store does not support an index")
+	}
+
+	n, err := indexer.Reindex()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Indexed %d ticket(s) in %s\n", n, filepath.Join(store.Dir(), ticket.IndexFile))
+	return nil
+}
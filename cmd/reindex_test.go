@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestReindexCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Indexed ticket")
+	id = strings.TrimSpace(id)
+	ctx.exec("new", "Another ticket")
+
+	output, err := ctx.exec("reindex")
+	if err != nil {
+		t.Fatalf("reindex error: %v", err)
+	}
+	if !strings.Contains(output, "Indexed 2 ticket(s)") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	index := filepath.Join(ctx.store().Dir(), ticket.IndexFile)
+	if _, err := os.Stat(index); err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+
+	// Commands keep resolving partial IDs as before
+	output, err = ctx.exec("show", "--no-indicators", id[len(id)-4:])
+	if err != nil {
+		t.Fatalf("show with a partial ID failed: %v", err)
+	}
+	if !strings.Contains(output, "Indexed ticket") {
+		t.Errorf("show resolved the wrong ticket:\n%s", output)
+	}
+}
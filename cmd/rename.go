@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lo5/tk/internal/config"
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <id> <title>",
+	Short: "Change a ticket's title",
+	Long: `Change the title of a ticket, leaving the rest of the file untouched.
+Remaining arguments are joined with spaces, as for new.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	title := strings.Join(args[1:], " ")
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("title cannot be empty")
+	}
+
+	cfg, err := config.Load(store.Dir())
+	if err != nil {
+		return err
+	}
+	if err := cfg.CheckTitle(title); err != nil {
+		return err
+	}
+
+	id, content, err := store.ReadRaw(args[0])
+	if err != nil {
+		return err
+	}
+	if err := store.WriteRaw(id, ticket.UpdateTitle(content, title)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Renamed %s\n", id)
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenameCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Old title", "-d", "Body text")
+	id = strings.TrimSpace(id)
+
+	if _, err := ctx.exec("rename", id, "New", "title"); err != nil {
+		t.Fatalf("rename error: %v", err)
+	}
+
+	tk, _ := ctx.store().Get(id)
+	if tk.Title != "New title" {
+		t.Errorf("title = %q, want %q", tk.Title, "New title")
+	}
+	if tk.Body != "Body text" {
+		t.Errorf("body = %q, want it preserved", tk.Body)
+	}
+}
+
+func TestMaxTitleLength(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	if _, err := ctx.exec("config", "set", "max_title_length", "20"); err != nil {
+		t.Fatalf("config set error: %v", err)
+	}
+
+	long := strings.Repeat("x", 30)
+	output, err := ctx.exec("new", long)
+	if err == nil {
+		t.Fatal("expected 30-character title to be rejected")
+	}
+	if !strings.Contains(output, "title is 30 characters, exceeding max_title_length of 20") {
+		t.Errorf("expected clear message, got: %s", output)
+	}
+	if tickets, _ := ctx.store().List(); len(tickets) != 0 {
+		t.Errorf("no ticket should be created, got %d", len(tickets))
+	}
+
+	id, err := ctx.exec("new", "Short title")
+	if err != nil {
+		t.Fatalf("short title rejected: %v", err)
+	}
+	id = strings.TrimSpace(id)
+
+	if _, err := ctx.exec("rename", id, long); err == nil {
+		t.Error("expected rename to a 30-character title to be rejected")
+	}
+	if tk, _ := ctx.store().Get(id); tk.Title != "Short title" {
+		t.Errorf("title = %q, want unchanged", tk.Title)
+	}
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/lo5/tk/internal/config"
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -17,20 +20,43 @@ Refuses deletion if:
   - Other tickets have it as a parent (in their parent field)
   - Ticket has links (bidirectional links field) without --force
 
-Use --force to remove links automatically (still refuses if dependants/children exist).`,
+Use --force to remove links automatically (still refuses if dependants/children exist).
+
+Use --impact to preview what deleting the ticket would touch without deleting
+anything: the links --force would clean, and any dependants or children that
+would block deletion. Add --with-dependants to list the full transitive set of
+tickets depending on it.
+
+With the trash enabled (tk config set trash true) the ticket is moved to
+.tickets/.trash/ instead, and 'tk undo' restores it.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRm,
 }
 
-var rmForce bool
+var (
+	rmForce          bool
+	rmImpact         bool
+	rmWithDependants bool
+)
 
 func init() {
 	rootCmd.AddCommand(rmCmd)
 	rmCmd.Flags().BoolVarP(&rmForce, "force", "f", false,
 		"Force deletion by removing links (still refuses if dependants/children exist)")
+	rmCmd.Flags().BoolVar(&rmImpact, "impact", false, "Preview what deletion would touch without deleting")
+	rmCmd.Flags().BoolVar(&rmWithDependants, "with-dependants", false, "With --impact, list all transitive dependants")
 }
 
 func runRm(cmd *cobra.Command, args []string) error {
+	if rmWithDependants && !rmImpact {
+		return fmt.Errorf("--with-dependants requires --impact")
+	}
+
+	cfg, err := config.Load(store.Dir())
+	if err != nil {
+		return err
+	}
+
 	// 1. Get target ticket
 	target, err := store.Get(args[0])
 	if err != nil {
@@ -43,6 +69,11 @@ func runRm(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if rmImpact {
+		printRmImpact(target, allTickets, rmWithDependants)
+		return nil
+	}
+
 	// 3. Find blocking relationships
 	dependants := findDependants(allTickets, target.ID)
 	children := findChildren(allTickets, target.ID)
@@ -67,7 +98,7 @@ func runRm(cmd *cobra.Command, args []string) error {
 		}
 
 		var linkedTickets []*ticket.Ticket
-		for _, linkID := range target.Links {
+		for _, linkID := range target.LinkIDs() {
 			if linked, ok := ticketMap[linkID]; ok {
 				linkedTickets = append(linkedTickets, linked)
 			}
@@ -80,7 +111,7 @@ func runRm(cmd *cobra.Command, args []string) error {
 	// 6. Remove links if --force is used and links exist
 	linksRemoved := 0
 	if rmForce && len(target.Links) > 0 {
-		for _, linkedID := range target.Links {
+		for _, linkedID := range target.LinkIDs() {
 			linkedTicket, err := store.Get(linkedID)
 			if err != nil {
 				// Skip if linked ticket doesn't exist (orphaned link)
@@ -90,7 +121,7 @@ func runRm(cmd *cobra.Command, args []string) error {
 			// Remove target.ID from linked ticket's Links array
 			var newLinks []string
 			for _, l := range linkedTicket.Links {
-				if l != target.ID {
+				if ticket.ParseLink(l).ID != target.ID {
 					newLinks = append(newLinks, l)
 				}
 			}
@@ -107,7 +138,7 @@ func runRm(cmd *cobra.Command, args []string) error {
 	}
 
 	// 7. Delete the ticket
-	if err := store.Delete(target.ID); err != nil {
+	if err := removeTicket(cfg, target.ID, time.Now()); err != nil {
 		return err
 	}
 
@@ -117,6 +148,67 @@ func runRm(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf("Deleted ticket: %s\n", target.ID)
 	}
+	if cfg.Trash {
+		fmt.Println("Moved to trash; run 'tk undo' to restore.")
+	}
 
 	return nil
 }
+
+// printRmImpact reports what deleting a ticket would touch, without deleting
+func printRmImpact(target *ticket.Ticket, allTickets []*ticket.Ticket, withDependants bool) {
+	ticketMap := make(map[string]*ticket.Ticket)
+	for _, t := range allTickets {
+		ticketMap[t.ID] = t
+	}
+
+	dependants := findDependants(allTickets, target.ID)
+	children := findChildren(allTickets, target.ID)
+
+	fmt.Printf("Impact of deleting %s [%s] %s\n", target.ID, target.Status, target.Title)
+
+	switch {
+	case len(dependants) > 0:
+		fmt.Println("\nBlocked: ticket has dependants")
+	case len(children) > 0:
+		fmt.Println("\nBlocked: ticket has children")
+	case len(target.Links) > 0:
+		fmt.Println("\nRequires --force: ticket has links")
+	default:
+		fmt.Println("\nSafe to delete")
+	}
+
+	if len(target.Links) > 0 {
+		var linked []*ticket.Ticket
+		var missing []string
+		for _, id := range target.LinkIDs() {
+			if t, ok := ticketMap[id]; ok {
+				linked = append(linked, t)
+			} else {
+				missing = append(missing, id)
+			}
+		}
+		fmt.Printf("\nLinks removed with --force (%d):\n", len(target.Links))
+		if len(linked) > 0 {
+			fmt.Println(formatBlockingTickets(linked))
+		}
+		for _, id := range missing {
+			fmt.Printf("  - %s [missing]\n", id)
+		}
+	}
+
+	if len(dependants) > 0 {
+		fmt.Printf("\nDependants (%d):\n%s\n", len(dependants), formatBlockingTickets(dependants))
+	}
+	if len(children) > 0 {
+		fmt.Printf("\nChildren (%d):\n%s\n", len(children), formatBlockingTickets(children))
+	}
+
+	if withDependants {
+		all := deptree.NewGraph(allTickets).TransitiveDependants(target.ID)
+		fmt.Printf("\nTransitive dependants (%d):\n", len(all))
+		if len(all) > 0 {
+			fmt.Println(formatBlockingTickets(all))
+		}
+	}
+}
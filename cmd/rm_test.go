@@ -818,3 +818,60 @@ func TestRmOutput(t *testing.T) {
 		}
 	})
 }
+
+func TestRmImpact(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// B depends on A, C depends on B; A is linked to D
+	idA, _ := ctx.exec("new", "Ticket A")
+	idA = strings.TrimSpace(idA)
+	idB, _ := ctx.exec("new", "Ticket B")
+	idB = strings.TrimSpace(idB)
+	idC, _ := ctx.exec("new", "Ticket C")
+	idC = strings.TrimSpace(idC)
+	idD, _ := ctx.exec("new", "Ticket D")
+	idD = strings.TrimSpace(idD)
+	ctx.exec("dep", idB, idA)
+	ctx.exec("dep", idC, idB)
+	ctx.exec("link", idA, idD)
+
+	t.Run("lists links and direct dependants", func(t *testing.T) {
+		output, err := ctx.exec("rm", "--impact", idA)
+		if err != nil {
+			t.Fatalf("rm --impact error: %v", err)
+		}
+		for _, want := range []string{
+			"Blocked: ticket has dependants",
+			"Links removed with --force (1):\n  - " + idD,
+			"Dependants (1):\n  - " + idB,
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected %q in output, got:\n%s", want, output)
+			}
+		}
+		if strings.Contains(output, "Transitive") {
+			t.Errorf("transitive set should need --with-dependants, got:\n%s", output)
+		}
+	})
+
+	t.Run("with dependants lists transitive set", func(t *testing.T) {
+		output, err := ctx.exec("rm", "--impact", "--with-dependants", idA)
+		if err != nil {
+			t.Fatalf("rm --impact error: %v", err)
+		}
+		if !strings.Contains(output, "Transitive dependants (2):\n  - "+idB+" [open] Ticket B\n  - "+idC) {
+			t.Errorf("expected B and C as transitive dependants, got:\n%s", output)
+		}
+		rmWithDependants = false
+	})
+
+	t.Run("nothing is deleted", func(t *testing.T) {
+		if _, err := ctx.store().Get(idA); err != nil {
+			t.Errorf("ticket should still exist: %v", err)
+		}
+		if tk, _ := ctx.store().Get(idD); len(tk.Links) != 1 {
+			t.Errorf("links should be untouched, got %v", tk.Links)
+		}
+	})
+}
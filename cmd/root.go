@@ -1,17 +1,31 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/lo5/tk/internal/config"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
 
 var (
 	ticketsDir string
-	store      *ticket.FileStore
+	jsonErrors bool
+	shortIDs   bool
+	store      ticket.Store
+
+	// abbreviations maps IDs to their shortest unique suffix with --short-ids
+	abbreviations map[string]string
 )
 
+// openStore opens the ticket store for the tickets directory. Tests may
+// replace it to run commands against another backend.
+var openStore = func(dir string) (ticket.Store, error) {
+	return config.OpenStore(dir)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "tk",
 	Short: "Minimal ticket system with dependency tracking",
@@ -19,17 +33,110 @@ var rootCmd = &cobra.Command{
 
 Tickets are stored as markdown files with YAML frontmatter in .tickets/
 Supports partial ID matching (e.g., 'tk show 5c4' matches 'nw-5c46')`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		store = ticket.NewFileStore(ticketsDir)
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Structured errors replace cobra's human-readable error and usage
+		// output; bulk script lines report errors without usage
+		cmd.Root().SilenceErrors = jsonErrors
+		cmd.Root().SilenceUsage = jsonErrors || inBulk
+		if cmd != cmd.Root() {
+			// Clear silencing left over from a previous silent failure
+			cmd.SilenceErrors = false
+			cmd.SilenceUsage = false
+		}
+
+		// A broken config could change where tickets are stored, such as
+		// whether they are sharded, so nothing touching tickets runs until it
+		// is fixed. Help, completion and config itself still work, so the
+		// config can be inspected and repaired.
+		s, err := openStore(ticketsDir)
+		if err != nil {
+			if touchesTickets(cmd) {
+				cmd.SilenceUsage = true
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+			if s == nil {
+				s = ticket.NewFileStore(ticketsDir)
+			}
+		}
+		store = s
+
+		// Unknown keys are usually typos that would otherwise be ignored.
+		// Bulk script lines skip this, as the bulk command already warned.
+		if cfg, err := config.Load(store.Dir()); err == nil && !inBulk {
+			for _, key := range cfg.Unknown {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: unknown config key '%s' in %s\n", key, config.Path(store.Dir()))
+			}
+		}
+
+		abbreviations = nil
+		if shortIDs {
+			if modTimes, err := store.ModTimes(); err == nil {
+				ids := make([]string, 0, len(modTimes))
+				for id := range modTimes {
+					ids = append(ids, id)
+				}
+				abbreviations = ticket.ShortIDs(ids)
+			}
+		}
+		return nil
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// Files that couldn't be read were skipped; say so rather than
+		// letting the tickets silently disappear
+		if reporter, ok := store.(ticket.UnreadableReporter); ok {
+			for _, u := range reporter.Unreadable() {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipped unreadable ticket file %s: %v\n", u.Path, u.Err)
+			}
+		}
+	},
+}
+
+// touchesTickets reports whether a command reads or writes tickets, rather
+// than only printing help or completions or working on the config file
+func touchesTickets(cmd *cobra.Command) bool {
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		switch c.Name() {
+		case "help", "completion", "config", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return false
+		}
+	}
+	return true
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		var silent silentExit
+		if errors.As(err, &silent) {
+			os.Exit(silent.code)
+		}
+		if jsonErrors {
+			os.Exit(writeJSONError(os.Stderr, err))
+		}
+		os.Exit(exitError)
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&ticketsDir, "dir", ticket.DefaultTicketsDir, "tickets directory")
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json-errors", false, "Print errors as JSON to stderr")
+	rootCmd.PersistentFlags().BoolVar(&shortIDs, "short-ids", false, "Display the shortest unambiguous suffix of each ID")
+}
+
+// displayID returns the ID as it should be shown to the user, abbreviated
+// when --short-ids is set
+func displayID(id string) string {
+	if short, ok := abbreviations[id]; ok {
+		return short
+	}
+	return id
+}
+
+// displayIDs applies displayID to each ID
+func displayIDs(ids []string) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = displayID(id)
+	}
+	return out
 }
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/config"
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// countingStore wraps a Store, counting List calls. Embedding the interface
+// hides the FileStore's optional capabilities such as the trash.
+type countingStore struct {
+	ticket.Store
+	lists int
+}
+
+func (s *countingStore) List() ([]*ticket.Ticket, error) {
+	s.lists++
+	return s.Store.List()
+}
+
+func TestCommandsUseStoreInterface(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Through the interface")
+	id = strings.TrimSpace(id)
+
+	wrapped := &countingStore{Store: ticket.NewFileStore(ctx.ticketsDir)}
+	origOpenStore := openStore
+	openStore = func(string) (ticket.Store, error) { return wrapped, nil }
+	defer func() { openStore = origOpenStore }()
+
+	output, err := ctx.exec("ls")
+	if err != nil {
+		t.Fatalf("ls error: %v", err)
+	}
+	if !strings.Contains(output, id) || wrapped.lists == 0 {
+		t.Errorf("ls did not list through the store (lists=%d):\n%s", wrapped.lists, output)
+	}
+
+	if _, err := ctx.exec("undo"); err == nil || !strings.Contains(err.Error(), "does not support the trash") {
+		t.Errorf("undo without a trash-capable store error = %v", err)
+	}
+}
+
+// TestBrokenConfigStopsCommands tests that a config that fails to parse is
+// reported instead of falling back to a flat store, which would hide every
+// ticket of a sharded one
+func TestBrokenConfigStopsCommands(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	if err := os.MkdirAll(ctx.ticketsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.Path(ctx.ticketsDir), []byte("sharded: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	id, err := ctx.exec("new", "Sharded ticket")
+	if err != nil {
+		t.Fatalf("new error: %v", err)
+	}
+	id = strings.TrimSpace(id)
+
+	if err := os.WriteFile(config.Path(ctx.ticketsDir), []byte("sharded: [oops\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"ls"}, {"show", id}, {"new", "Lost ticket"}} {
+		output, err := ctx.exec(args...)
+		if err == nil || !strings.Contains(output, "parsing config") {
+			t.Errorf("%s with a broken config: err = %v, output:\n%s", args[0], err, output)
+		}
+		if strings.Contains(output, "Usage:") {
+			t.Errorf("%s should not print usage for a broken config:\n%s", args[0], output)
+		}
+	}
+
+	// The config can still be inspected and repaired
+	for _, args := range [][]string{{"help"}, {"completion", "bash"}, {"config", "get", "trash"}} {
+		output, _ := ctx.exec(args...)
+		if !strings.Contains(output, "Warning: parsing config") {
+			t.Errorf("%s with a broken config should only warn, got:\n%s", args[0], output)
+		}
+	}
+	os.WriteFile(config.Path(ctx.ticketsDir), []byte("sharded: [true]\n"), 0644)
+	if output, err := ctx.exec("config", "set", "sharded", "true"); err != nil {
+		t.Fatalf("config set should repair the config: %v\n%s", err, output)
+	}
+	if output, err := ctx.exec("show", id); err != nil {
+		t.Errorf("show after repairing the config: %v\n%s", err, output)
+	}
+}
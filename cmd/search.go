@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <text> [--include-body]",
+	Short: "Search tickets by text",
+	Long: `Search tickets for text (case-insensitive unless --case-sensitive).
+Each match is printed with the first matching line of the ticket file.
+
+By default only titles and frontmatter values are searched, which avoids
+reading ticket bodies. Field names are not searched, so "tk search deps"
+finds tickets mentioning deps rather than every ticket. Use --include-body
+to also search descriptions, notes and other body sections.
+
+Use --regex to treat the text as a Go regular expression, matched against
+one line at a time: a field's value, the title text, or a body line:
+  tk search --include-body --regex 'time ?out'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+var (
+	searchIncludeBody   bool
+	searchCaseSensitive bool
+	searchRegex         bool
+)
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().BoolVar(&searchIncludeBody, "include-body", false, "Also search ticket bodies")
+	searchCmd.Flags().BoolVar(&searchCaseSensitive, "case-sensitive", false, "Match case exactly")
+	searchCmd.Flags().BoolVar(&searchRegex, "regex", false, "Treat the text as a Go regular expression")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	results, err := store.Search(args[0], ticket.SearchOptions{
+		CaseSensitive: searchCaseSensitive,
+		Regex:         searchRegex,
+		HeadersOnly:   !searchIncludeBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	// A missing tickets directory has nothing to search
+	if _, err := os.Stat(store.Dir()); os.IsNotExist(err) {
+		return nil
+	}
+
+	printed := 0
+	for i, r := range results {
+		// Results are ordered by ID; show the first matching line of each
+		if i > 0 && results[i-1].ID == r.ID {
+			continue
+		}
+		t, err := store.Get(r.ID)
+		if err != nil {
+			// Skip malformed tickets
+			continue
+		}
+		fmt.Printf("%-8s [%s] - %s\n", t.ID, t.Status, t.Title)
+		fmt.Printf("    %s\n", r.Line)
+		printed++
+	}
+
+	if printed == 0 {
+		fmt.Println("No matching tickets")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	titleID, _ := ctx.exec("new", "Fix Widget rendering")
+	titleID = strings.TrimSpace(titleID)
+	bodyID, _ := ctx.exec("new", "Unrelated title", "-d", "The widget crashes on startup")
+	bodyID = strings.TrimSpace(bodyID)
+
+	t.Run("metadata only by default", func(t *testing.T) {
+		output, err := ctx.exec("search", "widget")
+		if err != nil {
+			t.Fatalf("search error: %v", err)
+		}
+		if !strings.Contains(output, titleID) {
+			t.Errorf("expected title match %s, got: %s", titleID, output)
+		}
+		if strings.Contains(output, bodyID) {
+			t.Errorf("body-only match %s should need --include-body, got: %s", bodyID, output)
+		}
+	})
+
+	t.Run("include body", func(t *testing.T) {
+		output, err := ctx.exec("search", "--include-body", "widget")
+		if err != nil {
+			t.Fatalf("search error: %v", err)
+		}
+		if !strings.Contains(output, titleID) || !strings.Contains(output, bodyID) {
+			t.Errorf("expected both %s and %s, got: %s", titleID, bodyID, output)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		output, err := ctx.exec("search", "nothing-like-this")
+		if err != nil {
+			t.Fatalf("search error: %v", err)
+		}
+		if !strings.Contains(output, "No matching tickets") {
+			t.Errorf("expected no-match message, got: %s", output)
+		}
+	})
+
+	t.Run("prints the first matching line", func(t *testing.T) {
+		output, err := ctx.exec("search", "--include-body", "CRASHES")
+		if err != nil {
+			t.Fatalf("search error: %v", err)
+		}
+		if !strings.Contains(output, bodyID) || !strings.Contains(output, "    The widget crashes on startup\n") {
+			t.Errorf("expected %s with its matching line, got: %s", bodyID, output)
+		}
+	})
+
+	t.Run("case sensitive", func(t *testing.T) {
+		output, err := ctx.exec("search", "--case-sensitive", "widget")
+		if err != nil {
+			t.Fatalf("search error: %v", err)
+		}
+		if strings.Contains(output, titleID) {
+			t.Errorf("'widget' should not match 'Widget' with --case-sensitive, got: %s", output)
+		}
+	})
+}
+
+func TestSearchRegex(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	timeout, _ := ctx.exec("new", "Request time out")
+	timeout = strings.TrimSpace(timeout)
+	other, _ := ctx.exec("new", "Timer drift")
+	other = strings.TrimSpace(other)
+
+	output, err := ctx.exec("search", "--regex", "^request time ?out$")
+	if err != nil {
+		t.Fatalf("search --regex error: %v", err)
+	}
+	if !strings.Contains(output, timeout) || strings.Contains(output, other) {
+		t.Errorf("expected only %s, got: %s", timeout, output)
+	}
+
+	if _, err := ctx.exec("search", "--regex", "(unclosed"); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestSearchMissingDirectory(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	output, err := ctx.exec("search", "anything")
+	if err != nil {
+		t.Fatalf("search error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("expected no output without a tickets directory, got: %q", output)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -12,16 +14,59 @@ import (
 var showCmd = &cobra.Command{
 	Use:   "show <id>",
 	Short: "Display a ticket",
-	Long:  "Display a ticket with its metadata, content, and relationships.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runShow,
+	Long: `Display a ticket with its metadata, content, and relationships.
+
+When the ticket has an estimate (taken as hours) or a ## Worklog section,
+an Effort section sums them up: the estimate, the total time logged and the
+time remaining.
+
+On a terminal, the ticket's status line and related tickets are marked as
+ready to work on (● or *) or blocked by unclosed dependencies (○ or -);
+closed tickets are unmarked. ASCII marks are used with --ascii or when the
+locale is not UTF-8; --no-indicators drops them.
+
+Use --relationships-only to print just the Blockers, Blocking, Children and
+Linked sections, skipping the frontmatter and body.
+
+Use --history to append a chronological timeline merging the body's
+## History section with the git log of the ticket file (when available).
+
+Use --copy-id to also copy the full ticket ID to the system clipboard
+(pbcopy, wl-copy, xclip or xsel).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShow,
 }
 
+var (
+	showFollowLinks bool
+	showHistory     bool
+	showCopyID      bool
+	showRelsOnly    bool
+	showNoIndic     bool
+	showASCII       bool
+)
+
 func init() {
 	rootCmd.AddCommand(showCmd)
+	showCmd.Flags().BoolVar(&showFollowLinks, "follow-links", false,
+		"Also summarize the relationships of directly related tickets (depth 1)")
+	showCmd.Flags().BoolVar(&showHistory, "history", false,
+		"Append a timeline merging the ## History section and git log")
+	showCmd.Flags().BoolVar(&showCopyID, "copy-id", false,
+		"Copy the full ticket ID to the system clipboard")
+	showCmd.Flags().BoolVar(&showRelsOnly, "relationships-only", false,
+		"Print only the relationship sections, without frontmatter or body")
+	showCmd.Flags().BoolVar(&showNoIndic, "no-indicators", false,
+		"Don't mark tickets as ready or blocked")
+	showCmd.Flags().BoolVar(&showASCII, "ascii", false,
+		"Use ASCII readiness marks")
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
+	if showRelsOnly && showHistory {
+		return fmt.Errorf("--relationships-only cannot be combined with --history")
+	}
+
 	target, err := store.Get(args[0])
 	if err != nil {
 		return err
@@ -33,103 +78,234 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Build lookup maps
-	ticketMap := make(map[string]*ticket.Ticket)
-	for _, t := range allTickets {
-		ticketMap[t.ID] = t
+	graph := deptree.NewGraph(allTickets)
+
+	// Readiness marks, omitted for closed tickets
+	mark := func(t *ticket.Ticket) string { return "" }
+	if showIndicators(showNoIndic) {
+		glyphs := readinessGlyphs(showASCII)
+		mark = func(t *ticket.Ticket) string {
+			if m := readinessIndicator(graph, t, glyphs); m != " " {
+				return m + " "
+			}
+			return ""
+		}
 	}
 
-	// Find relationships
-	var blockers []*ticket.Ticket // Unclosed deps of this ticket
-	var blocking []*ticket.Ticket // Tickets that have this as a dep (not closed)
-	var children []*ticket.Ticket // Tickets with this as parent
-	var linked []*ticket.Ticket   // Tickets in links array
+	// Output the ticket
+	if !showRelsOnly {
+		printTicket(target, graph, mark)
+		printEffort(target)
+	}
 
-	// Blockers: unclosed deps
-	for _, depID := range target.Deps {
-		if dep, ok := ticketMap[depID]; ok {
-			if dep.Status != ticket.StatusClosed {
-				blockers = append(blockers, dep)
-			}
+	// Print relationship sections
+	missing := printRelationships(target, graph, mark)
+	if target.Parent != "" {
+		if _, ok := graph.Ticket(target.Parent); !ok {
+			missing++
 		}
 	}
 
-	// Blocking: tickets that depend on this one (and are not closed)
-	for _, t := range allTickets {
-		if t.ID == target.ID {
-			continue
+	if showFollowLinks {
+		printNeighborhood(target, graph)
+	}
+
+	if showHistory {
+		path, err := store.Path(target.ID)
+		if err != nil {
+			return err
 		}
-		for _, depID := range t.Deps {
-			if depID == target.ID && t.Status != ticket.StatusClosed {
-				blocking = append(blocking, t)
-				break
-			}
+		printHistory(path, target.Body)
+	}
+
+	if showCopyID {
+		if err := clipboard.Copy(target.ID); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "\nWarning: could not copy ID to clipboard: %v\n", err)
 		}
 	}
 
-	// Children: tickets with this as parent
-	for _, t := range allTickets {
-		if t.Parent == target.ID {
-			children = append(children, t)
+	if missing > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "\nWarning: %d missing reference(s). Run 'tk prune' to review and remove them.\n", missing)
+	}
+
+	return nil
+}
+
+// relatedTicket is an entry in a relationship section.
+// The ticket is nil when the referenced ID no longer exists.
+type relatedTicket struct {
+	id     string
+	ticket *ticket.Ticket
+	rel    string // Relationship type of a typed link
+}
+
+// printEffort prints the Effort section summarizing the estimate and the
+// ## Worklog total, if the ticket has either
+func printEffort(t *ticket.Ticket) {
+	estimate, hasEstimate := time.Duration(t.Estimate)*time.Hour, t.Estimate > 0
+	logged, hasWorklog := ticket.Worklog(t.Body)
+	if !hasEstimate && !hasWorklog {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("## Effort")
+	fmt.Println()
+	if hasEstimate {
+		fmt.Printf("- Estimate: %s\n", ticket.FormatEffort(estimate))
+	}
+	if hasWorklog {
+		fmt.Printf("- Logged: %s\n", ticket.FormatEffort(logged))
+	}
+	if hasEstimate && hasWorklog {
+		if remaining := estimate - logged; remaining >= 0 {
+			fmt.Printf("- Remaining: %s\n", ticket.FormatEffort(remaining))
+		} else {
+			fmt.Printf("- Remaining: 0h (over estimate by %s)\n", ticket.FormatEffort(-remaining))
 		}
 	}
+}
 
-	// Linked: tickets in links array
-	for _, linkID := range target.Links {
-		if linked_t, ok := ticketMap[linkID]; ok {
-			linked = append(linked, linked_t)
+// printRelationships prints the Blockers, Blocking, Children and Linked
+// sections for a ticket. Returns the number of dangling references shown.
+func printRelationships(target *ticket.Ticket, graph *deptree.DependencyGraph, mark func(*ticket.Ticket) string) int {
+	var blockers []relatedTicket // Unclosed or missing deps of this ticket
+	var blocking []relatedTicket // Tickets that have this as a dep (not closed)
+	var children []relatedTicket // Tickets with this as parent
+	var linked []relatedTicket   // Tickets in links array
+	missing := 0
+
+	// Blockers: unclosed or missing deps
+	for _, depID := range graph.Blockers(target.ID) {
+		dep, ok := graph.Ticket(depID)
+		if !ok {
+			missing++
 		}
+		blockers = append(blockers, relatedTicket{id: depID, ticket: dep})
 	}
 
-	// Output the ticket
-	printTicket(target, ticketMap)
+	// Blocking: tickets that depend on this one (and are not closed)
+	for _, t := range graph.Dependants(target.ID) {
+		if t.Status != ticket.StatusClosed {
+			blocking = append(blocking, relatedTicket{id: t.ID, ticket: t})
+		}
+	}
 
-	// Print relationship sections
-	if len(blockers) > 0 {
-		fmt.Println()
-		fmt.Println("## Blockers")
-		fmt.Println()
-		for _, b := range blockers {
-			fmt.Printf("- %s [%s] %s\n", b.ID, b.Status, b.Title)
+	// Children: tickets with this as parent
+	for _, t := range graph.Children(target.ID) {
+		children = append(children, relatedTicket{id: t.ID, ticket: t})
+	}
+
+	// Linked: tickets in links array
+	for _, entry := range target.Links {
+		link := ticket.ParseLink(entry)
+		l, ok := graph.Ticket(link.ID)
+		if !ok {
+			missing++
 		}
+		linked = append(linked, relatedTicket{id: link.ID, ticket: l, rel: link.Rel})
 	}
 
-	if len(blocking) > 0 {
-		fmt.Println()
-		fmt.Println("## Blocking")
-		fmt.Println()
-		for _, b := range blocking {
-			fmt.Printf("- %s [%s] %s\n", b.ID, b.Status, b.Title)
+	printRelationSection("Blockers", blockers, mark)
+	printRelationSection("Blocking", blocking, mark)
+	printRelationSection("Children", children, mark)
+	printRelationSection("Linked", linked, mark)
+
+	return missing
+}
+
+// printRelationSection prints a titled list of related tickets, if any
+func printRelationSection(title string, entries []relatedTicket, mark func(*ticket.Ticket) string) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("## %s\n", title)
+	fmt.Println()
+	for _, e := range entries {
+		rel := ""
+		if e.rel != "" {
+			rel = " (" + e.rel + ")"
+		}
+		if e.ticket == nil {
+			fmt.Printf("- %s [missing]%s\n", e.id, rel)
+			continue
 		}
+		fmt.Printf("- %s%s [%s] %s%s\n", mark(e.ticket), displayID(e.id), e.ticket.Status, e.ticket.Title, rel)
 	}
+}
 
-	if len(children) > 0 {
-		fmt.Println()
-		fmt.Println("## Children")
-		fmt.Println()
-		for _, c := range children {
-			fmt.Printf("- %s [%s] %s\n", c.ID, c.Status, c.Title)
+// printNeighborhood prints a one-level summary of the relationships of every
+// ticket directly linked to, depended on by, or depending on the target.
+// Neighbors' own relationships are listed but never expanded further.
+func printNeighborhood(target *ticket.Ticket, graph *deptree.DependencyGraph) {
+	var neighbors []*ticket.Ticket
+	seen := map[string]bool{target.ID: true}
+	addNeighbor := func(id string) {
+		if seen[id] {
+			return
+		}
+		if t, ok := graph.Ticket(id); ok {
+			seen[id] = true
+			neighbors = append(neighbors, t)
 		}
 	}
 
-	if len(linked) > 0 {
-		fmt.Println()
-		fmt.Println("## Linked")
-		fmt.Println()
-		for _, l := range linked {
-			fmt.Printf("- %s [%s] %s\n", l.ID, l.Status, l.Title)
+	for _, id := range target.LinkIDs() {
+		addNeighbor(id)
+	}
+	for _, id := range target.Deps {
+		addNeighbor(id)
+	}
+	for _, t := range graph.Dependants(target.ID) {
+		addNeighbor(t.ID)
+	}
+
+	if len(neighbors) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("## Neighborhood")
+	fmt.Println()
+	for _, n := range neighbors {
+		fmt.Printf("- %s [%s] %s\n", n.ID, n.Status, n.Title)
+		if len(n.Deps) > 0 {
+			fmt.Printf("    deps: %s\n", formatNeighborRefs(n.Deps, graph))
+		}
+		if len(n.Links) > 0 {
+			fmt.Printf("    links: %s\n", formatNeighborRefs(n.LinkIDs(), graph))
 		}
 	}
+}
 
-	return nil
+// formatNeighborRefs formats referenced IDs with their statuses on one line
+func formatNeighborRefs(ids []string, graph *deptree.DependencyGraph) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if t, ok := graph.Ticket(id); ok {
+			parts = append(parts, fmt.Sprintf("%s [%s]", id, t.Status))
+		} else {
+			parts = append(parts, id)
+		}
+	}
+	return strings.Join(parts, ", ")
 }
 
-func printTicket(t *ticket.Ticket, ticketMap map[string]*ticket.Ticket) {
+func printTicket(t *ticket.Ticket, graph *deptree.DependencyGraph, mark func(*ticket.Ticket) string) {
 	fmt.Println("---")
 	fmt.Printf("id: %s\n", t.ID)
-	fmt.Printf("status: %s\n", t.Status)
+	if m := mark(t); m != "" {
+		fmt.Printf("status: %s  # %s%s\n", t.Status, m, readinessWord(graph, t))
+	} else {
+		fmt.Printf("status: %s\n", t.Status)
+	}
 	fmt.Printf("deps: %s\n", formatArray(t.Deps))
 	fmt.Printf("links: %s\n", formatArray(t.Links))
+	if len(t.Tags) > 0 {
+		fmt.Printf("tags: %s\n", formatArray(t.Tags))
+	}
 	fmt.Printf("created: %s\n", t.Created.UTC().Format("2006-01-02T15:04:05Z"))
 	fmt.Printf("type: %s\n", t.Type)
 	fmt.Printf("priority: %d\n", t.Priority)
@@ -140,12 +316,24 @@ func printTicket(t *ticket.Ticket, ticketMap map[string]*ticket.Ticket) {
 		fmt.Printf("external-ref: %s\n", t.ExternalRef)
 	}
 	if t.Parent != "" {
-		if parent, ok := ticketMap[t.Parent]; ok {
+		if parent, ok := graph.Ticket(t.Parent); ok {
 			fmt.Printf("parent: %s  # %s\n", t.Parent, parent.Title)
 		} else {
-			fmt.Printf("parent: %s\n", t.Parent)
+			fmt.Printf("parent: %s  # [missing]\n", t.Parent)
 		}
 	}
+	if t.CreatedBy != "" {
+		fmt.Printf("created-by: %s\n", t.CreatedBy)
+	}
+	if t.Estimate != 0 {
+		fmt.Printf("estimate: %d\n", t.Estimate)
+	}
+	if !t.Due.IsZero() {
+		fmt.Printf("due: %s\n", t.Due.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	for _, k := range ticket.SortedExtraKeys(t.Extra) {
+		fmt.Printf("%s: %s\n", k, t.Extra[k])
+	}
 	fmt.Println("---")
 	fmt.Printf("# %s\n", t.Title)
 
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestShowCommand tests the show command
@@ -230,6 +234,42 @@ func TestShowBlockersSection(t *testing.T) {
 	})
 }
 
+// TestShowRelationshipsOnly tests that --relationships-only prints only the
+// relationship sections
+func TestShowRelationshipsOnly(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	dep, _ := ctx.exec("new", "Open Dependency")
+	dep = strings.TrimSpace(dep)
+	linked, _ := ctx.exec("new", "Linked Ticket")
+	linked = strings.TrimSpace(linked)
+
+	target, _ := ctx.exec("new", "Main Ticket", "-d", "Secret body text")
+	target = strings.TrimSpace(target)
+	child, _ := ctx.exec("new", "Child Ticket", "--parent", target)
+	child = strings.TrimSpace(child)
+
+	ctx.exec("dep", target, dep)
+	ctx.exec("link", target, linked)
+
+	output, err := ctx.exec("show", target, "--relationships-only")
+	if err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+
+	for _, want := range []string{"## Blockers", "## Children", "## Linked", dep, child, linked} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"---", "id: ", "status: ", "# Main Ticket", "Secret body text"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("output should not contain %q, got:\n%s", unwanted, output)
+		}
+	}
+}
+
 // TestShowBlockingSection tests the blocking section
 func TestShowBlockingSection(t *testing.T) {
 	t.Run("shows tickets blocked by this one", func(t *testing.T) {
@@ -525,3 +565,339 @@ func TestShowNonExistentTicket(t *testing.T) {
 		}
 	})
 }
+
+// TestShowFollowLinks tests the --follow-links neighborhood view
+func TestShowFollowLinks(t *testing.T) {
+	t.Run("shows direct links with their relationships", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		idA, _ := ctx.exec("new", "Ticket A")
+		idA = strings.TrimSpace(idA)
+		idB, _ := ctx.exec("new", "Ticket B")
+		idB = strings.TrimSpace(idB)
+		idC, _ := ctx.exec("new", "Ticket C")
+		idC = strings.TrimSpace(idC)
+
+		ctx.exec("link", idA, idB)
+		ctx.exec("dep", idB, idC)
+		ctx.exec("close", idC)
+
+		output, err := ctx.exec("show", idA, "--follow-links")
+		if err != nil {
+			t.Fatalf("show --follow-links error: %v", err)
+		}
+
+		if !strings.Contains(output, "## Neighborhood") {
+			t.Fatalf("should show Neighborhood section, got: %s", output)
+		}
+		if !strings.Contains(output, "- "+idB+" [open] Ticket B") {
+			t.Errorf("should list linked ticket with status, got: %s", output)
+		}
+		if !strings.Contains(output, "deps: "+idC+" [closed]") {
+			t.Errorf("should summarize the neighbor's deps with status, got: %s", output)
+		}
+	})
+
+	t.Run("stops at depth 1", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		idA, _ := ctx.exec("new", "Ticket A")
+		idA = strings.TrimSpace(idA)
+		idB, _ := ctx.exec("new", "Ticket B")
+		idB = strings.TrimSpace(idB)
+		idC, _ := ctx.exec("new", "Ticket C")
+		idC = strings.TrimSpace(idC)
+		idD, _ := ctx.exec("new", "Ticket D")
+		idD = strings.TrimSpace(idD)
+
+		ctx.exec("link", idA, idB)
+		ctx.exec("link", idB, idC)
+		ctx.exec("link", idC, idD)
+
+		output, _ := ctx.exec("show", idA, "--follow-links")
+
+		// C is a link of B, so it is mentioned, but not expanded
+		if !strings.Contains(output, idC) {
+			t.Errorf("should mention the neighbor's link %s", idC)
+		}
+		if strings.Contains(output, "- "+idC+" [") {
+			t.Errorf("should not expand %s beyond depth 1", idC)
+		}
+		if strings.Contains(output, idD) {
+			t.Errorf("should not reach %s at depth 2", idD)
+		}
+	})
+
+	t.Run("no neighborhood without flag", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		idA, _ := ctx.exec("new", "Ticket A")
+		idA = strings.TrimSpace(idA)
+		idB, _ := ctx.exec("new", "Ticket B")
+		idB = strings.TrimSpace(idB)
+		ctx.exec("link", idA, idB)
+
+		output, _ := ctx.exec("show", idA)
+		if strings.Contains(output, "## Neighborhood") {
+			t.Error("should not show Neighborhood section without --follow-links")
+		}
+	})
+}
+
+// TestShowDanglingReferences tests rendering of references to deleted tickets
+func TestShowDanglingReferences(t *testing.T) {
+	t.Run("dangling dep shows missing marker", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Ticket")
+		id = strings.TrimSpace(id)
+		if _, err := ctx.store().UpdateField(id, "deps", "[gone-1234]"); err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := ctx.exec("show", id)
+		if err != nil {
+			t.Fatalf("show error: %v", err)
+		}
+
+		if !strings.Contains(output, "## Blockers") {
+			t.Errorf("should show Blockers section for dangling dep, got: %s", output)
+		}
+		if !strings.Contains(output, "- gone-1234 [missing]") {
+			t.Errorf("should mark dangling dep as missing, got: %s", output)
+		}
+		if !strings.Contains(output, "tk prune") {
+			t.Errorf("should hint at prune, got: %s", output)
+		}
+	})
+
+	t.Run("dangling link and parent", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Ticket")
+		id = strings.TrimSpace(id)
+		ctx.store().UpdateField(id, "links", "[lost-5678]")
+		ctx.store().UpdateField(id, "parent", "orphan-9999")
+
+		output, _ := ctx.exec("show", id)
+
+		if !strings.Contains(output, "- lost-5678 [missing]") {
+			t.Errorf("should mark dangling link as missing, got: %s", output)
+		}
+		if !strings.Contains(output, "parent: orphan-9999  # [missing]") {
+			t.Errorf("should mark dangling parent as missing, got: %s", output)
+		}
+		if !strings.Contains(output, "2 missing reference(s)") {
+			t.Errorf("should count missing references, got: %s", output)
+		}
+	})
+
+	t.Run("no warning without dangling references", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Ticket")
+		id = strings.TrimSpace(id)
+
+		output, _ := ctx.exec("show", id)
+		if strings.Contains(output, "missing") {
+			t.Errorf("should not warn without dangling references, got: %s", output)
+		}
+	})
+}
+
+// TestShowHistory tests merging the ## History section with git history
+func TestShowHistory(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "History ticket")
+	id = strings.TrimSpace(id)
+
+	path := filepath.Join(ctx.ticketsDir, id+".md")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open ticket: %v", err)
+	}
+	f.WriteString("\n## History\n\n- 2024-01-03T10:00:00Z open -> in_progress\n- 2024-01-05T10:00:00Z in_progress -> closed\n")
+	f.Close()
+
+	origGitHistory := gitHistory
+	defer func() { gitHistory = origGitHistory }()
+
+	t.Run("merges both sources chronologically", func(t *testing.T) {
+		gitHistory = func(string) ([]historyEntry, error) {
+			return []historyEntry{
+				{when: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), source: "git", text: "close ticket"},
+				{when: time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC), source: "git", text: "edit description"},
+				{when: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), source: "git", text: "create ticket"},
+			}, nil
+		}
+
+		output, err := ctx.exec("show", id, "--history")
+		if err != nil {
+			t.Fatalf("show --history error: %v", err)
+		}
+
+		timeline := output[strings.Index(output, "## Timeline"):]
+		want := []string{
+			"2024-01-01T08:00:00Z [git] create ticket",
+			"2024-01-03T10:00:00Z [history] open -> in_progress",
+			"2024-01-04T09:00:00Z [git] edit description",
+			"2024-01-05T10:00:00Z [history] in_progress -> closed",
+		}
+		last := -1
+		for _, w := range want {
+			idx := strings.Index(timeline, w)
+			if idx == -1 {
+				t.Fatalf("expected %q in timeline, got:\n%s", w, timeline)
+			}
+			if idx < last {
+				t.Errorf("expected %q after previous entries, got:\n%s", w, timeline)
+			}
+			last = idx
+		}
+		if strings.Contains(timeline, "close ticket") {
+			t.Errorf("expected git entry with duplicate timestamp to be dropped, got:\n%s", timeline)
+		}
+	})
+
+	t.Run("falls back without git", func(t *testing.T) {
+		gitHistory = func(string) ([]historyEntry, error) {
+			return nil, errors.New("git not found")
+		}
+
+		output, err := ctx.exec("show", id, "--history")
+		if err != nil {
+			t.Fatalf("show --history error: %v", err)
+		}
+		if !strings.Contains(output, "[history] open -> in_progress") {
+			t.Errorf("expected body history entries, got:\n%s", output)
+		}
+	})
+}
+
+// TestShowExtraFrontmatter tests that custom frontmatter keys are displayed
+func TestShowExtraFrontmatter(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Custom fields")
+	id = strings.TrimSpace(id)
+
+	if _, err := ctx.store().UpdateField(id, "sprint", "s1"); err != nil {
+		t.Fatalf("UpdateField: %v", err)
+	}
+
+	// A full rewrite must not drop the custom key
+	tk, err := ctx.store().Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.store().Update(tk); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := ctx.exec("show", id)
+	if err != nil {
+		t.Fatalf("show error: %v", err)
+	}
+	if !strings.Contains(output, "sprint: s1") {
+		t.Errorf("expected custom key in show output, got:\n%s", output)
+	}
+}
+
+// stubClipboard records copied text, or fails with err when set
+type stubClipboard struct {
+	copied []string
+	err    error
+}
+
+func (s *stubClipboard) Copy(text string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.copied = append(s.copied, text)
+	return nil
+}
+
+func TestShowCopyID(t *testing.T) {
+	withStubClipboard := func(t *testing.T, stub *stubClipboard) {
+		old := clipboard
+		clipboard = stub
+		t.Cleanup(func() { clipboard = old })
+	}
+
+	t.Run("copies resolved full ID", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		stub := &stubClipboard{}
+		withStubClipboard(t, stub)
+
+		id, _ := ctx.exec("new", "Copy me")
+		id = strings.TrimSpace(id)
+
+		output, err := ctx.exec("show", id[len(id)-4:], "--copy-id")
+		if err != nil {
+			t.Fatalf("show --copy-id error: %v", err)
+		}
+		if !strings.Contains(output, "# Copy me") {
+			t.Errorf("ticket should still be displayed, got: %s", output)
+		}
+		if len(stub.copied) != 1 || stub.copied[0] != id {
+			t.Errorf("copied = %v, want [%s]", stub.copied, id)
+		}
+	})
+
+	t.Run("warns without a clipboard tool", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		withStubClipboard(t, &stubClipboard{err: errNoClipboard})
+
+		id, _ := ctx.exec("new", "No clipboard")
+		id = strings.TrimSpace(id)
+
+		output, err := ctx.exec("show", id, "--copy-id")
+		if err != nil {
+			t.Fatalf("show should continue without a clipboard: %v", err)
+		}
+		if !strings.Contains(output, "Warning: could not copy ID to clipboard") {
+			t.Errorf("expected warning, got: %s", output)
+		}
+	})
+}
+
+func TestShowEffort(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Estimated")
+	id = strings.TrimSpace(id)
+	if _, err := ctx.store().UpdateField(id, "estimate", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.store().AppendToFile(id, "\n## Worklog\n\n- 2026-10-17T09:00:00Z 1h30m investigate\n- 30m fix\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := ctx.exec("show", id)
+	if err != nil {
+		t.Fatalf("show error: %v", err)
+	}
+	for _, want := range []string{"## Effort", "- Estimate: 5h", "- Logged: 2h", "- Remaining: 3h"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+
+	plain, _ := ctx.exec("new", "Unestimated")
+	output, _ = ctx.exec("show", strings.TrimSpace(plain))
+	if strings.Contains(output, "## Effort") {
+		t.Errorf("unexpected Effort section:\n%s", output)
+	}
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lo5/tk/internal/deptree"
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [--json [--fields=LIST] | --burndown [--since=DATE]]",
+	Short: "Show ticket statistics",
+	Long: `Show a summary of the backlog for a standup: the total, how many
+tickets are ready to work on and how many are blocked (counted as tk ready
+and tk blocked do), then ticket counts by status, type and priority.
+
+Use --burndown to print the number of open tickets at the end of each day
+since --since (YYYY-MM-DD, default 14 days ago). Closure times come from the
+"-> closed" entry in a ticket's ## History section, else the git commit that
+set its status to closed, and finally the file's modification time when
+neither is available.
+
+Use --json to print the summary as a JSON object for monitoring. Its keys are
+stable; new sections may be added but existing ones keep their name and type:
+  total          number of tickets
+  by_status      tickets per status, every status present
+  by_type        tickets per type, every type present
+  by_priority    tickets per priority "0" to "4", every priority present
+  ready_count    open or in-progress tickets with every dependency closed
+  blocked_count  open or in-progress tickets with an unclosed dependency
+
+Use --fields with --json to print only some top-level sections:
+  tk stats --json --fields by_status,blocked_count`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+var (
+	statsBurndown bool
+	statsSince    string
+	statsJSON     bool
+	statsFields   []string
+)
+
+// statsReport is the stats --json output. The keys are documented in the
+// command help and must stay stable.
+type statsReport struct {
+	Total        int            `json:"total"`
+	ByStatus     map[string]int `json:"by_status"`
+	ByType       map[string]int `json:"by_type"`
+	ByPriority   map[string]int `json:"by_priority"`
+	ReadyCount   int            `json:"ready_count"`
+	BlockedCount int            `json:"blocked_count"`
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsBurndown, "burndown", false, "Print open ticket counts per day")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Start date for --burndown (YYYY-MM-DD, default 14 days ago)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the summary as JSON")
+	statsCmd.Flags().StringSliceVar(&statsFields, "fields", nil, "With --json, only print these top-level sections (comma-separated)")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	graph, err := loadGraph()
+	if err != nil {
+		return err
+	}
+
+	if statsBurndown {
+		return printBurndown(graph.Tickets())
+	}
+	if statsSince != "" {
+		return fmt.Errorf("--since requires --burndown")
+	}
+	if len(statsFields) > 0 && !statsJSON {
+		return fmt.Errorf("--fields requires --json")
+	}
+	report := buildStatsReport(graph)
+	if statsJSON {
+		return printStatsJSON(report, statsFields)
+	}
+
+	printStatsReport(report)
+	return nil
+}
+
+// printStatsReport prints the summary as aligned text, listing every
+// status, type and priority even when no ticket has it
+func printStatsReport(report statsReport) {
+	fmt.Printf("%-9s %d\n", "Total:", report.Total)
+	fmt.Printf("%-9s %d\n", "Ready:", report.ReadyCount)
+	fmt.Printf("%-9s %d\n", "Blocked:", report.BlockedCount)
+
+	fmt.Println("Status:")
+	for _, s := range ticket.ValidStatuses {
+		fmt.Printf("  %-12s %d\n", s, report.ByStatus[string(s)])
+	}
+	fmt.Println("Type:")
+	for _, t := range ticket.ValidTypes {
+		fmt.Printf("  %-12s %d\n", t, report.ByType[string(t)])
+	}
+	fmt.Println("Priority:")
+	for p := 0; p <= 4; p++ {
+		fmt.Printf("  %-12s %d\n", "P"+strconv.Itoa(p), report.ByPriority[strconv.Itoa(p)])
+	}
+}
+
+// buildStatsReport computes the stats summary. Ready and blocked tickets
+// are counted with the graph queries behind tk ready and tk blocked.
+func buildStatsReport(graph *deptree.DependencyGraph) statsReport {
+	tickets := graph.Tickets()
+	report := statsReport{
+		Total:      len(tickets),
+		ByStatus:   make(map[string]int),
+		ByType:     make(map[string]int),
+		ByPriority: make(map[string]int),
+	}
+	for _, s := range ticket.ValidStatuses {
+		report.ByStatus[string(s)] = 0
+	}
+	for _, t := range ticket.ValidTypes {
+		report.ByType[string(t)] = 0
+	}
+	for p := 0; p <= 4; p++ {
+		report.ByPriority[strconv.Itoa(p)] = 0
+	}
+
+	for _, t := range tickets {
+		report.ByStatus[string(t.Status)]++
+		report.ByType[string(t.Type)]++
+		report.ByPriority[strconv.Itoa(t.Priority)]++
+
+		switch {
+		case graph.IsReady(t.ID):
+			report.ReadyCount++
+		case graph.IsBlocked(t.ID):
+			report.BlockedCount++
+		}
+	}
+	return report
+}
+
+// printStatsJSON prints the report, keeping only the requested top-level
+// sections when fields is non-empty
+func printStatsJSON(report statsReport, fields []string) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("encoding stats: %w", err)
+	}
+
+	if len(fields) > 0 {
+		var sections map[string]json.RawMessage
+		if err := json.Unmarshal(data, &sections); err != nil {
+			return fmt.Errorf("encoding stats: %w", err)
+		}
+		selected := make(map[string]json.RawMessage)
+		for _, field := range fields {
+			section, ok := sections[field]
+			if !ok {
+				names := make([]string, 0, len(sections))
+				for name := range sections {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				return fmt.Errorf("unknown stats field %q (valid: %s)", field, strings.Join(names, ", "))
+			}
+			selected[field] = section
+		}
+		if data, err = json.Marshal(selected); err != nil {
+			return fmt.Errorf("encoding stats: %w", err)
+		}
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// printBurndown prints the number of open tickets at the end of each day
+func printBurndown(tickets []*ticket.Ticket) error {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -14)
+	if statsSince != "" {
+		parsed, err := time.Parse("2006-01-02", statsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since date '%s'. Use YYYY-MM-DD", statsSince)
+		}
+		since = parsed
+	}
+	if since.After(today) {
+		return fmt.Errorf("--since date '%s' is in the future", statsSince)
+	}
+
+	modTimes, err := store.ModTimes()
+	if err != nil {
+		return err
+	}
+
+	// Git is optional: without it (or outside a repo) the History section
+	// and modification times are used
+	committed, _ := gitClosures(store.Dir())
+
+	closedAt := make(map[string]time.Time)
+	for _, t := range tickets {
+		if t.Status == ticket.StatusClosed {
+			closedAt[t.ID] = closureTime(t, modTimes[t.ID], committed)
+		}
+	}
+
+	fmt.Printf("Burndown since %s:\n", since.Format("2006-01-02"))
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		open := 0
+		for _, t := range tickets {
+			if !t.Created.Before(end) {
+				continue
+			}
+			if closed, ok := closedAt[t.ID]; ok && closed.Before(end) {
+				continue
+			}
+			open++
+		}
+		fmt.Printf("%s  %3d %s\n", day.Format("2006-01-02"), open, strings.Repeat("#", open))
+	}
+	return nil
+}
+
+// closureTime estimates when a closed ticket was closed: the last "-> closed"
+// entry in its ## History section, else the git commit that set its status
+// to closed, else the file's modification time. Later edits such as notes
+// don't move the closure, since neither source records them.
+func closureTime(t *ticket.Ticket, mtime time.Time, committed map[string]time.Time) time.Time {
+	var closed time.Time
+	for _, e := range parseHistorySection(t.Body) {
+		if strings.HasSuffix(e.text, "-> closed") && e.when.After(closed) {
+			closed = e.when
+		}
+	}
+	if !closed.IsZero() {
+		return closed
+	}
+
+	if when, ok := committed[t.ID]; ok {
+		return when
+	}
+	return mtime
+}
+
+// gitClosures returns when each ticket under dir was last set to closed in
+// git: the author time of the newest commit adding a "status: closed" line
+// to its file, keyed by ticket ID. A single git log covers every ticket. It
+// is a variable so tests can stub out git.
+var gitClosures = func(dir string) (map[string]time.Time, error) {
+	out, err := exec.Command("git", "-C", dir, "log", "-p", "--unified=0", "--no-renames",
+		"--format=%x00%aI", "-G^status: closed", "--", ".").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	closures := make(map[string]time.Time)
+	var when time.Time
+	id := ""
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "\x00"):
+			when, _ = time.Parse(time.RFC3339, line[1:])
+		case strings.HasPrefix(line, "+++ "):
+			id = strings.TrimSuffix(path.Base(line[4:]), ".md")
+		case line == "+status: closed":
+			// git log lists the newest commit first
+			if _, seen := closures[id]; !seen && !when.IsZero() {
+				closures[id] = when
+			}
+		}
+	}
+	return closures, nil
+}
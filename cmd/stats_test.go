@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestStatsSummary(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	ctx.exec("new", "One")
+	id, _ := ctx.exec("new", "Two")
+	ctx.exec("close", strings.TrimSpace(id))
+
+	output, err := ctx.exec("stats")
+	if err != nil {
+		t.Fatalf("stats error: %v", err)
+	}
+	for _, want := range []string{"Total:    2", "open         1", "closed       1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestStatsReport tests the full text report and that its ready and blocked
+// counts agree with the ready and blocked commands
+func TestStatsReport(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	dep, _ := ctx.exec("new", "Dependency", "-t", "bug", "-p", "0")
+	dep = strings.TrimSpace(dep)
+	for _, title := range []string{"Blocked one", "Blocked two"} {
+		id, _ := ctx.exec("new", title, "-t", "feature", "-p", "2")
+		ctx.exec("dep", strings.TrimSpace(id), dep)
+	}
+	done, _ := ctx.exec("new", "Done", "-t", "task", "-p", "4")
+	ctx.exec("close", strings.TrimSpace(done))
+	started, _ := ctx.exec("new", "Started", "-t", "task", "-p", "2")
+	ctx.exec("start", strings.TrimSpace(started))
+
+	output, err := ctx.exec("stats")
+	if err != nil {
+		t.Fatalf("stats error: %v", err)
+	}
+	want := `Total:    5
+Ready:    2
+Blocked:  2
+Status:
+  open         3
+  in_progress  1
+  closed       1
+Type:
+  bug          1
+  feature      2
+  task         2
+  epic         0
+  chore        0
+Priority:
+  P0           1
+  P1           0
+  P2           3
+  P3           0
+  P4           1
+`
+	if output != want {
+		t.Errorf("stats output:\n%s\nwant:\n%s", output, want)
+	}
+
+	countLines := func(args ...string) int {
+		out, err := ctx.exec(args...)
+		if err != nil {
+			t.Fatalf("%v error: %v", args, err)
+		}
+		return strings.Count(out, "\n")
+	}
+	if n := countLines("ready"); n != 2 {
+		t.Errorf("ready lists %d ticket(s), stats reports 2", n)
+	}
+	if n := countLines("blocked"); n != 2 {
+		t.Errorf("blocked lists %d ticket(s), stats reports 2", n)
+	}
+}
+
+func TestStatsBurndownFallback(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	// Force the fallback path: no git history available
+	origGitClosures := gitClosures
+	gitClosures = func(string) (map[string]time.Time, error) { return nil, errors.New("git not found") }
+	defer func() { gitClosures = origGitClosures }()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := func(offset int) time.Time { return today.AddDate(0, 0, offset).Add(12 * time.Hour) }
+
+	// Four tickets created 5 days ago; closed 3, 2 and 1 day(s) ago
+	var ids []string
+	for i := 0; i < 4; i++ {
+		id, _ := ctx.exec("new", "Sprint item "+strconv.Itoa(i))
+		id = strings.TrimSpace(id)
+		ids = append(ids, id)
+
+		tk, _ := ctx.store().Get(id)
+		tk.Created = day(-5)
+		if err := ctx.store().Update(tk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, offset := range []int{-3, -2, -1} {
+		ctx.exec("close", ids[i])
+		if err := ctx.store().SetModTime(ids[i], day(offset)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	since := today.AddDate(0, 0, -6).Format("2006-01-02")
+	output, err := ctx.exec("stats", "--burndown", "--since", since)
+	if err != nil {
+		t.Fatalf("stats --burndown error: %v", err)
+	}
+
+	var counts []int
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "20") {
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			t.Fatalf("bad count in line %q", line)
+		}
+		counts = append(counts, n)
+	}
+
+	want := []int{0, 4, 4, 3, 2, 1, 1}
+	if len(counts) != len(want) {
+		t.Fatalf("expected %d days, got %d:\n%s", len(want), len(counts), output)
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Errorf("day %d: open = %d, want %d\n%s", i, counts[i], want[i], output)
+		}
+	}
+}
+
+func TestClosureTime(t *testing.T) {
+	mtime := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	committedAt := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	committed := map[string]time.Time{"c-1": committedAt}
+
+	withHistory := &ticket.Ticket{ID: "c-1", Body: "## History\n\n" +
+		"- 2026-03-01T09:00:00Z open -> closed\n- 2026-03-02T09:00:00Z closed -> open\n- 2026-03-03T09:00:00Z open -> closed\n"}
+	if got := closureTime(withHistory, mtime, committed); !got.Equal(time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("closureTime() = %v, want the last History closure", got)
+	}
+	if got := closureTime(&ticket.Ticket{ID: "c-1"}, mtime, committed); !got.Equal(committedAt) {
+		t.Errorf("closureTime() = %v, want the closing commit", got)
+	}
+	if got := closureTime(&ticket.Ticket{ID: "c-2"}, mtime, committed); !got.Equal(mtime) {
+		t.Errorf("closureTime() = %v, want the modification time", got)
+	}
+}
+
+func TestGitClosures(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date,
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(date, status, body string) {
+		t.Helper()
+		content := "---\nid: g-1\nstatus: " + status + "\n---\n# Git ticket\n" + body
+		if err := os.WriteFile(filepath.Join(dir, "g-1.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git(date, "add", ".")
+		git(date, "commit", "-q", "-m", status)
+	}
+
+	git("2026-03-01T00:00:00Z", "init", "-q")
+	commit("2026-03-01T00:00:00Z", "open", "")
+	commit("2026-03-02T00:00:00Z", "closed", "")
+	commit("2026-03-03T00:00:00Z", "open", "")
+	commit("2026-03-04T00:00:00Z", "closed", "")
+	commit("2026-03-08T00:00:00Z", "closed", "A later note\n")
+
+	closures, err := gitClosures(dir)
+	if err != nil {
+		t.Fatalf("gitClosures() error = %v", err)
+	}
+	if got := closures["g-1"]; !got.Equal(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("closure = %v, want the last commit closing the ticket, not the later note", got)
+	}
+}
+
+func TestStatsBurndownInvalidSince(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	if _, err := ctx.exec("stats", "--burndown", "--since", "last week"); err == nil {
+		t.Error("expected error for invalid --since date")
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	dep, _ := ctx.exec("new", "Dependency")
+	dep = strings.TrimSpace(dep)
+	blocked, _ := ctx.exec("new", "Blocked")
+	ctx.exec("dep", strings.TrimSpace(blocked), dep)
+
+	t.Run("full output has every documented key", func(t *testing.T) {
+		output, err := ctx.exec("stats", "--json")
+		if err != nil {
+			t.Fatalf("stats --json error: %v", err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", output, err)
+		}
+		for _, key := range []string{"total", "by_status", "by_type", "by_priority", "ready_count", "blocked_count"} {
+			if _, ok := got[key]; !ok {
+				t.Errorf("missing key %q in %s", key, output)
+			}
+		}
+		if got["total"] != 2.0 || got["ready_count"] != 1.0 || got["blocked_count"] != 1.0 {
+			t.Errorf("counts = %s", output)
+		}
+		if byStatus := got["by_status"].(map[string]interface{}); byStatus["closed"] != 0.0 {
+			t.Errorf("by_status = %v, want closed listed as 0", byStatus)
+		}
+	})
+
+	t.Run("fields selects sections", func(t *testing.T) {
+		output, err := ctx.exec("stats", "--json", "--fields", "by_status,blocked_count")
+		if err != nil {
+			t.Fatalf("stats --fields error: %v", err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", output, err)
+		}
+		if len(got) != 2 || got["blocked_count"] != 1.0 || got["by_status"] == nil {
+			t.Errorf("output = %s, want only by_status and blocked_count", output)
+		}
+	})
+
+	t.Run("unknown field errors", func(t *testing.T) {
+		_, err := ctx.exec("stats", "--json", "--fields", "nope")
+		if err == nil || !strings.Contains(err.Error(), `unknown stats field "nope"`) {
+			t.Errorf("error = %v", err)
+		}
+	})
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/lo5/tk/internal/config"
+	"github.com/lo5/tk/internal/deptree"
 	"github.com/lo5/tk/internal/ticket"
 	"github.com/spf13/cobra"
 )
@@ -11,39 +15,76 @@ import (
 var statusCmd = &cobra.Command{
 	Use:   "status <id> <status>",
 	Short: "Update ticket status",
-	Long:  fmt.Sprintf("Update the status of a ticket.\nValid statuses: %s", strings.Join(statusNames(), ", ")),
-	Args:  cobra.ExactArgs(2),
-	RunE:  runStatus,
+	Long: fmt.Sprintf(`Update the status of a ticket.
+Valid statuses: %s
+
+Use --batch-json <file> (or - for stdin) to apply a JSON array of updates,
+e.g. [{"id":"a","status":"closed"}]. Per-item results are printed as JSON.`, strings.Join(statusNames(), ", ")),
+	Args: orBatchJSON(&statusBatchJSON, cobra.ExactArgs(2)),
+	RunE: runStatus,
 }
 
+// multiIDHelp describes how start, close and reopen handle several IDs
+const multiIDHelp = `Several IDs may be given. Each is updated independently and reported on its
+own line, followed by a summary; the exit status is non-zero if any failed.
+With --strict, every ID must resolve before any ticket is changed.`
+
 var startCmd = &cobra.Command{
-	Use:   "start <id>",
+	Use:   "start <id>... | start --ready [--mine]",
 	Short: "Set ticket status to in_progress",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		return setStatus(args[0], ticket.StatusInProgress)
-	},
+	Long: `Set ticket status to in_progress.
+
+With --ready, start every open ticket whose dependencies are all closed.
+Add --mine to only start ready tickets assigned to the current user.
+
+` + multiIDHelp,
+	RunE: runStart,
 }
 
 var closeCmd = &cobra.Command{
-	Use:   "close <id>",
+	Use:   "close <id>...",
 	Short: "Set ticket status to closed",
-	Args:  cobra.ExactArgs(1),
+	Long:  "Set ticket status to closed.\n\n" + multiIDHelp,
+	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return setStatus(args[0], ticket.StatusClosed)
+		return setStatuses(cmd, args, ticket.StatusClosed, closeStrict)
 	},
 }
 
 var reopenCmd = &cobra.Command{
-	Use:   "reopen <id>",
+	Use:   "reopen <id>...",
 	Short: "Set ticket status to open",
-	Args:  cobra.ExactArgs(1),
+	Long:  "Set ticket status to open.\n\n" + multiIDHelp,
+	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return setStatus(args[0], ticket.StatusOpen)
+		return setStatuses(cmd, args, ticket.StatusOpen, reopenStrict)
 	},
 }
 
+var (
+	statusBatchJSON string
+	startReady      bool
+	startMine       bool
+	startStrict     bool
+	closeStrict     bool
+	reopenStrict    bool
+)
+
+// statusOp is a single --batch-json status update
+type statusOp struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
 func init() {
+	statusCmd.Flags().StringVar(&statusBatchJSON, "batch-json", "", "Apply a JSON array of status updates from a file (- for stdin)")
+
+	startCmd.Flags().BoolVar(&startReady, "ready", false, "Start all ready open tickets")
+	startCmd.Flags().BoolVar(&startMine, "mine", false, "With --ready, only start tickets assigned to the current user")
+	startCmd.Flags().BoolVar(&startStrict, "strict", false, "Change nothing unless every ID resolves")
+	closeCmd.Flags().BoolVar(&closeStrict, "strict", false, "Change nothing unless every ID resolves")
+	reopenCmd.Flags().BoolVar(&reopenStrict, "strict", false, "Change nothing unless every ID resolves")
+
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(closeCmd)
@@ -59,6 +100,10 @@ func statusNames() []string {
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	if statusBatchJSON != "" {
+		return runBatchJSON(cmd, statusBatchJSON, applyStatusOp)
+	}
+
 	id := args[0]
 	status := ticket.Status(args[1])
 
@@ -69,8 +114,129 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	return setStatus(id, status)
 }
 
+// applyStatusOp validates and applies a --batch-json status update
+func applyStatusOp(item json.RawMessage) (string, error) {
+	var op statusOp
+	if err := decodeBatchItem(item, &op); err != nil {
+		return "", err
+	}
+	if op.ID == "" {
+		return "", fmt.Errorf("missing 'id'")
+	}
+	status := ticket.Status(op.Status)
+	if !status.IsValid() {
+		return "", fmt.Errorf("invalid status '%s'. Must be one of: %s", op.Status, strings.Join(statusNames(), ", "))
+	}
+
+	return updateStatus(op.ID, status)
+}
+
+func runStart(cmd *cobra.Command, args []string) error {
+	if !startReady {
+		if startMine {
+			return fmt.Errorf("--mine requires --ready")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("usage: tk start <id>... or tk start --ready [--mine]")
+		}
+		return setStatuses(cmd, args, ticket.StatusInProgress, startStrict)
+	}
+
+	if len(args) > 0 {
+		return fmt.Errorf("cannot combine a ticket ID with --ready")
+	}
+	return startReadyTickets(startMine)
+}
+
+// startReadyTickets moves ready open tickets to in_progress, optionally only
+// those assigned to the current user
+func startReadyTickets(mine bool) error {
+	user := ""
+	if mine {
+		user = currentUser()
+		if user == "" {
+			return fmt.Errorf("cannot determine current user for --mine: %s", noUserHelp)
+		}
+	}
+
+	tickets, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	var selected []*ticket.Ticket
+	for _, t := range deptree.NewGraph(tickets).Ready() {
+		if t.Status != ticket.StatusOpen {
+			continue
+		}
+		if mine && t.Assignee != user {
+			continue
+		}
+		selected = append(selected, t)
+	}
+
+	if len(selected) == 0 {
+		fmt.Println("No ready tickets to start")
+		return nil
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].Priority != selected[j].Priority {
+			return selected[i].Priority < selected[j].Priority
+		}
+		return selected[i].ID < selected[j].ID
+	})
+
+	for _, t := range selected {
+		if err := setStatus(t.ID, ticket.StatusInProgress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setStatuses applies a status to each (partial) ID, reporting every
+// failure on stderr and continuing with the rest. A single ID behaves like
+// setStatus. With strict, nothing changes unless every ID resolves.
+func setStatuses(cmd *cobra.Command, partials []string, status ticket.Status, strict bool) error {
+	if len(partials) == 1 {
+		return setStatus(partials[0], status)
+	}
+
+	failed := 0
+	if strict {
+		for _, partial := range partials {
+			if _, err := store.ResolveID(partial); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s: %v\n", partial, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("--strict: %d of %d ID(s) did not resolve; no tickets changed", failed, len(partials))
+		}
+	}
+
+	for _, partial := range partials {
+		if err := setStatus(partial, status); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s: %v\n", partial, err)
+			failed++
+		}
+	}
+
+	fmt.Printf("Updated %d of %d ticket(s) -> %s", len(partials)-failed, len(partials), status)
+	if failed > 0 {
+		fmt.Printf(", %d error(s)", failed)
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return failSilently(cmd, exitError)
+	}
+	return nil
+}
+
 func setStatus(partial string, status ticket.Status) error {
-	id, err := store.UpdateField(partial, "status", string(status))
+	id, err := updateStatus(partial, status)
 	if err != nil {
 		return err
 	}
@@ -78,3 +244,42 @@ func setStatus(partial string, status ticket.Status) error {
 	fmt.Printf("Updated %s -> %s\n", id, status)
 	return nil
 }
+
+// updateStatus writes a status change and returns the full ticket ID. Every
+// status change goes through here so the assignee settings always apply.
+func updateStatus(partial string, status ticket.Status) (string, error) {
+	fields, err := statusFields(partial, status)
+	if err != nil {
+		return "", err
+	}
+	return store.UpdateFields(partial, fields)
+}
+
+// statusFields returns the fields to write for a status change, including
+// the assignee changes enabled by assign_on_start and unassign_on_close
+func statusFields(partial string, status ticket.Status) (map[string]string, error) {
+	fields := map[string]string{"status": string(status)}
+
+	cfg, err := config.Load(store.Dir())
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.AssignOnStart && !cfg.UnassignOnClose {
+		return fields, nil
+	}
+
+	t, err := store.Get(partial)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case status == ticket.StatusInProgress && cfg.AssignOnStart && t.Assignee == "":
+		if user := currentUser(); user != "" {
+			fields["assignee"] = user
+		}
+	case status == ticket.StatusClosed && cfg.UnassignOnClose && t.Assignee != "":
+		fields["assignee"] = ""
+	}
+	return fields, nil
+}
@@ -258,6 +258,85 @@ func TestStartCommand(t *testing.T) {
 	})
 }
 
+// TestStartReady tests batch-starting ready tickets
+func TestStartReady(t *testing.T) {
+	t.Run("only ready and assigned tickets transition", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		const me = "alice"
+		orig := currentUser
+		currentUser = func() string { return me }
+		defer func() { currentUser = orig }()
+
+		blocker, _ := ctx.exec("new", "Blocker", "--assignee", "someone-else")
+		blocker = strings.TrimSpace(blocker)
+		mineReady, _ := ctx.exec("new", "Mine ready", "--assignee", me)
+		mineReady = strings.TrimSpace(mineReady)
+		mineBlocked, _ := ctx.exec("new", "Mine blocked", "--assignee", me)
+		mineBlocked = strings.TrimSpace(mineBlocked)
+		ctx.exec("dep", mineBlocked, blocker)
+
+		output, err := ctx.exec("start", "--ready", "--mine")
+		if err != nil {
+			t.Fatalf("start --ready --mine error: %v", err)
+		}
+		if !strings.Contains(output, mineReady) {
+			t.Errorf("output should report started ticket, got: %s", output)
+		}
+
+		want := map[string]ticket.Status{
+			mineReady:   ticket.StatusInProgress,
+			mineBlocked: ticket.StatusOpen,
+			blocker:     ticket.StatusOpen,
+		}
+		for id, status := range want {
+			tk, _ := ctx.store().Get(id)
+			if tk.Status != status {
+				t.Errorf("%s status = %v, want %v", tk.Title, tk.Status, status)
+			}
+		}
+	})
+
+	t.Run("without --mine starts all ready open tickets", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "A", "--assignee", "alice")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "B", "--assignee", "bob")
+		b = strings.TrimSpace(b)
+		c, _ := ctx.exec("new", "C")
+		c = strings.TrimSpace(c)
+		ctx.exec("dep", c, a)
+
+		if _, err := ctx.exec("start", "--ready"); err != nil {
+			t.Fatalf("start --ready error: %v", err)
+		}
+
+		for id, status := range map[string]ticket.Status{
+			a: ticket.StatusInProgress,
+			b: ticket.StatusInProgress,
+			c: ticket.StatusOpen,
+		} {
+			tk, _ := ctx.store().Get(id)
+			if tk.Status != status {
+				t.Errorf("%s status = %v, want %v", tk.Title, tk.Status, status)
+			}
+		}
+	})
+
+	t.Run("rejects id with --ready", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id := createTestTicket(ctx, t, "Test Ticket", ticket.StatusOpen)
+		if _, err := ctx.exec("start", "--ready", id); err == nil {
+			t.Error("expected error combining id and --ready")
+		}
+	})
+}
+
 // TestCloseCommand tests the close command
 func TestCloseCommand(t *testing.T) {
 	t.Run("close open ticket", func(t *testing.T) {
@@ -547,3 +626,148 @@ func TestStatusTransitions(t *testing.T) {
 		}
 	})
 }
+
+// TestAssignOnStart tests the assign_on_start and unassign_on_close settings
+func TestAssignOnStart(t *testing.T) {
+	// new defaults the assignee to the current user, so tickets are created
+	// with no identity and the user is switched before starting them
+	user := ""
+	orig := currentUser
+	currentUser = func() string { return user }
+	defer func() { currentUser = orig }()
+
+	t.Run("starting assigns unassigned tickets only", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		user = ""
+
+		ctx.exec("config", "set", "assign_on_start", "true")
+		unassigned, _ := ctx.exec("new", "Unassigned")
+		unassigned = strings.TrimSpace(unassigned)
+		theirs, _ := ctx.exec("new", "Theirs", "--assignee", "someone-else")
+		theirs = strings.TrimSpace(theirs)
+
+		user = "me"
+		for _, id := range []string{unassigned, theirs} {
+			if _, err := ctx.exec("start", id); err != nil {
+				t.Fatalf("start %s error: %v", id, err)
+			}
+		}
+
+		want := map[string]string{unassigned: "me", theirs: "someone-else"}
+		for id, assignee := range want {
+			tk, _ := ctx.store().Get(id)
+			if tk.Assignee != assignee {
+				t.Errorf("%s assignee = %q, want %q", tk.Title, tk.Assignee, assignee)
+			}
+			if tk.Status != ticket.StatusInProgress {
+				t.Errorf("%s status = %v, want in_progress", tk.Title, tk.Status)
+			}
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+		user = ""
+
+		id, _ := ctx.exec("new", "Unassigned")
+		id = strings.TrimSpace(id)
+		user = "me"
+		ctx.exec("start", id)
+
+		tk, _ := ctx.store().Get(id)
+		if tk.Assignee != "" {
+			t.Errorf("assignee = %q, want none without config", tk.Assignee)
+		}
+	})
+
+	t.Run("closing unassigns", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("config", "set", "unassign_on_close", "true")
+		id, _ := ctx.exec("new", "Assigned", "--assignee", "someone")
+		id = strings.TrimSpace(id)
+		ctx.exec("close", id)
+
+		tk, _ := ctx.store().Get(id)
+		if tk.Assignee != "" || tk.Status != ticket.StatusClosed {
+			t.Errorf("got assignee %q status %v, want unassigned and closed", tk.Assignee, tk.Status)
+		}
+	})
+
+	t.Run("applies to several IDs and --batch-json", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("config", "set", "unassign_on_close", "true")
+		var ids []string
+		for _, title := range []string{"First", "Second", "Third"} {
+			id, _ := ctx.exec("new", title, "--assignee", "someone")
+			ids = append(ids, strings.TrimSpace(id))
+		}
+		if _, err := ctx.exec("close", ids[0], ids[1]); err != nil {
+			t.Fatalf("close error: %v", err)
+		}
+		batch := `[{"id":"` + ids[2] + `","status":"closed"}]`
+		if _, err := ctx.execWithStdin(batch, "status", "--batch-json", "-"); err != nil {
+			t.Fatalf("status --batch-json error: %v", err)
+		}
+
+		for _, id := range ids {
+			tk, _ := ctx.store().Get(id)
+			if tk.Assignee != "" || tk.Status != ticket.StatusClosed {
+				t.Errorf("%s: got assignee %q status %v, want unassigned and closed", tk.Title, tk.Assignee, tk.Status)
+			}
+		}
+	})
+}
+
+func TestCloseMultipleIDs(t *testing.T) {
+	t.Run("reports each ID and a summary", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "First")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "Second")
+		b = strings.TrimSpace(b)
+
+		output, err := ctx.exec("close", a, "no-such-ticket", b)
+		if err == nil {
+			t.Error("close with a missing ID should exit non-zero")
+		}
+		for _, want := range []string{
+			"Updated " + a + " -> closed",
+			"Updated " + b + " -> closed",
+			"Error: no-such-ticket: ticket 'no-such-ticket' not found",
+			"Updated 2 of 3 ticket(s) -> closed, 1 error(s)",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected %q in output:\n%s", want, output)
+			}
+		}
+		for _, id := range []string{a, b} {
+			if tk, _ := ctx.store().Get(id); tk.Status != ticket.StatusClosed {
+				t.Errorf("%s status = %s, want closed", id, tk.Status)
+			}
+		}
+	})
+
+	t.Run("strict changes nothing when an ID is missing", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		a, _ := ctx.exec("new", "First")
+		a = strings.TrimSpace(a)
+
+		_, err := ctx.exec("start", "--strict", a, "no-such-ticket")
+		if err == nil || !strings.Contains(err.Error(), "no tickets changed") {
+			t.Errorf("start --strict error = %v", err)
+		}
+		if tk, _ := ctx.store().Get(a); tk.Status != ticket.StatusOpen {
+			t.Errorf("status = %s, want open", tk.Status)
+		}
+	})
+}
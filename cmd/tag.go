@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <id> <tag> [tag...]",
+	Short: "Add tags to a ticket",
+	Long: `Add one or more free-form tags to a ticket. Tags already on the ticket
+are left alone, so tagging is idempotent. Tags are stored in the frontmatter
+as a flow-style list, e.g. "tags: [backend, urgent]", and appear in query
+output as the tags array:
+  tk query '.tags | index("urgent")'`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runTag,
+}
+
+var untagCmd = &cobra.Command{
+	Use:   "untag <id> <tag>",
+	Short: "Remove a tag from a ticket",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runUntag,
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(untagCmd)
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	for _, tag := range args[1:] {
+		if err := validateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	t, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	tags := append([]string{}, t.Tags...)
+	added := 0
+	for _, tag := range args[1:] {
+		if hasTag(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+		added++
+	}
+
+	if added == 0 {
+		fmt.Println("All tags already present")
+		return nil
+	}
+
+	if _, err := store.UpdateField(t.ID, "tags", formatArray(tags)); err != nil {
+		return err
+	}
+	fmt.Printf("Added %d tag(s) to %s: %s\n", added, t.ID, formatArray(tags))
+	return nil
+}
+
+func runUntag(cmd *cobra.Command, args []string) error {
+	t, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	tag := args[1]
+	if !hasTag(t.Tags, tag) {
+		return fmt.Errorf("tag '%s' not found on %s", tag, t.ID)
+	}
+
+	var tags []string
+	for _, existing := range t.Tags {
+		if existing != tag {
+			tags = append(tags, existing)
+		}
+	}
+
+	// Removing the last tag removes the field, as it was before tagging
+	value := ""
+	if len(tags) > 0 {
+		value = formatArray(tags)
+	}
+	if _, err := store.UpdateFields(t.ID, map[string]string{"tags": value}); err != nil {
+		return err
+	}
+	fmt.Printf("Removed tag %s from %s\n", tag, t.ID)
+	return nil
+}
+
+// hasTag reports whether tags contains tag
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTag rejects tags that would not survive as an element of a YAML
+// flow-style list
+func validateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tag cannot be empty")
+	}
+	if strings.ContainsAny(tag, " \t\n,[]{}:#\"'") {
+		return fmt.Errorf("invalid tag %q: tags cannot contain whitespace, quotes or any of ,[]{}:#", tag)
+	}
+	return nil
+}
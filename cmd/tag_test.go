@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestTagCommand tests adding tags with tag
+func TestTagCommand(t *testing.T) {
+	t.Run("adds tags idempotently", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Ticket")
+		id = strings.TrimSpace(id)
+
+		if _, err := ctx.exec("tag", id, "backend", "urgent"); err != nil {
+			t.Fatalf("tag failed: %v", err)
+		}
+		output, err := ctx.exec("tag", id, "urgent", "backend")
+		if err != nil {
+			t.Fatalf("repeat tag failed: %v", err)
+		}
+		if !strings.Contains(output, "All tags already present") {
+			t.Errorf("expected no-op message, got: %s", output)
+		}
+
+		tk, _ := ctx.store().Get(id)
+		if len(tk.Tags) != 2 || tk.Tags[0] != "backend" || tk.Tags[1] != "urgent" {
+			t.Errorf("Tags = %v, want [backend urgent]", tk.Tags)
+		}
+	})
+
+	t.Run("tags are queryable", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		idTagged, _ := ctx.exec("new", "Tagged")
+		idTagged = strings.TrimSpace(idTagged)
+		idPlain, _ := ctx.exec("new", "Plain")
+		idPlain = strings.TrimSpace(idPlain)
+		ctx.exec("tag", idTagged, "urgent")
+
+		output, err := ctx.exec("query", `.tags | index("urgent")`)
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if !strings.Contains(output, idTagged) {
+			t.Errorf("should match the tagged ticket, got: %s", output)
+		}
+		if strings.Contains(output, idPlain) {
+			t.Errorf("should not match the untagged ticket, got: %s", output)
+		}
+	})
+
+	t.Run("rejects invalid tags", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Ticket")
+		id = strings.TrimSpace(id)
+
+		_, err := ctx.exec("tag", id, "a,b")
+		if err == nil || !strings.Contains(err.Error(), "invalid tag") {
+			t.Errorf("expected invalid tag error, got: %v", err)
+		}
+	})
+}
+
+// TestUntagCommand tests removing tags with untag
+func TestUntagCommand(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Ticket")
+	id = strings.TrimSpace(id)
+	ctx.exec("tag", id, "backend", "urgent")
+
+	if _, err := ctx.exec("untag", id, "backend"); err != nil {
+		t.Fatalf("untag failed: %v", err)
+	}
+	tk, _ := ctx.store().Get(id)
+	if len(tk.Tags) != 1 || tk.Tags[0] != "urgent" {
+		t.Errorf("Tags = %v, want [urgent]", tk.Tags)
+	}
+
+	_, err := ctx.exec("untag", id, "backend")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+
+	// Removing the last tag leaves the file as it was before tagging
+	plain, _ := ctx.exec("new", "Untagged")
+	plain = strings.TrimSpace(plain)
+	_, before, _ := ctx.store().ReadRaw(plain)
+	ctx.exec("tag", plain, "temp")
+	if _, err := ctx.exec("untag", plain, "temp"); err != nil {
+		t.Fatalf("untag failed: %v", err)
+	}
+	if _, after, _ := ctx.store().ReadRaw(plain); after != before {
+		t.Errorf("untagging the last tag should remove the field:\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lo5/tk/internal/config"
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Restore the most recently deleted ticket(s) from the trash",
+	Long: `Restore the ticket(s) removed by the most recent rm or clean --fix.
+
+Deleted tickets are only kept when the trash is enabled:
+  tk config set trash true
+
+Links removed from other tickets at deletion are not restored. A warning is
+printed for each relationship of a restored ticket that no longer holds.`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+var emptyTrashCmd = &cobra.Command{
+	Use:   "empty-trash",
+	Short: "Permanently remove all tickets in the trash",
+	Args:  cobra.NoArgs,
+	RunE:  runEmptyTrash,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(emptyTrashCmd)
+}
+
+// trasher returns the store's trash, if it has one
+func trasher() (ticket.Trasher, error) {
+	bin, ok := store.(ticket.Trasher)
+	if !ok {
+		return nil, fmt.Errorf("the ticket store does not support the trash")
+	}
+	return bin, nil
+}
+
+// removeTicket deletes a ticket, or moves it to the trash when enabled.
+// Tickets removed by one command share the deletion time.
+func removeTicket(cfg *config.Config, id string, at time.Time) error {
+	if !cfg.Trash {
+		return store.Delete(id)
+	}
+	bin, err := trasher()
+	if err != nil {
+		return err
+	}
+	_, err = bin.Trash(id, at)
+	return err
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	bin, err := trasher()
+	if err != nil {
+		return err
+	}
+	trash, err := bin.ListTrash()
+	if err != nil {
+		return err
+	}
+	if len(trash) == 0 {
+		return fmt.Errorf("trash is empty")
+	}
+
+	// Restore everything deleted together with the most recent entry
+	var restored []string
+	for _, entry := range trash {
+		if !entry.Trashed.Equal(trash[0].Trashed) {
+			break
+		}
+		if err := bin.Restore(entry); err != nil {
+			return err
+		}
+		fmt.Printf("Restored: %s\n", entry.ID)
+		restored = append(restored, entry.ID)
+	}
+
+	allTickets, err := store.List()
+	if err != nil {
+		return err
+	}
+	for _, id := range restored {
+		for _, warning := range restoreWarnings(id, allTickets) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", warning)
+		}
+	}
+	return nil
+}
+
+// restoreWarnings describes the relationships of a restored ticket that
+// changed while it was in the trash
+func restoreWarnings(id string, allTickets []*ticket.Ticket) []string {
+	ticketMap := make(map[string]*ticket.Ticket)
+	for _, t := range allTickets {
+		ticketMap[t.ID] = t
+	}
+	t, ok := ticketMap[id]
+	if !ok {
+		return nil
+	}
+
+	var warnings []string
+	for _, dep := range t.Deps {
+		if _, ok := ticketMap[dep]; !ok {
+			warnings = append(warnings, fmt.Sprintf("%s depends on %s, which no longer exists", id, dep))
+		}
+	}
+	if t.Parent != "" {
+		if _, ok := ticketMap[t.Parent]; !ok {
+			warnings = append(warnings, fmt.Sprintf("%s has parent %s, which no longer exists", id, t.Parent))
+		}
+	}
+	for _, linkID := range t.LinkIDs() {
+		linked, ok := ticketMap[linkID]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s links to %s, which no longer exists", id, linkID))
+			continue
+		}
+		if _, ok := linked.FindLink(id); !ok {
+			warnings = append(warnings, fmt.Sprintf("%s links to %s, which no longer links back (run 'tk link %s %s')", id, linkID, id, linkID))
+		}
+	}
+	return warnings
+}
+
+func runEmptyTrash(cmd *cobra.Command, args []string) error {
+	bin, err := trasher()
+	if err != nil {
+		return err
+	}
+	n, err := bin.EmptyTrash()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Emptied trash: %d ticket(s) permanently removed\n", n)
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUndo(t *testing.T) {
+	t.Run("restores a trashed ticket", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("config", "set", "trash", "true")
+		id, _ := ctx.exec("new", "Oops")
+		id = strings.TrimSpace(id)
+
+		output, err := ctx.exec("rm", id)
+		if err != nil {
+			t.Fatalf("rm error: %v", err)
+		}
+		if !strings.Contains(output, "tk undo") {
+			t.Errorf("rm output = %q, want undo hint", output)
+		}
+		if tickets, _ := ctx.store().List(); len(tickets) != 0 {
+			t.Fatalf("List() after rm returned %d tickets", len(tickets))
+		}
+
+		output, err = ctx.exec("undo")
+		if err != nil {
+			t.Fatalf("undo error: %v", err)
+		}
+		if !strings.Contains(output, "Restored: "+id) {
+			t.Errorf("undo output = %q", output)
+		}
+		tickets, _ := ctx.store().List()
+		if len(tickets) != 1 || tickets[0].ID != id {
+			t.Errorf("List() after undo = %v, want %s", tickets, id)
+		}
+
+		if _, err := ctx.exec("undo"); err == nil || !strings.Contains(err.Error(), "trash is empty") {
+			t.Errorf("second undo error = %v", err)
+		}
+	})
+
+	t.Run("warns when links changed", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		ctx.exec("config", "set", "trash", "true")
+		a, _ := ctx.exec("new", "Linked A")
+		a = strings.TrimSpace(a)
+		b, _ := ctx.exec("new", "Linked B")
+		b = strings.TrimSpace(b)
+		ctx.exec("link", a, b)
+
+		if _, err := ctx.exec("rm", "--force", a); err != nil {
+			t.Fatalf("rm --force error: %v", err)
+		}
+		output, err := ctx.exec("undo")
+		if err != nil {
+			t.Fatalf("undo error: %v", err)
+		}
+		if !strings.Contains(output, "Warning: "+a+" links to "+b+", which no longer links back") {
+			t.Errorf("undo output = %q, want link warning", output)
+		}
+	})
+
+	t.Run("deletes without trash by default", func(t *testing.T) {
+		ctx, cleanup := setupTestCmd(t)
+		defer cleanup()
+
+		id, _ := ctx.exec("new", "Gone")
+		ctx.exec("rm", strings.TrimSpace(id))
+
+		if _, err := ctx.exec("undo"); err == nil {
+			t.Error("undo should fail without trash")
+		}
+	})
+}
+
+func TestEmptyTrash(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	ctx.exec("config", "set", "trash", "true")
+	id, _ := ctx.exec("new", "Trashed")
+	ctx.exec("rm", strings.TrimSpace(id))
+
+	output, err := ctx.exec("empty-trash")
+	if err != nil {
+		t.Fatalf("empty-trash error: %v", err)
+	}
+	if !strings.Contains(output, "1 ticket(s) permanently removed") {
+		t.Errorf("output = %q", output)
+	}
+	if _, err := ctx.exec("undo"); err == nil {
+		t.Error("undo after empty-trash should fail")
+	}
+}
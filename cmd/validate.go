@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [--fix-format]",
+	Short: "Check that every ticket file parses",
+	Long: `Check that every ticket file parses and can be safely re-formatted.
+Invalid files are reported and the exit status is non-zero. Files that cannot
+be read, e.g. for lack of permission, are reported as invalid too, although
+other commands merely warn about them.
+
+Use --fix-format to rewrite every valid ticket in canonical form (flow-style
+arrays, standard key order, no stray whitespace). The content is unchanged;
+files already in canonical form are left untouched.`,
+	Args: cobra.NoArgs,
+	RunE: runValidate,
+}
+
+var validateFixFormat bool
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVar(&validateFixFormat, "fix-format", false, "Rewrite valid tickets in canonical format")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	invalid := make(map[string]error)
+	var reformat []string
+	canonical := make(map[string]string)
+	total := 0
+
+	err := store.Walk(func(id, content string) error {
+		total++
+		formatted, err := ticket.Canonicalize(content)
+		if err != nil {
+			invalid[id] = err
+			return nil
+		}
+		if formatted != content {
+			reformat = append(reformat, id)
+			canonical[id] = formatted
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if reporter, ok := store.(ticket.UnreadableReporter); ok {
+		for _, u := range reporter.Unreadable() {
+			total++
+			id := strings.TrimSuffix(filepath.Base(u.Path), ".md")
+			invalid[id] = fmt.Errorf("unreadable %s: %w", u.Path, u.Err)
+		}
+	}
+
+	if validateFixFormat {
+		sort.Strings(reformat)
+		for _, id := range reformat {
+			if err := store.WriteRaw(id, canonical[id]); err != nil {
+				return err
+			}
+			fmt.Printf("Reformatted %s\n", id)
+		}
+		fmt.Printf("Reformatted %d of %d file(s)\n", len(reformat), total)
+	}
+
+	if len(invalid) == 0 {
+		if !validateFixFormat {
+			fmt.Printf("All %d ticket(s) valid\n", total)
+		}
+		return nil
+	}
+
+	ids := make([]string, 0, len(invalid))
+	for id := range invalid {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Invalid %s: %v\n", id, invalid[id])
+	}
+	return failSilently(cmd, exitError)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLegacyEstimateKept tests that a ticket with an estimate written as a
+// duration stays listed and is reported by validate
+func TestLegacyEstimateKept(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	os.MkdirAll(ctx.ticketsDir, 0755)
+	content := "---\nid: est-1\nstatus: open\ndeps: []\nlinks: []\ncreated: 2026-01-02T03:04:05Z\ntype: task\npriority: 2\nestimate: 90m\n---\n# Old estimate\n"
+	path := filepath.Join(ctx.ticketsDir, "est-1.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if output, _ := ctx.exec("ls"); !strings.Contains(output, "est-1") {
+		t.Errorf("ticket with a legacy estimate should be listed, got:\n%s", output)
+	}
+	output, err := ctx.exec("validate", "--fix-format")
+	if err == nil || !strings.Contains(output, "Invalid est-1: estimate '90m' is not whole hours (read as 2)") {
+		t.Errorf("validate should report the estimate, err = %v, output:\n%s", err, output)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("--fix-format should leave the file alone, got:\n%s", data)
+	}
+}
+
+func TestValidateFixFormat(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	canonicalID, _ := ctx.exec("new", "Already canonical")
+	canonicalID = strings.TrimSpace(canonicalID)
+	canonicalPath := filepath.Join(ctx.ticketsDir, canonicalID+".md")
+	before, _ := os.ReadFile(canonicalPath)
+
+	blockPath := filepath.Join(ctx.ticketsDir, "blk-1.md")
+	block := `---
+status: open
+id: blk-1
+deps:
+  - ` + canonicalID + `
+links: []
+created: 2026-01-02T03:04:05Z
+type: task
+priority: 2   
+---
+# Block style
+`
+	if err := os.WriteFile(blockPath, []byte(block), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(ctx.ticketsDir, "bad-1.md"), []byte("---\nid: [\n---\n# Broken\n"), 0644)
+
+	output, err := ctx.exec("validate", "--fix-format")
+	if err == nil {
+		t.Error("expected non-zero exit for the unparseable file")
+	}
+	if !strings.Contains(output, "Reformatted 1 of 3 file(s)") {
+		t.Errorf("expected change count, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Invalid bad-1") {
+		t.Errorf("expected unparseable file to be reported, got:\n%s", output)
+	}
+
+	rewritten, _ := os.ReadFile(blockPath)
+	if !strings.Contains(string(rewritten), "deps: ["+canonicalID+"]\n") {
+		t.Errorf("expected flow-style deps, got:\n%s", rewritten)
+	}
+	if !strings.HasPrefix(string(rewritten), "---\nid: blk-1\nstatus: open\n") || strings.Contains(string(rewritten), "2   \n") {
+		t.Errorf("expected canonical key order without trailing spaces, got:\n%s", rewritten)
+	}
+
+	after, _ := os.ReadFile(canonicalPath)
+	if string(after) != string(before) {
+		t.Errorf("canonical file changed:\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+}
+
+// TestUnreadableTicketFile tests that a ticket file that can't be read is
+// skipped with a warning, but reported as invalid by validate
+func TestUnreadableTicketFile(t *testing.T) {
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	id, _ := ctx.exec("new", "Readable")
+	id = strings.TrimSpace(id)
+	broken := filepath.Join(ctx.ticketsDir, "gone-1.md")
+	if err := os.Symlink(filepath.Join(ctx.ticketsDir, "missing"), broken); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	output, err := ctx.exec("ls")
+	if err != nil {
+		t.Fatalf("ls failed: %v", err)
+	}
+	if !strings.Contains(output, id) {
+		t.Errorf("readable ticket should still be listed, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Warning: skipped unreadable ticket file "+broken) {
+		t.Errorf("should warn about the unreadable file, got:\n%s", output)
+	}
+
+	output, err = ctx.exec("validate")
+	if err == nil {
+		t.Error("validate should fail on an unreadable file")
+	}
+	if !strings.Contains(output, "Invalid gone-1: unreadable") {
+		t.Errorf("validate should report the unreadable file, got:\n%s", output)
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var watchExecCmd = &cobra.Command{
+	Use:   "watch-exec <shell-command>",
+	Short: "Run a command whenever tickets change",
+	Long: `Watch the tickets directory and run a shell command whenever a ticket
+file is created, modified or deleted. The directory is polled every
+--interval; changes are debounced so that a burst of edits runs the command
+once, after nothing has changed for --debounce.
+
+The command runs with sh -c and gets the changed tickets in its environment:
+  TK_CHANGED_ID   the first changed ticket ID
+  TK_CHANGED_IDS  all IDs changed in the burst, space-separated
+
+Stop watching with Ctrl-C.
+  tk watch-exec 'make docs'
+  tk watch-exec 'notify-chat "ticket $TK_CHANGED_ID changed"'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWatchExec,
+}
+
+var (
+	watchExecInterval time.Duration
+	watchExecDebounce time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(watchExecCmd)
+	watchExecCmd.Flags().DurationVar(&watchExecInterval, "interval", 500*time.Millisecond, "How often to poll the tickets directory")
+	watchExecCmd.Flags().DurationVar(&watchExecDebounce, "debounce", time.Second, "Quiet period after a change before the command runs")
+}
+
+func runWatchExec(cmd *cobra.Command, args []string) error {
+	if watchExecInterval <= 0 || watchExecDebounce < 0 {
+		return fmt.Errorf("--interval must be positive and --debounce not negative")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "Watching %s (Ctrl-C to stop)\n", store.Dir())
+	return watchExec(ctx, store, args[0], watchExecInterval, watchExecDebounce, cmd.ErrOrStderr())
+}
+
+// watchExec polls the store's modification times until ctx is done, running
+// command once per debounced burst of changes
+func watchExec(ctx context.Context, s ticket.Store, command string, interval, debounce time.Duration, errOut io.Writer) error {
+	last, err := s.ModTimes()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pending := make(map[string]bool)
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			current, err := s.ModTimes()
+			if err != nil {
+				fmt.Fprintf(errOut, "Warning: %v\n", err)
+				continue
+			}
+			for _, id := range changedIDs(last, current) {
+				pending[id] = true
+				lastChange = now
+			}
+			last = current
+
+			if len(pending) == 0 || now.Sub(lastChange) < debounce {
+				continue
+			}
+			ids := make([]string, 0, len(pending))
+			for id := range pending {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			pending = make(map[string]bool)
+
+			if err := runWatchCommand(ctx, command, ids); err != nil {
+				fmt.Fprintf(errOut, "Warning: %s: %v\n", command, err)
+			}
+		}
+	}
+}
+
+// changedIDs returns the sorted IDs of tickets added, removed or modified
+// between two snapshots of modification times
+func changedIDs(before, after map[string]time.Time) []string {
+	var ids []string
+	for id, mtime := range after {
+		if prev, ok := before[id]; !ok || !prev.Equal(mtime) {
+			ids = append(ids, id)
+		}
+	}
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// runWatchCommand runs command with sh -c, passing the changed IDs in the
+// environment
+func runWatchCommand(ctx context.Context, command string, ids []string) error {
+	c := exec.CommandContext(ctx, "sh", "-c", command)
+	c.Env = append(os.Environ(),
+		"TK_CHANGED_ID="+ids[0],
+		"TK_CHANGED_IDS="+strings.Join(ids, " "),
+	)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestWatchExec(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	ctx, cleanup := setupTestCmd(t)
+	defer cleanup()
+
+	s := ctx.store()
+	if err := s.Create(&ticket.Ticket{ID: "w-old", Status: ticket.StatusOpen, Type: ticket.TypeTask, Title: "Existing"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// The script records each run's changed IDs
+	log := filepath.Join(t.TempDir(), "runs.log")
+	script := filepath.Join(t.TempDir(), "record.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$TK_CHANGED_ID|$TK_CHANGED_IDS\" >> "+log+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watchExec(watchCtx, s, script, 10*time.Millisecond, 200*time.Millisecond, io.Discard)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// A create followed by edits within the debounce window is one burst
+	if err := s.Create(&ticket.Ticket{ID: "w-new", Status: ticket.StatusOpen, Type: ticket.TypeTask, Title: "Created"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, status := range []string{"in_progress", "closed"} {
+		time.Sleep(30 * time.Millisecond)
+		if _, err := s.UpdateField("w-new", "status", status); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+
+	time.Sleep(600 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watchExec error: %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatalf("command did not run: %v", err)
+	}
+	if got := string(data); got != "w-new|w-new\n" {
+		t.Errorf("expected exactly one run for w-new, got %q", got)
+	}
+}
+
+func TestChangedIDs(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	before := map[string]time.Time{"a": t0, "b": t0, "c": t0}
+	after := map[string]time.Time{"a": t0, "b": t0.Add(time.Second), "d": t0}
+
+	got := changedIDs(before, after)
+	want := []string{"b", "c", "d"}
+	if len(got) != len(want) {
+		t.Fatalf("changedIDs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("changedIDs() = %v, want %v", got, want)
+		}
+	}
+}
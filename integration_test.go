@@ -465,6 +465,12 @@ func TestEditWorkflow(t *testing.T) {
 	if !strings.Contains(tk.Body, noteText2) {
 		t.Error("second note should be present")
 	}
+
+	// The appended entries parse back as structured notes
+	notes := tk.Notes()
+	if len(notes) != 2 || notes[0].Text != noteText || notes[1].Text != noteText2 {
+		t.Errorf("Notes() = %+v, want both notes in order", notes)
+	}
 }
 
 // TestTicketFileFormat tests that tickets are written in the correct format
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lo5/tk/internal/ticket"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file inside the tickets directory
+const FileName = "config"
+
+// Config holds settings loaded from the tickets directory's config file
+type Config struct {
+	// ExternalURL is a URL template for external references, e.g.
+	// https://github.com/org/repo/issues/{ref}
+	ExternalURL string `yaml:"external_url,omitempty"`
+
+	// DefaultPriority is the priority given to new tickets when --priority
+	// is not passed. Nil when unset.
+	DefaultPriority *int `yaml:"default_priority,omitempty"`
+
+	// AssignOnStart assigns unassigned tickets to the current user on start
+	AssignOnStart bool `yaml:"assign_on_start,omitempty"`
+
+	// UnassignOnClose clears the assignee when a ticket is closed
+	UnassignOnClose bool `yaml:"unassign_on_close,omitempty"`
+
+	// MaxTitleLength caps the number of characters in a title. Zero means
+	// no limit.
+	MaxTitleLength int `yaml:"max_title_length,omitempty"`
+
+	// Sharded stores tickets in subdirectories named after their ID
+	// prefix, e.g. ab/ab-1234.md, instead of one flat directory
+	Sharded bool `yaml:"sharded,omitempty"`
+
+	// Trash makes rm and clean --fix move tickets to the trash directory,
+	// where undo can restore them, instead of deleting them
+	Trash bool `yaml:"trash,omitempty"`
+
+	// PriorityKeywords infers the priority of new tickets from their title
+	// when --priority is not passed. The first matching keyword wins.
+	PriorityKeywords []PriorityKeyword `yaml:"priority_keywords,omitempty"`
+
+	// Views maps saved view names to jq filters for query --view
+	Views map[string]string `yaml:"views,omitempty"`
+
+	// Unknown lists the top-level keys in the file that tk doesn't use,
+	// which are usually typos
+	Unknown []string `yaml:"-"`
+}
+
+// PriorityKeyword maps a title substring to a priority
+type PriorityKeyword struct {
+	Keyword  string `yaml:"keyword"`
+	Priority int    `yaml:"priority"`
+}
+
+// InferPriority returns the priority of the first keyword found in the
+// title, ignoring case, and whether any matched
+func (c *Config) InferPriority(title string) (int, bool) {
+	lower := strings.ToLower(title)
+	for _, k := range c.PriorityKeywords {
+		if k.Keyword != "" && strings.Contains(lower, strings.ToLower(k.Keyword)) {
+			return k.Priority, true
+		}
+	}
+	return 0, false
+}
+
+// CheckTitle returns an error if the title exceeds MaxTitleLength
+func (c *Config) CheckTitle(title string) error {
+	if c.MaxTitleLength <= 0 {
+		return nil
+	}
+	if n := utf8.RuneCountInString(title); n > c.MaxTitleLength {
+		return fmt.Errorf("title is %d characters, exceeding max_title_length of %d", n, c.MaxTitleLength)
+	}
+	return nil
+}
+
+// View returns the filter of a saved view
+func (c *Config) View(name string) (string, error) {
+	filter, ok := c.Views[name]
+	if !ok {
+		return "", fmt.Errorf("unknown view %q", name)
+	}
+	return filter, nil
+}
+
+// OpenStore returns a FileStore for the tickets directory with the layout
+// its config selects. If the config can't be read, a flat store is returned
+// along with the error.
+func OpenStore(ticketsDir string) (*ticket.FileStore, error) {
+	cfg, err := Load(ticketsDir)
+	if err != nil {
+		return ticket.NewFileStore(ticketsDir), err
+	}
+	if cfg.Sharded {
+		return ticket.NewShardedFileStore(ticketsDir), nil
+	}
+	return ticket.NewFileStore(ticketsDir), nil
+}
+
+// Path returns the config file path for a tickets directory
+func Path(ticketsDir string) string {
+	return filepath.Join(ticketsDir, FileName)
+}
+
+// Load reads the config file from the tickets directory.
+// A missing file yields an empty configuration.
+func Load(ticketsDir string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(Path(ticketsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return cfg, nil
+	}
+	if err := doc.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	cfg.Unknown = unknownKeys(doc.Content[0])
+	for _, k := range cfg.PriorityKeywords {
+		if k.Priority < 0 || k.Priority > 4 {
+			return nil, fmt.Errorf("parsing config: priority_keywords: priority of %q must be 0-4", k.Keyword)
+		}
+	}
+
+	return cfg, nil
+}
+
+// unknownKeys returns the keys of a config mapping that no Config field reads
+func unknownKeys(root *yaml.Node) []string {
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	typ := reflect.TypeOf(Config{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+
+	var unknown []string
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if key := root.Content[i].Value; !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	return unknown
+}
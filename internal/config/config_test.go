@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoad tests loading the config file
+func TestLoad(t *testing.T) {
+	t.Run("missing file yields empty config", func(t *testing.T) {
+		cfg, err := Load(filepath.Join(t.TempDir(), "missing"))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.ExternalURL != "" {
+			t.Errorf("ExternalURL = %q, want empty", cfg.ExternalURL)
+		}
+	})
+
+	t.Run("reads values", func(t *testing.T) {
+		dir := t.TempDir()
+		content := "external_url: https://example.com/issues/{ref}\n"
+		if err := os.WriteFile(Path(dir), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.ExternalURL != "https://example.com/issues/{ref}" {
+			t.Errorf("ExternalURL = %q", cfg.ExternalURL)
+		}
+	})
+
+	t.Run("reports unknown keys", func(t *testing.T) {
+		dir := t.TempDir()
+		content := "shardded: true\ntrash: true\nviews:\n  mine: .\n"
+		if err := os.WriteFile(Path(dir), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(dir)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(cfg.Unknown) != 1 || cfg.Unknown[0] != "shardded" {
+			t.Errorf("Unknown = %v, want [shardded]", cfg.Unknown)
+		}
+		if cfg.Sharded || !cfg.Trash {
+			t.Errorf("Sharded = %v, Trash = %v, want false, true", cfg.Sharded, cfg.Trash)
+		}
+	})
+
+	t.Run("invalid yaml errors", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(Path(dir), []byte("external_url: [unclosed\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(dir); err == nil {
+			t.Error("expected error for invalid config")
+		}
+	})
+}
+
+// TestSet tests validating and persisting values
+func TestSet(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Set(dir, "default_priority", "3"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := Set(dir, "external_url", "https://example.com/{ref}"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.DefaultPriority == nil || *cfg.DefaultPriority != 3 {
+		t.Errorf("DefaultPriority = %v, want 3", cfg.DefaultPriority)
+	}
+	if cfg.ExternalURL != "https://example.com/{ref}" {
+		t.Errorf("ExternalURL = %q", cfg.ExternalURL)
+	}
+
+	if err := Set(dir, "default_priority", "high"); err == nil {
+		t.Error("expected error for non-numeric priority")
+	}
+	if err := Set(dir, "external_url", "https://example.com"); err == nil {
+		t.Error("expected error for template without {ref}")
+	}
+
+	value, ok, err := Get(dir, "default_priority")
+	if err != nil || !ok || value != "3" {
+		t.Errorf("Get = (%q, %v, %v), want (3, true, nil)", value, ok, err)
+	}
+}
+
+// TestSetKeepsFile tests that Set leaves comments and key order alone
+func TestSetKeepsFile(t *testing.T) {
+	dir := t.TempDir()
+	content := `# Team settings
+trash: true # keep deleted tickets
+default_priority: 2
+external_url: https://example.com/{ref}
+`
+	if err := os.WriteFile(Path(dir), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Set(dir, "default_priority", "1"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := Set(dir, "sharded", "true"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := SaveView(dir, "mine", ".assignee == \"me\""); err != nil {
+		t.Fatalf("SaveView failed: %v", err)
+	}
+
+	data, err := os.ReadFile(Path(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Team settings
+trash: true # keep deleted tickets
+default_priority: 1
+external_url: https://example.com/{ref}
+sharded: true
+views:
+    mine: .assignee == "me"
+`
+	if string(data) != want {
+		t.Errorf("config file =\n%s\nwant\n%s", data, want)
+	}
+}
+
+// TestInferPriority tests title keyword matching
+func TestInferPriority(t *testing.T) {
+	cfg := &Config{PriorityKeywords: []PriorityKeyword{
+		{Keyword: "Urgent", Priority: 1},
+		{Keyword: "critical", Priority: 0},
+	}}
+
+	tests := []struct {
+		title string
+		want  int
+		ok    bool
+	}{
+		{"Fix critical login bug", 0, true},
+		{"URGENT: critical outage", 1, true}, // First keyword wins
+		{"Tidy docs", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := cfg.InferPriority(tt.title)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("InferPriority(%q) = %d, %v, want %d, %v", tt.title, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	t.Run("loads from config and validates", func(t *testing.T) {
+		dir := t.TempDir()
+		content := "priority_keywords:\n  - keyword: critical\n    priority: 7\n"
+		if err := os.WriteFile(Path(dir), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); err == nil {
+			t.Error("expected error for out-of-range priority")
+		}
+	})
+}
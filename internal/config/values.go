@@ -0,0 +1,278 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Key describes a known configuration key
+type Key struct {
+	Name        string
+	Description string
+	// Parse validates a raw value and converts it to the type stored in the file
+	Parse func(value string) (interface{}, error)
+}
+
+// Keys lists the known configuration keys
+var Keys = []Key{
+	{
+		Name:        "external_url",
+		Description: "URL template for external references, containing {ref}",
+		Parse: func(value string) (interface{}, error) {
+			if !strings.Contains(value, "{ref}") {
+				return nil, fmt.Errorf("must contain {ref}")
+			}
+			return value, nil
+		},
+	},
+	{
+		Name:        "default_priority",
+		Description: "Priority 0-4 given to new tickets",
+		Parse: func(value string) (interface{}, error) {
+			p, err := strconv.Atoi(value)
+			if err != nil || p < 0 || p > 4 {
+				return nil, fmt.Errorf("must be 0-4")
+			}
+			return p, nil
+		},
+	},
+	{
+		Name:        "assign_on_start",
+		Description: "Assign unassigned tickets to the current user on start (true/false)",
+		Parse:       parseBool,
+	},
+	{
+		Name:        "unassign_on_close",
+		Description: "Clear the assignee when a ticket is closed (true/false)",
+		Parse:       parseBool,
+	},
+	{
+		Name:        "max_title_length",
+		Description: "Maximum number of characters in a ticket title",
+		Parse: func(value string) (interface{}, error) {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("must be a positive integer")
+			}
+			return n, nil
+		},
+	},
+	{
+		Name:        "sharded",
+		Description: "Store tickets in subdirectories named after their ID prefix (true/false)",
+		Parse:       parseBool,
+	},
+	{
+		Name:        "trash",
+		Description: "Move deleted tickets to .tickets/.trash/ so undo can restore them (true/false)",
+		Parse:       parseBool,
+	},
+}
+
+// parseBool accepts the boolean spellings understood by strconv
+func parseBool(value string) (interface{}, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("must be true or false")
+	}
+	return b, nil
+}
+
+// LookupKey returns the known key with the given name
+func LookupKey(name string) (Key, bool) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// Values reads the raw key/value pairs of the config file.
+// A missing file yields an empty map.
+func Values(ticketsDir string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	data, err := os.ReadFile(Path(ticketsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	return values, nil
+}
+
+// Get returns the value of a key formatted as a string, and whether it is set
+func Get(ticketsDir, name string) (string, bool, error) {
+	values, err := Values(ticketsDir)
+	if err != nil {
+		return "", false, err
+	}
+
+	v, ok := values[name]
+	if !ok {
+		return "", false, nil
+	}
+	return FormatValue(v), true, nil
+}
+
+// Set validates and stores a value. Known keys are converted to their
+// stored type; unknown keys are stored as strings. The rest of the file,
+// including comments and key order, is kept as written.
+func Set(ticketsDir, name, value string) error {
+	var stored interface{} = value
+	if key, ok := LookupKey(name); ok {
+		parsed, err := key.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s: %w", value, name, err)
+		}
+		stored = parsed
+	}
+
+	node, err := encodeValue(stored)
+	if err != nil {
+		return err
+	}
+	return edit(ticketsDir, func(root *yaml.Node) {
+		setValue(root, name, node)
+	})
+}
+
+// SaveView stores a jq filter under a view name in the views map,
+// replacing any view of the same name
+func SaveView(ticketsDir, name, filter string) error {
+	node, err := encodeValue(filter)
+	if err != nil {
+		return err
+	}
+	return edit(ticketsDir, func(root *yaml.Node) {
+		views := lookupValue(root, "views")
+		if views == nil || views.Kind != yaml.MappingNode {
+			views = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setValue(root, "views", views)
+		}
+		setValue(views, name, node)
+	})
+}
+
+// SortedNames returns the keys of a values map in sorted order
+func SortedNames(values map[string]interface{}) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatValue renders a config value for display. Scalars are printed as-is;
+// nested values are printed as inline YAML.
+func FormatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int, bool, float64:
+		return fmt.Sprint(v)
+	}
+
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// encodeValue converts a value to a YAML node
+func encodeValue(v interface{}) (*yaml.Node, error) {
+	node := &yaml.Node{}
+	if err := node.Encode(v); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	return node, nil
+}
+
+// lookupValue returns the value node of a key in a mapping, or nil
+func lookupValue(mapping *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == name {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setValue replaces the value of a key in a mapping, keeping the comments
+// around the old value, or appends the key if it isn't there
+func setValue(mapping *yaml.Node, name string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != name {
+			continue
+		}
+		old := mapping.Content[i+1]
+		value.HeadComment = old.HeadComment
+		value.LineComment = old.LineComment
+		value.FootComment = old.FootComment
+		mapping.Content[i+1] = value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+	mapping.Content = append(mapping.Content, key, value)
+}
+
+// edit applies a change to the top-level mapping of the config file and
+// writes it back atomically. Editing the node tree rather than a decoded
+// map keeps the file's comments and key order.
+func edit(ticketsDir string, change func(root *yaml.Node)) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(Path(ticketsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		// Empty or comment-only file
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("parsing config: expected key: value pairs")
+	}
+	change(root)
+
+	data, err = yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	if err := os.MkdirAll(ticketsDir, 0755); err != nil {
+		return fmt.Errorf("creating tickets directory: %w", err)
+	}
+
+	path := Path(ticketsDir)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package deptree
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// Cycle is a closed dependency path: each ticket depends on the next, and the
+// last depends on the first. A self-dependency is a cycle of one ticket.
+type Cycle []string
+
+// String renders the cycle as "a -> b -> c -> a"
+func (c Cycle) String() string {
+	return strings.Join(append(append([]string{}, c...), c[0]), " -> ")
+}
+
+// SelfDependency reports whether the cycle is a ticket listing itself in deps
+func (c Cycle) SelfDependency() bool {
+	return len(c) == 1
+}
+
+// FindCycles detects dependency cycles with a depth-first search that keeps
+// the current path on a recursion stack: a dep already on the stack closes a
+// cycle. Every self-dependency is reported; otherwise each back edge yields
+// one cycle, so a tangle of cycles reports at least one per tangle. Deps on
+// missing tickets are ignored. The search visits IDs in sorted order so the
+// result is stable.
+func FindCycles(tickets []*ticket.Ticket) []Cycle {
+	deps := make(map[string][]string, len(tickets))
+	var ids []string
+	for _, t := range tickets {
+		deps[t.ID] = t.Deps
+		ids = append(ids, t.ID)
+	}
+	sort.Strings(ids)
+
+	var cycles []Cycle
+	done := make(map[string]bool)
+	onStack := make(map[string]int) // ID -> index in stack
+	var stack []string
+
+	var visit func(id string)
+	visit = func(id string) {
+		onStack[id] = len(stack)
+		stack = append(stack, id)
+
+		for _, dep := range deps[id] {
+			if dep == id {
+				continue // Reported as a self-dependency
+			}
+			if _, exists := deps[dep]; !exists || done[dep] {
+				continue
+			}
+			if i, ok := onStack[dep]; ok {
+				cycles = append(cycles, append(Cycle{}, stack[i:]...))
+				continue
+			}
+			visit(dep)
+		}
+
+		stack = stack[:len(stack)-1]
+		delete(onStack, id)
+		done[id] = true
+	}
+
+	for _, id := range ids {
+		for _, dep := range deps[id] {
+			if dep == id {
+				cycles = append(cycles, Cycle{id})
+				break
+			}
+		}
+	}
+	for _, id := range ids {
+		if !done[id] {
+			visit(id)
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,67 @@
+package deptree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestFindCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		tickets []*ticket.Ticket
+		want    []string
+	}{
+		{
+			name: "acyclic",
+			tickets: []*ticket.Ticket{
+				createTestTicket("a", "A", ticket.StatusOpen, []string{"b"}),
+				createTestTicket("b", "B", ticket.StatusOpen, []string{"c"}),
+				createTestTicket("c", "C", ticket.StatusOpen, nil),
+				createTestTicket("d", "D", ticket.StatusOpen, []string{"b", "c"}),
+			},
+			want: nil,
+		},
+		{
+			name: "three ticket cycle",
+			tickets: []*ticket.Ticket{
+				createTestTicket("c", "C", ticket.StatusOpen, []string{"a"}),
+				createTestTicket("a", "A", ticket.StatusOpen, []string{"b"}),
+				createTestTicket("b", "B", ticket.StatusOpen, []string{"c"}),
+				createTestTicket("d", "D", ticket.StatusOpen, []string{"a"}),
+			},
+			want: []string{"a -> b -> c -> a"},
+		},
+		{
+			name: "self-dependency",
+			tickets: []*ticket.Ticket{
+				createTestTicket("a", "A", ticket.StatusOpen, []string{"a", "b"}),
+				createTestTicket("b", "B", ticket.StatusOpen, nil),
+			},
+			want: []string{"a -> a"},
+		},
+		{
+			name: "separate cycles and missing deps",
+			tickets: []*ticket.Ticket{
+				createTestTicket("a", "A", ticket.StatusOpen, []string{"b", "gone"}),
+				createTestTicket("b", "B", ticket.StatusOpen, []string{"a"}),
+				createTestTicket("x", "X", ticket.StatusOpen, []string{"y"}),
+				createTestTicket("y", "Y", ticket.StatusOpen, []string{"x"}),
+			},
+			want: []string{"a -> b -> a", "x -> y -> x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range FindCycles(tt.tickets) {
+				got = append(got, c.String())
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("FindCycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package deptree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// statusColors are the DOT fill colors of ticket nodes by status
+var statusColors = map[ticket.Status]string{
+	ticket.StatusOpen:       "white",
+	ticket.StatusInProgress: "lightblue",
+	ticket.StatusClosed:     "lightgray",
+}
+
+// DOT renders tickets and their dependencies as a Graphviz digraph. Nodes
+// are filled by status; edges point from a ticket to the tickets it depends
+// on. Dependencies on tickets that are not in the list are drawn as bare
+// nodes.
+func DOT(tickets []*ticket.Ticket) string {
+	sorted := make([]*ticket.Ticket, len(tickets))
+	copy(sorted, tickets)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var b strings.Builder
+	b.WriteString("digraph tickets {\n")
+	b.WriteString("  node [shape=box, style=filled, fillcolor=white];\n")
+	for _, t := range sorted {
+		title := strings.ReplaceAll(t.Title, `\`, `\\`)
+		label := fmt.Sprintf("%s [%s]\\n%s", t.ID, t.Status, title)
+		color, ok := statusColors[t.Status]
+		if !ok {
+			color = "white"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%s];\n", dotQuote(t.ID), dotQuote(label), color)
+	}
+	for _, t := range sorted {
+		for _, dep := range t.Deps {
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(t.ID), dotQuote(dep))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes a string as a DOT identifier, keeping \n line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,30 @@
+package deptree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestDOT(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "b-2", Status: ticket.StatusOpen, Title: `Say "hi"`, Deps: []string{"a-1"}},
+		{ID: "a-1", Status: ticket.StatusClosed, Title: "Base"},
+	}
+
+	want := `digraph tickets {
+  node [shape=box, style=filled, fillcolor=white];
+  "a-1" [label="a-1 [closed]\nBase", fillcolor=lightgray];
+  "b-2" [label="b-2 [open]\nSay \"hi\"", fillcolor=white];
+  "b-2" -> "a-1";
+}
+`
+	if got := DOT(tickets); got != want {
+		t.Errorf("DOT() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := DOT(nil); !strings.HasPrefix(got, "digraph tickets {") {
+		t.Errorf("DOT(nil) = %q, want an empty digraph", got)
+	}
+}
@@ -0,0 +1,220 @@
+package deptree
+
+import (
+	"sort"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// DependencyGraph indexes a ticket list by ID, dependants and parent so that
+// relationship queries don't rescan every ticket. Build it once per List().
+type DependencyGraph struct {
+	tickets    []*ticket.Ticket
+	byID       map[string]*ticket.Ticket
+	dependants map[string][]*ticket.Ticket
+	children   map[string][]*ticket.Ticket
+	closed     map[string]bool // tickets treated as closed by AssumeClosed
+}
+
+// NewGraph builds a dependency graph from a ticket list. Query results keep
+// the order of the list.
+func NewGraph(tickets []*ticket.Ticket) *DependencyGraph {
+	g := &DependencyGraph{
+		tickets:    tickets,
+		byID:       make(map[string]*ticket.Ticket, len(tickets)),
+		dependants: make(map[string][]*ticket.Ticket),
+		children:   make(map[string][]*ticket.Ticket),
+	}
+	for _, t := range tickets {
+		g.byID[t.ID] = t
+		if t.Parent != "" {
+			g.children[t.Parent] = append(g.children[t.Parent], t)
+		}
+		seen := make(map[string]bool, len(t.Deps))
+		for _, dep := range t.Deps {
+			if dep == t.ID || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			g.dependants[dep] = append(g.dependants[dep], t)
+		}
+	}
+	return g
+}
+
+// NewGraphFromMap builds a dependency graph from tickets keyed by ID, as
+// returned by Store.ListParsed. Query results are in ID order.
+func NewGraphFromMap(tickets map[string]*ticket.Ticket) *DependencyGraph {
+	list := make([]*ticket.Ticket, 0, len(tickets))
+	for _, t := range tickets {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return NewGraph(list)
+}
+
+// Tickets returns the tickets the graph was built from
+func (g *DependencyGraph) Tickets() []*ticket.Ticket {
+	return g.tickets
+}
+
+// Ticket returns the ticket with the given ID
+func (g *DependencyGraph) Ticket(id string) (*ticket.Ticket, bool) {
+	t, ok := g.byID[id]
+	return t, ok
+}
+
+// AssumeClosed makes readiness queries treat the given tickets as closed
+// without changing them, for what-if planning
+func (g *DependencyGraph) AssumeClosed(ids []string) {
+	if g.closed == nil {
+		g.closed = make(map[string]bool)
+	}
+	for _, id := range ids {
+		g.closed[id] = true
+	}
+}
+
+// Status returns a ticket's status, taking AssumeClosed into account.
+// Unknown tickets have an empty status.
+func (g *DependencyGraph) Status(id string) ticket.Status {
+	if g.closed[id] {
+		return ticket.StatusClosed
+	}
+	if t, ok := g.byID[id]; ok {
+		return t.Status
+	}
+	return ""
+}
+
+// Blockers returns the dependencies of a ticket that are not closed,
+// including IDs of dependencies that no longer exist
+func (g *DependencyGraph) Blockers(id string) []string {
+	t, ok := g.byID[id]
+	if !ok {
+		return nil
+	}
+	var blockers []string
+	for _, dep := range t.Deps {
+		if g.Status(dep) != ticket.StatusClosed {
+			blockers = append(blockers, dep)
+		}
+	}
+	return blockers
+}
+
+// Dependants returns the tickets that list id as a dependency
+func (g *DependencyGraph) Dependants(id string) []*ticket.Ticket {
+	return g.dependants[id]
+}
+
+// TransitiveDeps returns every ticket that id directly or indirectly depends
+// on, in breadth-first order. Missing dependencies are skipped.
+func (g *DependencyGraph) TransitiveDeps(id string) []*ticket.Ticket {
+	var result []*ticket.Ticket
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+
+	for len(queue) > 0 {
+		current, ok := g.byID[queue[0]]
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, dep := range current.Deps {
+			t, ok := g.byID[dep]
+			if !ok || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			result = append(result, t)
+			queue = append(queue, dep)
+		}
+	}
+	return result
+}
+
+// TransitiveDependants returns every ticket that directly or indirectly
+// depends on id, in breadth-first order. Adding any of them as a dependency
+// of id would create a cycle.
+func (g *DependencyGraph) TransitiveDependants(id string) []*ticket.Ticket {
+	var result []*ticket.Ticket
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, t := range g.dependants[current] {
+			if seen[t.ID] {
+				continue
+			}
+			seen[t.ID] = true
+			result = append(result, t)
+			queue = append(queue, t.ID)
+		}
+	}
+	return result
+}
+
+// DepCandidates returns the tickets that could be added as dependencies of
+// id without creating a cycle: everything except id itself, its transitive
+// dependants and its existing dependencies, in list order
+func (g *DependencyGraph) DepCandidates(id string) []*ticket.Ticket {
+	excluded := map[string]bool{id: true}
+	for _, t := range g.TransitiveDependants(id) {
+		excluded[t.ID] = true
+	}
+	if t, ok := g.byID[id]; ok {
+		for _, dep := range t.Deps {
+			excluded[dep] = true
+		}
+	}
+
+	var candidates []*ticket.Ticket
+	for _, t := range g.tickets {
+		if !excluded[t.ID] {
+			candidates = append(candidates, t)
+		}
+	}
+	return candidates
+}
+
+// Children returns the tickets whose parent is id
+func (g *DependencyGraph) Children(id string) []*ticket.Ticket {
+	return g.children[id]
+}
+
+// IsReady reports whether a ticket is open or in progress with every
+// dependency closed
+func (g *DependencyGraph) IsReady(id string) bool {
+	status := g.Status(id)
+	if status != ticket.StatusOpen && status != ticket.StatusInProgress {
+		return false
+	}
+	return len(g.Blockers(id)) == 0
+}
+
+// IsBlocked reports whether a ticket is open or in progress with an
+// unclosed or missing dependency. Every unclosed ticket is either ready or
+// blocked.
+func (g *DependencyGraph) IsBlocked(id string) bool {
+	status := g.Status(id)
+	if status != ticket.StatusOpen && status != ticket.StatusInProgress {
+		return false
+	}
+	return len(g.Blockers(id)) > 0
+}
+
+// Ready returns every ready ticket, in list order
+func (g *DependencyGraph) Ready() []*ticket.Ticket {
+	var ready []*ticket.Ticket
+	for _, t := range g.tickets {
+		if g.IsReady(t.ID) {
+			ready = append(ready, t)
+		}
+	}
+	return ready
+}
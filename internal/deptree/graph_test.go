@@ -0,0 +1,149 @@
+package deptree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	// epic is the parent of a and b; b depends on a (open) and done (closed);
+	// c depends on done and a missing ticket
+	tickets := []*ticket.Ticket{
+		{ID: "epic", Status: ticket.StatusOpen},
+		{ID: "a", Status: ticket.StatusOpen, Parent: "epic"},
+		{ID: "b", Status: ticket.StatusInProgress, Parent: "epic", Deps: []string{"a", "done"}},
+		{ID: "c", Status: ticket.StatusOpen, Deps: []string{"done", "gone"}},
+		{ID: "done", Status: ticket.StatusClosed},
+	}
+	g := NewGraph(tickets)
+
+	ids := func(ts []*ticket.Ticket) string {
+		var out []string
+		for _, t := range ts {
+			out = append(out, t.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := strings.Join(g.Blockers("b"), ","); got != "a" {
+		t.Errorf("Blockers(b) = %q, want a", got)
+	}
+	if got := strings.Join(g.Blockers("c"), ","); got != "gone" {
+		t.Errorf("Blockers(c) = %q, want the missing dep", got)
+	}
+	if got := ids(g.Dependants("done")); got != "b,c" {
+		t.Errorf("Dependants(done) = %q, want b,c", got)
+	}
+	if got := ids(g.Children("epic")); got != "a,b" {
+		t.Errorf("Children(epic) = %q, want a,b", got)
+	}
+	if got := ids(g.Children("a")); got != "" {
+		t.Errorf("Children(a) = %q, want none", got)
+	}
+
+	ready := map[string]bool{"epic": true, "a": true, "b": false, "c": false, "done": false, "unknown": false}
+	for id, want := range ready {
+		if got := g.IsReady(id); got != want {
+			t.Errorf("IsReady(%s) = %v, want %v", id, got, want)
+		}
+	}
+	if got := ids(g.Ready()); got != "epic,a" {
+		t.Errorf("Ready() = %q, want epic,a", got)
+	}
+
+	blocked := map[string]bool{"epic": false, "a": false, "b": true, "c": true, "done": false, "unknown": false}
+	for id, want := range blocked {
+		if got := g.IsBlocked(id); got != want {
+			t.Errorf("IsBlocked(%s) = %v, want %v", id, got, want)
+		}
+	}
+
+	if _, ok := g.Ticket("gone"); ok {
+		t.Error("Ticket(gone) should not be found")
+	}
+}
+
+func TestDependencyGraphAssumeClosed(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "blocker", Status: ticket.StatusInProgress},
+		{ID: "dependant", Status: ticket.StatusOpen, Deps: []string{"blocker"}},
+	}
+	g := NewGraph(tickets)
+	if g.IsReady("dependant") {
+		t.Fatal("dependant should start blocked")
+	}
+
+	g.AssumeClosed([]string{"blocker"})
+	if !g.IsReady("dependant") {
+		t.Error("dependant should be ready with its blocker assumed closed")
+	}
+	if g.IsReady("blocker") || g.Status("blocker") != ticket.StatusClosed {
+		t.Error("assumed-closed ticket should report closed and not ready")
+	}
+	if tickets[0].Status != ticket.StatusInProgress {
+		t.Error("AssumeClosed must not modify tickets")
+	}
+}
+
+func TestDepCandidates(t *testing.T) {
+	// c depends on b, b depends on a; d depends on x, which is unrelated to a
+	tickets := []*ticket.Ticket{
+		{ID: "a", Status: ticket.StatusOpen},
+		{ID: "b", Status: ticket.StatusOpen, Deps: []string{"a"}},
+		{ID: "c", Status: ticket.StatusOpen, Deps: []string{"b"}},
+		{ID: "d", Status: ticket.StatusOpen, Deps: []string{"x"}},
+		{ID: "x", Status: ticket.StatusClosed},
+	}
+	g := NewGraph(tickets)
+
+	ids := func(ts []*ticket.Ticket) string {
+		var out []string
+		for _, t := range ts {
+			out = append(out, t.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(g.TransitiveDependants("a")); got != "b,c" {
+		t.Errorf("TransitiveDependants(a) = %q, want b,c", got)
+	}
+	if got := ids(g.TransitiveDeps("c")); got != "b,a" {
+		t.Errorf("TransitiveDeps(c) = %q, want b,a", got)
+	}
+	if got := ids(g.TransitiveDeps("a")); got != "" {
+		t.Errorf("TransitiveDeps(a) = %q, want none", got)
+	}
+	if got := ids(g.DepCandidates("a")); got != "d,x" {
+		t.Errorf("DepCandidates(a) = %q, want d,x", got)
+	}
+	// Existing dependencies are not offered again
+	if got := ids(g.DepCandidates("c")); got != "a,d,x" {
+		t.Errorf("DepCandidates(c) = %q, want a,d,x", got)
+	}
+}
+
+func TestNewGraphFromMap(t *testing.T) {
+	g := NewGraphFromMap(map[string]*ticket.Ticket{
+		"c": {ID: "c", Status: ticket.StatusOpen, Deps: []string{"a"}},
+		"a": {ID: "a", Status: ticket.StatusOpen},
+		"b": {ID: "b", Status: ticket.StatusOpen, Deps: []string{"a"}},
+	})
+
+	var ids []string
+	for _, tk := range g.Tickets() {
+		ids = append(ids, tk.ID)
+	}
+	if got := strings.Join(ids, ","); got != "a,b,c" {
+		t.Errorf("Tickets() = %s, want a,b,c", got)
+	}
+
+	var dependants []string
+	for _, tk := range g.Dependants("a") {
+		dependants = append(dependants, tk.ID)
+	}
+	if got := strings.Join(dependants, ","); got != "b,c" {
+		t.Errorf("Dependants(a) = %s, want b,c", got)
+	}
+}
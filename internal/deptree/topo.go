@@ -0,0 +1,79 @@
+package deptree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError reports tickets that could not be ordered because they are part
+// of, or depend on, a dependency cycle
+type CycleError struct {
+	IDs []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle among: %s", strings.Join(e.IDs, ", "))
+}
+
+// TopoSort orders ids so that every dependency comes before its dependants,
+// using Kahn's algorithm. deps maps an ID to the IDs it depends on; deps
+// outside ids are ignored. Among tickets that are ready at the same time,
+// less decides the order. If the graph has a cycle, the ordered prefix is
+// returned together with a *CycleError listing the remaining IDs.
+func TopoSort(ids []string, deps map[string][]string, less func(a, b string) bool) ([]string, error) {
+	included := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		included[id] = true
+	}
+
+	// Count unresolved deps and index dependants
+	inDegree := make(map[string]int, len(ids))
+	dependants := make(map[string][]string)
+	for _, id := range ids {
+		seen := make(map[string]bool)
+		for _, dep := range deps[id] {
+			if !included[dep] || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			inDegree[id]++
+			dependants[dep] = append(dependants[dep], id)
+		}
+	}
+
+	var ready []string
+	for _, id := range ids {
+		if inDegree[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	order := make([]string, 0, len(ids))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+
+		for _, d := range dependants[id] {
+			inDegree[d]--
+			if inDegree[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(order) < len(ids) {
+		var remaining []string
+		for _, id := range ids {
+			if inDegree[id] > 0 {
+				remaining = append(remaining, id)
+			}
+		}
+		sort.Strings(remaining)
+		return order, &CycleError{IDs: remaining}
+	}
+
+	return order, nil
+}
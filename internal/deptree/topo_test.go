@@ -0,0 +1,79 @@
+package deptree
+
+import (
+	"errors"
+	"testing"
+)
+
+func byID(a, b string) bool { return a < b }
+
+// assertValidOrder checks every dependency appears before its dependant
+func assertValidOrder(t *testing.T, order []string, deps map[string][]string) {
+	t.Helper()
+	pos := make(map[string]int)
+	for i, id := range order {
+		pos[id] = i
+	}
+	for id, ds := range deps {
+		for _, d := range ds {
+			if pos[d] > pos[id] {
+				t.Errorf("%s appears before its dependency %s in %v", id, d, order)
+			}
+		}
+	}
+}
+
+func TestTopoSortLinearChain(t *testing.T) {
+	deps := map[string][]string{"c": {"b"}, "b": {"a"}}
+	order, err := TopoSort([]string{"c", "b", "a"}, deps, byID)
+	if err != nil {
+		t.Fatalf("TopoSort() error = %v", err)
+	}
+	if len(order) != 3 || order[0] != "a" || order[1] != "b" || order[2] != "c" {
+		t.Errorf("TopoSort() = %v, want [a b c]", order)
+	}
+}
+
+func TestTopoSortDiamond(t *testing.T) {
+	deps := map[string][]string{"d": {"b", "c"}, "b": {"a"}, "c": {"a"}}
+	order, err := TopoSort([]string{"a", "b", "c", "d"}, deps, byID)
+	if err != nil {
+		t.Fatalf("TopoSort() error = %v", err)
+	}
+	if len(order) != 4 || order[0] != "a" || order[3] != "d" {
+		t.Errorf("TopoSort() = %v, want a first and d last", order)
+	}
+	assertValidOrder(t, order, deps)
+}
+
+func TestTopoSortTieBreak(t *testing.T) {
+	// Reverse ID order as the tie-breaker
+	order, _ := TopoSort([]string{"a", "b", "c"}, nil, func(a, b string) bool { return a > b })
+	if order[0] != "c" || order[2] != "a" {
+		t.Errorf("TopoSort() = %v, want tie-breaker order [c b a]", order)
+	}
+}
+
+func TestTopoSortIgnoresExternalDeps(t *testing.T) {
+	deps := map[string][]string{"b": {"a", "closed-1"}}
+	order, err := TopoSort([]string{"a", "b"}, deps, byID)
+	if err != nil || len(order) != 2 {
+		t.Errorf("TopoSort() = %v, %v; deps outside the set should be ignored", order, err)
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	deps := map[string][]string{"a": {"c"}, "b": {"a"}, "c": {"b"}, "d": {"c"}}
+	order, err := TopoSort([]string{"a", "b", "c", "d", "e"}, deps, byID)
+
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected CycleError, got %v", err)
+	}
+	if len(cycleErr.IDs) != 4 {
+		t.Errorf("CycleError.IDs = %v, want a, b, c, d", cycleErr.IDs)
+	}
+	if len(order) != 1 || order[0] != "e" {
+		t.Errorf("ordered prefix = %v, want [e]", order)
+	}
+}
@@ -13,38 +13,102 @@ type Node struct {
 	ID           string
 	Status       ticket.Status
 	Title        string
-	Deps         []string
+	Deps         []string // Child nodes: dependencies, or dependants in a reverse tree
+	Estimate     int
 	MaxDepth     int // Maximum depth at which this node appears
 	SubtreeDepth int // Maximum depth in this node's subtree
 }
 
+// Connectors holds the strings used to draw the branches of a tree
+type Connectors struct {
+	Branch string // Connector for a child that has later siblings
+	Last   string // Connector for the last child
+	Pipe   string // Indentation below a child that has later siblings
+	Blank  string // Indentation below the last child
+}
+
+// UnicodeConnectors draws branches with box-drawing characters (default)
+var UnicodeConnectors = Connectors{
+	Branch: "├── ",
+	Last:   "└── ",
+	Pipe:   "│   ",
+	Blank:  "    ",
+}
+
+// ASCIIConnectors draws branches with plain ASCII for non-UTF terminals
+var ASCIIConnectors = Connectors{
+	Branch: "|-- ",
+	Last:   "`-- ",
+	Pipe:   "|   ",
+	Blank:  "    ",
+}
+
 // Tree represents a dependency tree
 type Tree struct {
-	root    string
-	nodes   map[string]*Node
-	full    bool
-	printed map[string]bool
+	root       string
+	nodes      map[string]*Node
+	full       bool
+	printed    map[string]bool
+	connectors Connectors
+	keep       map[string]bool // nil unless a filter is set
+	critical   map[string]bool // nil unless the critical path is marked
+	totals     map[string]int  // nil unless totals are shown
+}
+
+// TreeOptions configures BuildFrom
+type TreeOptions struct {
+	Full    bool // Show all occurrences (disable deduplication)
+	Reverse bool // Children are dependants instead of dependencies
 }
 
 // Build constructs a dependency tree from the given tickets
 func Build(tickets map[string]*ticket.Ticket, rootID string, full bool) *Tree {
-	// Convert tickets to nodes
-	nodes := make(map[string]*Node)
-	for id, t := range tickets {
+	return BuildFrom(NewGraphFromMap(tickets), rootID, TreeOptions{Full: full})
+}
+
+// BuildReverse constructs the tree of tickets that depend on the root,
+// directly or transitively: the children of each node are the tickets that
+// list it in their deps. Rendering, cycle protection and deduplication work
+// as for Build.
+func BuildReverse(tickets map[string]*ticket.Ticket, rootID string, full bool) *Tree {
+	return BuildFrom(NewGraphFromMap(tickets), rootID, TreeOptions{Full: full, Reverse: true})
+}
+
+// BuildFrom constructs a tree from a dependency graph, so callers that
+// already built one for other queries don't index the tickets twice. With
+// opts.Reverse the tree follows the graph's dependants, sorted by ID.
+func BuildFrom(g *DependencyGraph, rootID string, opts TreeOptions) *Tree {
+	nodes := make(map[string]*Node, len(g.byID))
+	for id, t := range g.byID {
+		children := t.Deps
+		if opts.Reverse {
+			children = make([]string, 0, len(g.dependants[id]))
+			for _, d := range g.dependants[id] {
+				children = append(children, d.ID)
+			}
+			sort.Strings(children)
+		}
 		nodes[id] = &Node{
 			ID:       id,
 			Status:   t.Status,
 			Title:    t.Title,
-			Deps:     t.Deps,
+			Deps:     children,
+			Estimate: t.Estimate,
 			MaxDepth: -1, // Will be computed
 		}
 	}
 
+	return newTree(nodes, rootID, opts.Full)
+}
+
+// newTree computes the depths of the nodes reachable from the root
+func newTree(nodes map[string]*Node, rootID string, full bool) *Tree {
 	tree := &Tree{
-		root:    rootID,
-		nodes:   nodes,
-		full:    full,
-		printed: make(map[string]bool),
+		root:       rootID,
+		nodes:      nodes,
+		full:       full,
+		printed:    make(map[string]bool),
+		connectors: UnicodeConnectors,
 	}
 
 	tree.computeMaxDepths()
@@ -152,6 +216,145 @@ func (t *Tree) computeSubtreeDepths() {
 	}
 }
 
+// SetConnectors selects the connector set used by Render
+func (t *Tree) SetConnectors(c Connectors) {
+	t.connectors = c
+}
+
+// SetFilter prunes the rendered tree to branches containing at least one node
+// for which match returns true. Matching nodes and all of their ancestors are
+// kept; the root is always shown.
+func (t *Tree) SetFilter(match func(id string) bool) {
+	// Index dependants so matches can be propagated up to their ancestors
+	dependants := make(map[string][]string)
+	for id, node := range t.nodes {
+		for _, dep := range node.Deps {
+			dependants[dep] = append(dependants[dep], id)
+		}
+	}
+
+	t.keep = make(map[string]bool)
+	var queue []string
+	for id := range t.nodes {
+		if match(id) {
+			t.keep[id] = true
+			queue = append(queue, id)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, parent := range dependants[id] {
+			if !t.keep[parent] {
+				t.keep[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+}
+
+// CriticalPath returns the longest dependency chain from the root to a leaf,
+// starting with the root. It follows the subtree depths: at each step the
+// next node is a child at the next depth whose subtree reaches the deepest
+// leaf, preferring the lowest ID on ties.
+func (t *Tree) CriticalPath() []string {
+	root, ok := t.nodes[t.root]
+	if !ok {
+		return nil
+	}
+
+	deepest := root.SubtreeDepth
+	path := []string{root.ID}
+	onPath := map[string]bool{root.ID: true}
+	node := root
+	for depth := 0; depth < deepest; depth++ {
+		next := ""
+		for _, dep := range node.Deps {
+			depNode, ok := t.nodes[dep]
+			if !ok || onPath[dep] {
+				continue
+			}
+			if depNode.MaxDepth != depth+1 || depNode.SubtreeDepth != deepest {
+				continue
+			}
+			if next == "" || dep < next {
+				next = dep
+			}
+		}
+		if next == "" {
+			break
+		}
+		path = append(path, next)
+		onPath[next] = true
+		node = t.nodes[next]
+	}
+	return path
+}
+
+// MarkCriticalPath makes Render prefix the nodes of the critical path with "* "
+func (t *Tree) MarkCriticalPath() {
+	t.critical = make(map[string]bool)
+	for _, id := range t.CriticalPath() {
+		t.critical[id] = true
+	}
+}
+
+// ShowTotals makes Render append the summed estimate of each node's subtree,
+// e.g. "(Σ13)". Every ticket in the subtree counts once, however many
+// paths lead to it, so a dependency shared by two branches isn't counted
+// twice.
+func (t *Tree) ShowTotals() {
+	t.totals = make(map[string]int)
+}
+
+// Total returns the summed estimate of the distinct tickets in a node's
+// subtree, including the node itself
+func (t *Tree) Total(id string) int {
+	if total, ok := t.totals[id]; ok {
+		return total
+	}
+
+	total := 0
+	seen := map[string]bool{id: true}
+	stack := []string{id}
+	for len(stack) > 0 {
+		node, ok := t.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !ok {
+			continue
+		}
+		total += node.Estimate
+		for _, dep := range node.Deps {
+			if !seen[dep] {
+				seen[dep] = true
+				stack = append(stack, dep)
+			}
+		}
+	}
+
+	if t.totals != nil {
+		t.totals[id] = total
+	}
+	return total
+}
+
+// label formats a node for Render, marking critical path nodes and adding
+// subtree totals
+func (t *Tree) label(node *Node) string {
+	mark := ""
+	if t.critical[node.ID] {
+		mark = "* "
+	}
+	total := ""
+	if t.totals != nil {
+		if sum := t.Total(node.ID); sum > 0 {
+			total = fmt.Sprintf(" (Σ%d)", sum)
+		}
+	}
+	return fmt.Sprintf("%s%s [%s] %s%s", mark, node.ID, node.Status, node.Title, total)
+}
+
 // Render prints the dependency tree
 func (t *Tree) Render() {
 	root, ok := t.nodes[t.root]
@@ -160,7 +363,7 @@ func (t *Tree) Render() {
 	}
 
 	// Print root
-	fmt.Printf("%s [%s] %s\n", root.ID, root.Status, root.Title)
+	fmt.Println(t.label(root))
 	t.printed[root.ID] = true
 
 	// Render children
@@ -183,6 +386,10 @@ func (t *Tree) renderChildren(id, prefix, path string, depth int) {
 		if !ok {
 			continue
 		}
+		// Skip branches without a filter match
+		if t.keep != nil && !t.keep[dep] {
+			continue
+		}
 		// Skip if in path (cycle)
 		pathKey := ":" + dep + ":"
 		if strings.Contains(path, pathKey) {
@@ -221,13 +428,13 @@ func (t *Tree) renderChildren(id, prefix, path string, depth int) {
 		// Determine connector
 		var connector string
 		if i == len(children)-1 {
-			connector = "└── "
+			connector = t.connectors.Last
 		} else {
-			connector = "├── "
+			connector = t.connectors.Branch
 		}
 
 		// Print child
-		fmt.Printf("%s%s%s [%s] %s\n", prefix, connector, childNode.ID, childNode.Status, childNode.Title)
+		fmt.Printf("%s%s%s\n", prefix, connector, t.label(childNode))
 
 		if !t.full {
 			t.printed[child] = true
@@ -236,9 +443,9 @@ func (t *Tree) renderChildren(id, prefix, path string, depth int) {
 		// Compute new prefix
 		var newPrefix string
 		if i == len(children)-1 {
-			newPrefix = prefix + "    "
+			newPrefix = prefix + t.connectors.Blank
 		} else {
-			newPrefix = prefix + "│   "
+			newPrefix = prefix + t.connectors.Pipe
 		}
 
 		// Recurse
@@ -393,6 +393,128 @@ func TestTreeConnectors(t *testing.T) {
 	}
 }
 
+// TestASCIIConnectors tests the ASCII connector set
+func TestASCIIConnectors(t *testing.T) {
+	tickets := map[string]*ticket.Ticket{
+		"a-1111": createTestTicket("a-1111", "Ticket A", ticket.StatusOpen, []string{}),
+		"b-2222": createTestTicket("b-2222", "Ticket B", ticket.StatusOpen, []string{"a-1111"}),
+		"c-3333": createTestTicket("c-3333", "Ticket C", ticket.StatusOpen, []string{"e-5555"}),
+		"d-4444": createTestTicket("d-4444", "Ticket D", ticket.StatusOpen, []string{"b-2222", "c-3333"}),
+		"e-5555": createTestTicket("e-5555", "Ticket E", ticket.StatusOpen, []string{"f-6666"}),
+		"f-6666": createTestTicket("f-6666", "Ticket F", ticket.StatusOpen, []string{}),
+	}
+
+	unicode := captureOutput(func() {
+		Build(tickets, "d-4444", false).Render()
+	})
+
+	tree := Build(tickets, "d-4444", false)
+	tree.SetConnectors(ASCIIConnectors)
+	ascii := captureOutput(func() {
+		tree.Render()
+	})
+
+	// Output must be pure ASCII
+	for i, r := range ascii {
+		if r > 127 {
+			t.Fatalf("non-ASCII rune %q at offset %d in output:\n%s", r, i, ascii)
+		}
+	}
+
+	// Structure and indentation must match the Unicode rendering
+	replacer := strings.NewReplacer(
+		UnicodeConnectors.Branch, ASCIIConnectors.Branch,
+		UnicodeConnectors.Last, ASCIIConnectors.Last,
+		UnicodeConnectors.Pipe, ASCIIConnectors.Pipe,
+	)
+	if want := replacer.Replace(unicode); ascii != want {
+		t.Errorf("ASCII output mismatch\ngot:\n%s\nwant:\n%s", ascii, want)
+	}
+
+	if !strings.Contains(ascii, "`-- ") || !strings.Contains(ascii, "|-- ") || !strings.Contains(ascii, "|   ") {
+		t.Errorf("expected ASCII connectors in output:\n%s", ascii)
+	}
+}
+
+// TestSetFilter tests pruning branches without a matching node
+func TestSetFilter(t *testing.T) {
+	// root -> (a -> bug), (b -> c), bug2
+	tickets := map[string]*ticket.Ticket{
+		"root": createTestTicket("root", "Root", ticket.StatusOpen, []string{"a", "b", "bug2"}),
+		"a":    createTestTicket("a", "A", ticket.StatusOpen, []string{"bug"}),
+		"bug":  createTestTicket("bug", "Bug", ticket.StatusOpen, []string{}),
+		"b":    createTestTicket("b", "B", ticket.StatusOpen, []string{"c"}),
+		"c":    createTestTicket("c", "C", ticket.StatusOpen, []string{}),
+		"bug2": createTestTicket("bug2", "Bug 2", ticket.StatusOpen, []string{}),
+	}
+
+	tree := Build(tickets, "root", false)
+	tree.SetFilter(func(id string) bool { return strings.HasPrefix(id, "bug") })
+	output := captureOutput(func() {
+		tree.Render()
+	})
+
+	for _, want := range []string{"root [open] Root", "a [open] A", "bug [open] Bug", "bug2 [open] Bug 2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"B\n", "C\n"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("expected branch %q to be pruned:\n%s", unwanted, output)
+		}
+	}
+
+	t.Run("no matches shows only root", func(t *testing.T) {
+		tree := Build(tickets, "root", false)
+		tree.SetFilter(func(string) bool { return false })
+		output := captureOutput(func() {
+			tree.Render()
+		})
+		if output != "root [open] Root\n" {
+			t.Errorf("expected only the root, got:\n%s", output)
+		}
+	})
+}
+
+func TestCriticalPath(t *testing.T) {
+	// root -> short, (mid -> deep -> leaf), (side -> deep)
+	tickets := map[string]*ticket.Ticket{
+		"root":  createTestTicket("root", "Root", ticket.StatusOpen, []string{"short", "mid", "side"}),
+		"short": createTestTicket("short", "Short", ticket.StatusOpen, []string{}),
+		"mid":   createTestTicket("mid", "Mid", ticket.StatusOpen, []string{"deep"}),
+		"side":  createTestTicket("side", "Side", ticket.StatusOpen, []string{"deep"}),
+		"deep":  createTestTicket("deep", "Deep", ticket.StatusOpen, []string{"leaf"}),
+		"leaf":  createTestTicket("leaf", "Leaf", ticket.StatusOpen, []string{}),
+	}
+
+	tree := Build(tickets, "root", false)
+	want := []string{"root", "mid", "deep", "leaf"}
+	if got := tree.CriticalPath(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("CriticalPath() = %v, want %v", got, want)
+	}
+
+	tree.MarkCriticalPath()
+	output := captureOutput(func() {
+		tree.Render()
+	})
+
+	marked := 0
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if strings.Contains(line, "* ") {
+			marked++
+		}
+	}
+	if marked != len(want) {
+		t.Errorf("expected %d marked nodes, got %d:\n%s", len(want), marked, output)
+	}
+	for _, id := range want {
+		if !strings.Contains(output, "* "+id+" [open]") {
+			t.Errorf("expected %s to be marked:\n%s", id, output)
+		}
+	}
+}
+
 // TestMultiLevelTree tests deeper tree structure
 func TestMultiLevelTree(t *testing.T) {
 	tickets := map[string]*ticket.Ticket{
@@ -478,3 +600,141 @@ func TestStatusDisplay(t *testing.T) {
 		t.Error("should display [closed] status")
 	}
 }
+
+// TestBuildReverse tests that the reverse tree lists dependants as children
+func TestBuildReverse(t *testing.T) {
+	// Diamond: B and C depend on A, D depends on B and C
+	tickets := map[string]*ticket.Ticket{
+		"a-1111": createTestTicket("a-1111", "Ticket A", ticket.StatusOpen, []string{}),
+		"b-2222": createTestTicket("b-2222", "Ticket B", ticket.StatusOpen, []string{"a-1111"}),
+		"c-3333": createTestTicket("c-3333", "Ticket C", ticket.StatusClosed, []string{"a-1111"}),
+		"d-4444": createTestTicket("d-4444", "Ticket D", ticket.StatusOpen, []string{"b-2222", "c-3333"}),
+	}
+
+	t.Run("dedup", func(t *testing.T) {
+		output := captureOutput(func() {
+			BuildReverse(tickets, "a-1111", false).Render()
+		})
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), output)
+		}
+		if !strings.HasPrefix(lines[0], "a-1111 [open] Ticket A") {
+			t.Errorf("first line should be root a-1111, got: %s", lines[0])
+		}
+		if strings.Count(output, "d-4444") != 1 {
+			t.Errorf("d-4444 should appear once without --full:\n%s", output)
+		}
+		if !strings.Contains(output, "c-3333 [closed] Ticket C") {
+			t.Errorf("missing c-3333 with status:\n%s", output)
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		output := captureOutput(func() {
+			BuildReverse(tickets, "a-1111", true).Render()
+		})
+		if strings.Count(output, "d-4444") != 2 {
+			t.Errorf("d-4444 should appear twice with --full:\n%s", output)
+		}
+	})
+
+	t.Run("leaf has no dependants", func(t *testing.T) {
+		output := captureOutput(func() {
+			BuildReverse(tickets, "d-4444", false).Render()
+		})
+		if strings.TrimSpace(output) != "d-4444 [open] Ticket D" {
+			t.Errorf("expected only the root, got:\n%s", output)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		cyclic := map[string]*ticket.Ticket{
+			"a-1111": createTestTicket("a-1111", "Ticket A", ticket.StatusOpen, []string{"b-2222"}),
+			"b-2222": createTestTicket("b-2222", "Ticket B", ticket.StatusOpen, []string{"a-1111"}),
+		}
+		output := captureOutput(func() {
+			BuildReverse(cyclic, "a-1111", false).Render()
+		})
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) > 3 {
+			t.Errorf("output too long for cycle, got %d lines", len(lines))
+		}
+	})
+}
+
+// TestBuildFromMatchesBuild tests that a tree built from a shared graph
+// renders the same as one built from the ticket map
+func TestBuildFromMatchesBuild(t *testing.T) {
+	tickets := map[string]*ticket.Ticket{
+		"a-1111": createTestTicket("a-1111", "Ticket A", ticket.StatusClosed, []string{}),
+		"b-2222": createTestTicket("b-2222", "Ticket B", ticket.StatusOpen, []string{"a-1111"}),
+		"c-3333": createTestTicket("c-3333", "Ticket C", ticket.StatusInProgress, []string{"a-1111", "b-2222"}),
+		"d-4444": createTestTicket("d-4444", "Ticket D", ticket.StatusOpen, []string{"b-2222", "c-3333"}),
+	}
+	var list []*ticket.Ticket
+	for _, id := range []string{"d-4444", "c-3333", "b-2222", "a-1111"} {
+		list = append(list, tickets[id])
+	}
+	graph := NewGraph(list)
+
+	tests := []struct {
+		name string
+		root string
+		opts TreeOptions
+		want *Tree
+	}{
+		{"forward", "d-4444", TreeOptions{}, Build(tickets, "d-4444", false)},
+		{"forward full", "d-4444", TreeOptions{Full: true}, Build(tickets, "d-4444", true)},
+		{"reverse", "a-1111", TreeOptions{Reverse: true}, BuildReverse(tickets, "a-1111", false)},
+		{"reverse full", "a-1111", TreeOptions{Full: true, Reverse: true}, BuildReverse(tickets, "a-1111", true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := captureOutput(tt.want.Render)
+			got := captureOutput(BuildFrom(graph, tt.root, tt.opts).Render)
+			if got != want {
+				t.Errorf("BuildFrom output differs from Build:\ngot:\n%s\nwant:\n%s", got, want)
+			}
+			if strings.Count(got, "\n") < 4 {
+				t.Errorf("expected a multi-level tree, got:\n%s", got)
+			}
+		})
+	}
+}
+
+// TestShowTotals tests summing estimates over subtrees without counting
+// shared dependencies twice
+func TestShowTotals(t *testing.T) {
+	// Diamond: D -> B, C; B -> A; C -> A
+	tickets := map[string]*ticket.Ticket{
+		"a-1111": createTestTicket("a-1111", "Ticket A", ticket.StatusOpen, []string{}),
+		"b-2222": createTestTicket("b-2222", "Ticket B", ticket.StatusOpen, []string{"a-1111"}),
+		"c-3333": createTestTicket("c-3333", "Ticket C", ticket.StatusOpen, []string{"a-1111"}),
+		"d-4444": createTestTicket("d-4444", "Ticket D", ticket.StatusOpen, []string{"b-2222", "c-3333"}),
+	}
+	for id, estimate := range map[string]int{"a-1111": 5, "b-2222": 3, "c-3333": 2, "d-4444": 3} {
+		tickets[id].Estimate = estimate
+	}
+
+	tree := Build(tickets, "d-4444", false)
+	tree.ShowTotals()
+	output := captureOutput(tree.Render)
+
+	for _, want := range []string{
+		"d-4444 [open] Ticket D (Σ13)", // A counted once
+		"b-2222 [open] Ticket B (Σ8)",
+		"c-3333 [open] Ticket C (Σ7)",
+		"a-1111 [open] Ticket A (Σ5)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	// Without ShowTotals nothing is appended
+	plain := captureOutput(Build(tickets, "d-4444", false).Render)
+	if strings.Contains(plain, "Σ") {
+		t.Errorf("totals shown without ShowTotals:\n%s", plain)
+	}
+}
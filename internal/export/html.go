@@ -0,0 +1,142 @@
+// Package export renders the ticket store in formats meant for readers
+// outside tk.
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// ref is a reference from one ticket to another. Missing is set when the
+// target is not in the export, so no anchor can be made.
+type ref struct {
+	ID      string
+	Rel     string
+	Missing bool
+}
+
+// htmlTicket is a ticket prepared for the HTML template
+type htmlTicket struct {
+	*ticket.Ticket
+	Body   template.HTML
+	Deps   []ref
+	Links  []ref
+	Parent *ref
+}
+
+// htmlGroup is the tickets sharing a status
+type htmlGroup struct {
+	Status  ticket.Status
+	Tickets []htmlTicket
+}
+
+// HTML writes a single self-contained HTML page listing the tickets grouped
+// by status. Bodies are rendered from markdown inside collapsible sections,
+// and dependencies, links and parents are anchors within the page.
+func HTML(w io.Writer, tickets []*ticket.Ticket) error {
+	exists := make(map[string]bool, len(tickets))
+	for _, t := range tickets {
+		exists[t.ID] = true
+	}
+	makeRef := func(id, rel string) ref {
+		return ref{ID: id, Rel: rel, Missing: !exists[id]}
+	}
+
+	byStatus := make(map[ticket.Status][]htmlTicket)
+	for _, t := range tickets {
+		ht := htmlTicket{Ticket: t, Body: template.HTML(renderMarkdown(t.Body))}
+		for _, dep := range t.Deps {
+			ht.Deps = append(ht.Deps, makeRef(dep, ""))
+		}
+		for _, entry := range t.Links {
+			link := ticket.ParseLink(entry)
+			ht.Links = append(ht.Links, makeRef(link.ID, link.Rel))
+		}
+		if t.Parent != "" {
+			parent := makeRef(t.Parent, "")
+			ht.Parent = &parent
+		}
+		byStatus[t.Status] = append(byStatus[t.Status], ht)
+	}
+
+	var groups []htmlGroup
+	for _, status := range groupOrder(byStatus) {
+		group := byStatus[status]
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Priority != group[j].Priority {
+				return group[i].Priority < group[j].Priority
+			}
+			return group[i].ID < group[j].ID
+		})
+		groups = append(groups, htmlGroup{Status: status, Tickets: group})
+	}
+
+	if err := pageTemplate.Execute(w, groups); err != nil {
+		return fmt.Errorf("rendering HTML: %w", err)
+	}
+	return nil
+}
+
+// groupOrder lists the statuses present, known statuses first in their usual
+// order, then any others alphabetically
+func groupOrder(byStatus map[ticket.Status][]htmlTicket) []ticket.Status {
+	var order []ticket.Status
+	known := make(map[ticket.Status]bool)
+	for _, s := range ticket.ValidStatuses {
+		known[s] = true
+		if len(byStatus[s]) > 0 {
+			order = append(order, s)
+		}
+	}
+	var other []ticket.Status
+	for s := range byStatus {
+		if !known[s] {
+			other = append(other, s)
+		}
+	}
+	sort.Slice(other, func(i, j int) bool { return other[i] < other[j] })
+	return append(order, other...)
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8"/>
+<title>Tickets</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 60em; margin: 2em auto; padding: 0 1em; color: #222; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 0.2em; }
+details { border: 1px solid #ddd; border-radius: 4px; margin: 0.5em 0; padding: 0.4em 0.8em; }
+details:target { border-color: #0366d6; }
+summary { cursor: pointer; }
+.id { font-family: monospace; color: #555; }
+.meta { color: #666; font-size: 0.9em; margin: 0.4em 0; }
+.missing { color: #b00; text-decoration: line-through; }
+pre { background: #f6f8fa; padding: 0.6em; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>Tickets</h1>
+{{- range .}}
+<section id="status-{{.Status}}">
+<h2>{{.Status}} ({{len .Tickets}})</h2>
+{{- range .Tickets}}
+<details id="ticket-{{.ID}}">
+<summary><span class="id">{{.ID}}</span> [P{{.Priority}}] {{.Type}}: {{.Title}}</summary>
+<div class="meta">
+{{- if .Assignee}}<div>Assignee: {{.Assignee}}</div>{{end}}
+{{- if .Parent}}<div>Parent: {{template "ref" .Parent}}</div>{{end}}
+{{- if .Deps}}<div>Depends on:{{range .Deps}} {{template "ref" .}}{{end}}</div>{{end}}
+{{- if .Links}}<div>Linked:{{range .Links}} {{template "ref" .}}{{end}}</div>{{end}}
+</div>
+{{.Body}}</details>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+{{define "ref"}}{{if .Missing}}<span class="missing">{{.ID}}</span>{{else}}<a href="#ticket-{{.ID}}">{{.ID}}</a>{{end}}{{if .Rel}} ({{.Rel}}){{end}}{{end}}`))
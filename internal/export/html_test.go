@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// wellFormed parses the page after the doctype as XML, which the exporter
+// keeps compatible with (self-closed void elements, escaped text)
+func wellFormed(t *testing.T, page string) {
+	t.Helper()
+	doc := strings.TrimPrefix(page, "<!DOCTYPE html>\n")
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("page is not well-formed: %v\n%s", err, page)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "a-1", Status: ticket.StatusOpen, Type: ticket.TypeTask, Title: "Parse <input> & output",
+			Deps: []string{"b-2"}, Links: []string{"c-3:duplicates", "gone-9"},
+			Body: "Some **bold** text.\n\n## Steps\n\n- one\n- `two`\n"},
+		{ID: "b-2", Status: ticket.StatusClosed, Type: ticket.TypeBug, Title: "Base", Parent: "c-3"},
+		{ID: "c-3", Status: ticket.StatusInProgress, Type: ticket.TypeEpic, Title: "Epic"},
+	}
+
+	var buf bytes.Buffer
+	if err := HTML(&buf, tickets); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	page := buf.String()
+
+	if !strings.HasPrefix(page, "<!DOCTYPE html>") {
+		t.Errorf("page should start with a doctype")
+	}
+	wellFormed(t, page)
+
+	for _, want := range []string{
+		`<details id="ticket-a-1">`, `Parse &lt;input&gt; &amp; output`,
+		`<details id="ticket-b-2">`, `Base`,
+		`<details id="ticket-c-3">`, `Epic`,
+		`<a href="#ticket-b-2">b-2</a>`,
+		`<a href="#ticket-c-3">c-3</a> (duplicates)`,
+		`<span class="missing">gone-9</span>`,
+		`<strong>bold</strong>`, `<h4>Steps</h4>`, `<li><code>two</code></li>`,
+		`<style>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected %q in page:\n%s", want, page)
+		}
+	}
+
+	// Groups follow the usual status order
+	open := strings.Index(page, `id="status-open"`)
+	inProgress := strings.Index(page, `id="status-in_progress"`)
+	closed := strings.Index(page, `id="status-closed"`)
+	if !(open < inProgress && inProgress < closed) {
+		t.Errorf("expected open, in_progress, closed groups in order:\n%s", page)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"paragraph", "one\ntwo", "<p>one two</p>\n"},
+		{"heading", "# Title", "<h3>Title</h3>\n"},
+		{"numbered list", "1. a\n2. b", "<ol>\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{"code block", "```\n<x>\n```", "<pre><code>&lt;x&gt;\n</code></pre>\n"},
+		{"link", "[docs](https://example.com)", `<p><a href="https://example.com">docs</a></p>` + "\n"},
+		{"unsafe link", "[x](javascript:void)", "<p>x</p>\n"},
+		{"italic", "an *em* word", "<p>an <em>em</em> word</p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMarkdown(tt.src); got != tt.want {
+				t.Errorf("renderMarkdown(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// headingOffset demotes body headings below the page's own h1 and h2
+const headingOffset = 2
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletRe   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	numberedRe = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	boldRe     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	italicRe   = regexp.MustCompile(`\*([^*]+)\*`)
+	linkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// renderMarkdown converts the subset of markdown used in ticket bodies to
+// HTML: headings, paragraphs, bullet and numbered lists, fenced code blocks,
+// and inline code, bold, italic and links. Everything else is escaped text.
+func renderMarkdown(src string) string {
+	var out strings.Builder
+	var para []string
+	list := "" // "ul" or "ol" while inside a list
+	inCode := false
+
+	flushPara := func() {
+		if len(para) > 0 {
+			fmt.Fprintf(&out, "<p>%s</p>\n", renderInline(strings.Join(para, " ")))
+			para = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			fmt.Fprintf(&out, "</%s>\n", list)
+			list = ""
+		}
+	}
+	openList := func(tag string) {
+		if list != tag {
+			closeList()
+			fmt.Fprintf(&out, "<%s>\n", tag)
+			list = tag
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			if inCode {
+				out.WriteString("</code></pre>\n")
+			} else {
+				flushPara()
+				closeList()
+				out.WriteString("<pre><code>")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flushPara()
+			closeList()
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flushPara()
+			closeList()
+			level := len(m[1]) + headingOffset
+			if level > 6 {
+				level = 6
+			}
+			fmt.Fprintf(&out, "<h%d>%s</h%d>\n", level, renderInline(m[2]), level)
+			continue
+		}
+		if m := bulletRe.FindStringSubmatch(line); m != nil {
+			flushPara()
+			openList("ul")
+			fmt.Fprintf(&out, "<li>%s</li>\n", renderInline(m[1]))
+			continue
+		}
+		if m := numberedRe.FindStringSubmatch(line); m != nil {
+			flushPara()
+			openList("ol")
+			fmt.Fprintf(&out, "<li>%s</li>\n", renderInline(m[1]))
+			continue
+		}
+
+		closeList()
+		para = append(para, strings.TrimSpace(line))
+	}
+
+	if inCode {
+		out.WriteString("</code></pre>\n")
+	}
+	flushPara()
+	closeList()
+	return out.String()
+}
+
+// renderInline escapes text and applies code spans, bold, italic and links.
+// Code spans are left verbatim; links are only kept for web, mail and
+// in-page URLs.
+func renderInline(text string) string {
+	parts := strings.Split(text, "`")
+	var out strings.Builder
+	for i, part := range parts {
+		escaped := html.EscapeString(part)
+		// Odd segments sit between backticks; an unpaired final one does not
+		if i%2 == 1 && i < len(parts)-1 {
+			out.WriteString("<code>" + escaped + "</code>")
+			continue
+		}
+		if i%2 == 1 {
+			out.WriteString("`")
+		}
+		escaped = linkRe.ReplaceAllStringFunc(escaped, func(m string) string {
+			sub := linkRe.FindStringSubmatch(m)
+			if !safeURL(sub[2]) {
+				return sub[1]
+			}
+			return fmt.Sprintf(`<a href="%s">%s</a>`, sub[2], sub[1])
+		})
+		escaped = boldRe.ReplaceAllString(escaped, "<strong>$1</strong>")
+		escaped = italicRe.ReplaceAllString(escaped, "<em>$1</em>")
+		out.WriteString(escaped)
+	}
+	return out.String()
+}
+
+// safeURL reports whether a link target may be emitted as an href
+func safeURL(u string) bool {
+	for _, prefix := range []string{"http://", "https://", "mailto:", "#"} {
+		if strings.HasPrefix(strings.ToLower(u), prefix) {
+			return true
+		}
+	}
+	return false
+}
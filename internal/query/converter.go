@@ -3,24 +3,41 @@ package query
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/itchyny/gojq"
 	"github.com/lo5/tk/internal/ticket"
 )
 
-// TicketJSON represents a ticket in JSON format
+// TicketJSON represents a ticket in JSON format. Priority is a string, so
+// equality filters such as .priority == "0" read naturally, and PriorityNum
+// carries it as a number for range filters: jq orders every number before
+// every string, so .priority <= 2 would match nothing, while
+// .priority_num <= 2 compares numerically.
 type TicketJSON struct {
-	ID          string   `json:"id"`
-	Status      string   `json:"status"`
-	Deps        []string `json:"deps"`
-	Links       []string `json:"links"`
-	Created     string   `json:"created"`
-	Type        string   `json:"type"`
-	Priority    string   `json:"priority"`
-	Assignee    string   `json:"assignee,omitempty"`
-	ExternalRef string   `json:"external-ref,omitempty"`
-	Parent      string   `json:"parent,omitempty"`
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Deps        []string   `json:"deps"`
+	Links       []string   `json:"links"`
+	Tags        []string   `json:"tags"`
+	Created     string     `json:"created"`
+	Type        string     `json:"type"`
+	Priority    string     `json:"priority"`
+	PriorityNum int        `json:"priority_num"`       // Priority as a number, for range filters
+	Assignee    string     `json:"assignee,omitempty"` // Absent (null) when unassigned
+	ExternalRef string     `json:"external-ref,omitempty"`
+	Parent      string     `json:"parent,omitempty"`
+	CreatedBy   string     `json:"created-by,omitempty"`
+	Estimate    int        `json:"estimate,omitempty"`
+	Due         string     `json:"due,omitempty"`
+	Notes       []NoteJSON `json:"notes,omitempty"` // Entries of the ## Notes section
+}
+
+// NoteJSON is a ticket note in JSON format
+type NoteJSON struct {
+	Time string `json:"time"`
+	Text string `json:"text"`
 }
 
 // ToJSON converts a ticket to a JSON string
@@ -30,12 +47,22 @@ func ToJSON(t *ticket.Ticket) (string, error) {
 		Status:      string(t.Status),
 		Deps:        t.Deps,
 		Links:       t.Links,
+		Tags:        t.Tags,
 		Created:     t.Created.UTC().Format("2006-01-02T15:04:05Z"),
 		Type:        string(t.Type),
 		Priority:    fmt.Sprintf("%d", t.Priority),
+		PriorityNum: t.Priority,
 		Assignee:    t.Assignee,
 		ExternalRef: t.ExternalRef,
 		Parent:      t.Parent,
+		CreatedBy:   t.CreatedBy,
+		Estimate:    t.Estimate,
+	}
+	if !t.Due.IsZero() {
+		tj.Due = t.Due.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	for _, n := range t.Notes() {
+		tj.Notes = append(tj.Notes, NoteJSON{Time: n.Time.UTC().Format("2006-01-02T15:04:05Z"), Text: n.Text})
 	}
 
 	// Ensure arrays are not nil
@@ -45,31 +72,57 @@ func ToJSON(t *ticket.Ticket) (string, error) {
 	if tj.Links == nil {
 		tj.Links = []string{}
 	}
+	if tj.Tags == nil {
+		tj.Tags = []string{}
+	}
 
 	data, err := json.Marshal(tj)
 	if err != nil {
 		return "", fmt.Errorf("marshaling JSON: %w", err)
 	}
 
-	return string(data), nil
+	if len(t.Extra) == 0 {
+		return string(data), nil
+	}
+	return appendExtra(data, t.Extra)
 }
 
-// Filter applies a jq-style filter to JSON tickets
-func Filter(jsonLines []string, filterExpr string) ([]string, error) {
-	// Wrap in select() if not already
-	if !strings.HasPrefix(filterExpr, "select(") && !strings.HasPrefix(filterExpr, ".") {
-		filterExpr = "select(" + filterExpr + ")"
-	} else if strings.HasPrefix(filterExpr, ".") && !strings.Contains(filterExpr, "|") {
-		// If it's just a field access like ".priority == 0", wrap in select
-		filterExpr = "select(" + filterExpr + ")"
+// appendExtra adds custom frontmatter keys after the known fields, in sorted
+// order. Keys that collide with a known field are skipped.
+func appendExtra(data []byte, extra map[string]string) (string, error) {
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(data, &known); err != nil {
+		return "", fmt.Errorf("marshaling JSON: %w", err)
 	}
 
-	query, err := gojq.Parse(filterExpr)
+	var buf strings.Builder
+	buf.Write(data[:len(data)-1]) // Drop the closing brace
+	for _, k := range ticket.SortedExtraKeys(extra) {
+		if _, clash := known[k]; clash {
+			continue
+		}
+		key, _ := json.Marshal(k)
+		value, _ := json.Marshal(extra[k])
+		buf.WriteString(",")
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+
+	return buf.String(), nil
+}
+
+// Eval runs a jq program against each JSON ticket and returns every value it
+// produces, in order. Unlike Filter, the program is not wrapped in select().
+// Null results and evaluation errors are skipped.
+func Eval(jsonLines []string, program string) ([]interface{}, error) {
+	query, err := gojq.Parse(program)
 	if err != nil {
 		return nil, fmt.Errorf("parsing filter: %w", err)
 	}
 
-	var results []string
+	var results []interface{}
 	for _, line := range jsonLines {
 		var input interface{}
 		if err := json.Unmarshal([]byte(line), &input); err != nil {
@@ -82,18 +135,175 @@ func Filter(jsonLines []string, filterExpr string) ([]string, error) {
 			if !ok {
 				break
 			}
-			if err, ok := v.(error); ok {
-				// Filter returned error (e.g., select returned false)
-				_ = err
+			if _, isErr := v.(error); isErr || v == nil {
 				continue
 			}
-			// If we got a result, include this line
-			if v != nil {
+			results = append(results, v)
+		}
+	}
+
+	return results, nil
+}
+
+// FormatRaw renders a value like jq's --raw-output: strings are printed
+// without quotes, anything else as compact JSON
+func FormatRaw(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// MissingField returns the IDs of JSON tickets where a top-level field is
+// absent or null
+func MissingField(jsonLines []string, field string) []string {
+	var ids []string
+	for _, line := range jsonLines {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			continue
+		}
+		if v, ok := obj[field]; !ok || v == nil {
+			id, _ := obj["id"].(string)
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Distinct returns the sorted unique values of a top-level field across JSON
+// tickets, formatted like FormatRaw. Array fields contribute each element;
+// absent, null and empty values are skipped.
+func Distinct(jsonLines []string, field string) []string {
+	seen := make(map[string]bool)
+	add := func(v interface{}) {
+		if v == nil {
+			return
+		}
+		s, err := FormatRaw(v)
+		if err != nil || s == "" {
+			return
+		}
+		seen[s] = true
+	}
+
+	for _, line := range jsonLines {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			continue
+		}
+		if arr, ok := obj[field].([]interface{}); ok {
+			for _, v := range arr {
+				add(v)
+			}
+			continue
+		}
+		add(obj[field])
+	}
+
+	values := make([]string, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// CompiledFilter is a jq-style filter compiled once for reuse across many
+// tickets
+type CompiledFilter struct {
+	code *gojq.Code
+}
+
+// Compile parses and compiles a jq-style filter. Conditions and bare field
+// comparisons are wrapped in select(), as in Filter.
+func Compile(filterExpr string) (*CompiledFilter, error) {
+	// Wrap in select() if not already
+	if !strings.HasPrefix(filterExpr, "select(") && !strings.HasPrefix(filterExpr, ".") {
+		filterExpr = "select(" + filterExpr + ")"
+	} else if strings.HasPrefix(filterExpr, ".") && !strings.Contains(filterExpr, "|") {
+		// If it's just a field access like ".priority == 0", wrap in select
+		filterExpr = "select(" + filterExpr + ")"
+	}
+
+	query, err := gojq.Parse(filterExpr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing filter: %w", err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("compiling filter: %w", err)
+	}
+	return &CompiledFilter{code: code}, nil
+}
+
+// Eval reports whether a JSON ticket matches the filter, i.e. the program
+// produces a non-null value for it. Evaluation errors count as no match;
+// only a line that isn't valid JSON is an error.
+func (f *CompiledFilter) Eval(jsonLine string) (bool, error) {
+	var input interface{}
+	if err := json.Unmarshal([]byte(jsonLine), &input); err != nil {
+		return false, fmt.Errorf("parsing ticket JSON: %w", err)
+	}
+
+	iter := f.code.Run(input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return false, nil
+		}
+		if _, isErr := v.(error); isErr {
+			// Filter returned error (e.g., select returned false)
+			continue
+		}
+		if v != nil {
+			return true, nil
+		}
+	}
+}
+
+// Filter applies a jq-style filter to JSON tickets
+func Filter(jsonLines []string, filterExpr string) ([]string, error) {
+	filter, err := Compile(filterExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []string
+	for _, line := range jsonLines {
+		// Lines that aren't valid JSON are skipped
+		if ok, _ := filter.Eval(line); ok {
+			results = append(results, line)
+		}
+	}
+
+	return results, nil
+}
+
+// FilterAny returns the lines matching at least one of the filters, in
+// their original order
+func FilterAny(jsonLines []string, filterExprs []string) ([]string, error) {
+	filters := make([]*CompiledFilter, len(filterExprs))
+	for i, expr := range filterExprs {
+		filter, err := Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters[i] = filter
+	}
+
+	var results []string
+	for _, line := range jsonLines {
+		for _, filter := range filters {
+			if ok, _ := filter.Eval(line); ok {
 				results = append(results, line)
 				break
 			}
 		}
 	}
-
 	return results, nil
 }
@@ -2,6 +2,7 @@ package query
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -200,6 +201,9 @@ func TestToJSON(t *testing.T) {
 		if !strings.Contains(jsonStr, `"links":[]`) {
 			t.Error("links should be serialized as []")
 		}
+		if !strings.Contains(jsonStr, `"tags":[]`) {
+			t.Error("tags should be serialized as []")
+		}
 
 		// Parse and verify
 		var result map[string]interface{}
@@ -474,6 +478,37 @@ func TestFilter(t *testing.T) {
 		}
 	})
 
+	t.Run("assignee equality with unassigned tickets", func(t *testing.T) {
+		var jsonLines []string
+		for _, tk := range []*ticket.Ticket{
+			{ID: "a", Status: ticket.StatusOpen, Type: ticket.TypeTask, Assignee: "alice"},
+			{ID: "b", Status: ticket.StatusOpen, Type: ticket.TypeTask, Assignee: "bob"},
+			{ID: "c", Status: ticket.StatusOpen, Type: ticket.TypeTask},
+		} {
+			line, err := ToJSON(tk)
+			if err != nil {
+				t.Fatalf("ToJSON error: %v", err)
+			}
+			jsonLines = append(jsonLines, line)
+		}
+
+		// An unassigned ticket has no assignee field: it never equals a
+		// name, and matches null
+		for filter, want := range map[string]int{
+			`.assignee == "alice"`: 1,
+			`.assignee != "alice"`: 2,
+			`.assignee == null`:    1,
+		} {
+			results, err := Filter(jsonLines, filter)
+			if err != nil {
+				t.Fatalf("Filter(%s) error: %v", filter, err)
+			}
+			if len(results) != want {
+				t.Errorf("Filter(%s) = %d results, want %d: %v", filter, len(results), want, results)
+			}
+		}
+	})
+
 	t.Run("empty input returns empty", func(t *testing.T) {
 		jsonLines := []string{}
 
@@ -489,6 +524,105 @@ func TestFilter(t *testing.T) {
 }
 
 // TestRoundTrip tests converting ticket to JSON and back
+func TestMissingField(t *testing.T) {
+	lines := []string{
+		`{"id":"a-1","assignee":"alice"}`,
+		`{"id":"b-2"}`,
+		`{"id":"c-3","assignee":null}`,
+		`not json`,
+	}
+
+	got := MissingField(lines, "assignee")
+	if len(got) != 2 || got[0] != "b-2" || got[1] != "c-3" {
+		t.Errorf("MissingField() = %v, want [b-2 c-3]", got)
+	}
+
+	if got := MissingField(lines[:1], "assignee"); len(got) != 0 {
+		t.Errorf("MissingField() = %v, want none", got)
+	}
+}
+
+func TestToJSONExtraFields(t *testing.T) {
+	tk := &ticket.Ticket{
+		ID:     "extra-1234",
+		Status: ticket.StatusOpen,
+		Type:   ticket.TypeTask,
+		Extra:  map[string]string{"sprint": "s1", "status": "clobbered"},
+	}
+
+	jsonStr, err := ToJSON(tk)
+	if err != nil {
+		t.Fatalf("ToJSON error: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		t.Fatalf("failed to parse JSON %s: %v", jsonStr, err)
+	}
+	if result["sprint"] != "s1" {
+		t.Errorf("sprint = %v, want s1", result["sprint"])
+	}
+	if result["status"] != "open" {
+		t.Errorf("status = %v, known field must not be clobbered", result["status"])
+	}
+
+	results, err := Filter([]string{jsonStr}, `.sprint == "s1"`)
+	if err != nil || len(results) != 1 {
+		t.Errorf("Filter on .sprint = %v, %v; want one match", results, err)
+	}
+}
+
+func TestToJSONNotes(t *testing.T) {
+	tk := &ticket.Ticket{
+		ID:     "notes-1234",
+		Status: ticket.StatusOpen,
+		Type:   ticket.TypeTask,
+		Body:   "Description\n\n## Notes\n\n**2026-03-01T09:30:00Z**\n\nFirst\n\n**2026-03-02T10:00:00Z**\n\nSecond\n",
+	}
+
+	jsonStr, err := ToJSON(tk)
+	if err != nil {
+		t.Fatalf("ToJSON error: %v", err)
+	}
+	want := `"notes":[{"time":"2026-03-01T09:30:00Z","text":"First"},{"time":"2026-03-02T10:00:00Z","text":"Second"}]`
+	if !strings.Contains(jsonStr, want) {
+		t.Errorf("ToJSON() = %s, want it to contain %s", jsonStr, want)
+	}
+
+	results, err := Filter([]string{jsonStr}, `.notes | length == 2`)
+	if err != nil || len(results) != 1 {
+		t.Errorf("Filter on .notes = %v, %v; want one match", results, err)
+	}
+
+	// Tickets without notes have no notes key
+	tk.Body = "Description\n"
+	if jsonStr, _ = ToJSON(tk); strings.Contains(jsonStr, `"notes":`) {
+		t.Errorf("ToJSON() without notes = %s", jsonStr)
+	}
+}
+
+func TestEval(t *testing.T) {
+	lines := []string{`{"id":"a-1","assignee":"alice","priority":"1"}`, `{"id":"b-2"}`}
+
+	values, err := Eval(lines, ".assignee")
+	if err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if len(values) != 1 || values[0] != "alice" {
+		t.Errorf("Eval() = %v, want [alice] (nulls skipped)", values)
+	}
+
+	if _, err := Eval(lines, ".[[["); err == nil {
+		t.Error("Eval() expected parse error")
+	}
+
+	for v, want := range map[interface{}]string{"x": "x", 3.0: "3", true: "true"} {
+		if got, _ := FormatRaw(v); got != want {
+			t.Errorf("FormatRaw(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
 func TestRoundTrip(t *testing.T) {
 	t.Run("round trip preserves data", func(t *testing.T) {
 		now := time.Now().UTC().Truncate(time.Second)
@@ -618,3 +752,155 @@ func TestRoundTrip(t *testing.T) {
 		}
 	})
 }
+
+func TestDistinct(t *testing.T) {
+	lines := []string{
+		`{"id":"a","type":"bug","deps":["x","y"],"assignee":"bo"}`,
+		`{"id":"b","type":"task","deps":["y"]}`,
+		`{"id":"c","type":"bug","deps":[],"assignee":null}`,
+	}
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"type", "bug,task"},
+		{"deps", "x,y"},
+		{"assignee", "bo"},
+		{"missing", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(Distinct(lines, tt.field), ","); got != tt.want {
+			t.Errorf("Distinct(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+// TestFilterPriorityRange tests range filters on the numeric priority_num
+// field, and that filters are run as written
+func TestFilterPriorityRange(t *testing.T) {
+	var jsonLines []string
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		status := "open"
+		if id == "b" {
+			status = "closed"
+		}
+		jsonLines = append(jsonLines, fmt.Sprintf(`{"id":%q,"status":%q,"priority":"%d","priority_num":%d,"title":"check .priority < 3"}`, id, status, i, i))
+	}
+	ids := func(lines []string) string {
+		var out []string
+		for _, line := range lines {
+			var obj map[string]interface{}
+			json.Unmarshal([]byte(line), &obj)
+			out = append(out, obj["id"].(string))
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   string
+	}{
+		{"at most", `.priority_num <= 2`, "a,b,c"},
+		{"strictly greater", `.priority_num > 2`, "d,e"},
+		{"combined with and", `.status == "open" and .priority_num < 3`, "a,c"},
+		{"range", `.priority_num >= 1 and .priority_num <= 3`, "b,c,d"},
+		{"reversed operands", `2 >= .priority_num`, "a,b,c"},
+		{"reversed range", `1 <= .priority_num and 3 >= .priority_num`, "b,c,d"},
+		{"explicit select", `select(.priority_num < 2)`, "a,b"},
+		{"string equality unchanged", `.priority == "2"`, "c"},
+		{"comparison inside a string literal", `.title == "check .priority < 3"`, "a,b,c,d,e"},
+		{"string literal not a comparison", `.title == "check .priority_num < 3"`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := Filter(jsonLines, tt.filter)
+			if err != nil {
+				t.Fatalf("Filter error: %v", err)
+			}
+			if got := ids(results); got != tt.want {
+				t.Errorf("Filter(%s) = %s, want %s", tt.filter, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("priority_num is a number", func(t *testing.T) {
+		line, err := ToJSON(&ticket.Ticket{ID: "d", Status: ticket.StatusOpen, Priority: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var obj map[string]interface{}
+		json.Unmarshal([]byte(line), &obj)
+		if obj["priority_num"] != float64(3) || obj["priority"] != "3" {
+			t.Errorf("priority = %#v, priority_num = %#v", obj["priority"], obj["priority_num"])
+		}
+	})
+}
+
+func TestFilterAny(t *testing.T) {
+	lines := []string{
+		`{"id":"a","type":"bug","priority":0}`,
+		`{"id":"b","type":"bug","priority":3}`,
+		`{"id":"c","type":"feature","priority":0}`,
+		`{"id":"d","type":"feature","priority":3}`,
+	}
+
+	got, err := FilterAny(lines, []string{`.type == "bug"`, `.priority == 0`})
+	if err != nil {
+		t.Fatalf("FilterAny() error = %v", err)
+	}
+	want := []string{lines[0], lines[1], lines[2]}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("FilterAny() = %v, want %v", got, want)
+	}
+
+	if _, err := FilterAny(lines, []string{"select("}); err == nil {
+		t.Error("FilterAny() with invalid filter should error")
+	}
+}
+
+func TestCompiledFilterMatchesFilter(t *testing.T) {
+	var lines []string
+	for i := 0; i < 200; i++ {
+		lines = append(lines, fmt.Sprintf(`{"id":"t-%d","priority":"%d","status":"%s"}`, i, i%5, []string{"open", "closed"}[i%2]))
+	}
+
+	for _, expr := range []string{
+		`.priority == "0"`,
+		`.status == "open" and .priority != "4"`,
+		`select(.id | endswith("7"))`,
+	} {
+		want, err := Filter(lines, expr)
+		if err != nil {
+			t.Fatalf("Filter(%q) error = %v", expr, err)
+		}
+
+		filter, err := Compile(expr)
+		if err != nil {
+			t.Fatalf("Compile(%q) error = %v", expr, err)
+		}
+		var got []string
+		for _, line := range lines {
+			ok, err := filter.Eval(line)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if ok {
+				got = append(got, line)
+			}
+		}
+
+		if len(want) == 0 || strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("compiled %q matched %d lines, Filter matched %d", expr, len(got), len(want))
+		}
+	}
+
+	if _, err := Compile("select("); err == nil {
+		t.Error("Compile() of an invalid program should error")
+	}
+	filter, _ := Compile(".priority")
+	if _, err := filter.Eval("not json"); err == nil {
+		t.Error("Eval() of invalid JSON should error")
+	}
+}
@@ -0,0 +1,126 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NoValue labels the group of tickets where the field is empty
+const NoValue = "(none)"
+
+// Group is one distinct field value with the number of tickets having it
+type Group struct {
+	Value string
+	Count int
+}
+
+// GroupBy counts JSON tickets per value of a TicketJSON field, named as in
+// the JSON output. Array fields such as tags count each element. Fields
+// holding anything but strings and integers, such as notes, are rejected.
+// Groups are sorted by count, largest first, then by value.
+func GroupBy(jsonLines []string, field string) ([]Group, error) {
+	index, ok := ticketJSONField(field)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q (valid: %s)", field, strings.Join(ticketJSONFields(), ", "))
+	}
+	f := reflect.TypeOf(TicketJSON{}).Field(index)
+	if !groupable(f.Type) {
+		return nil, fmt.Errorf("cannot group by %q: only string, number and string array fields can be grouped", field)
+	}
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	omitEmpty := strings.Contains(opts, "omitempty")
+
+	counts := make(map[string]int)
+	for _, line := range jsonLines {
+		var tj TicketJSON
+		if err := json.Unmarshal([]byte(line), &tj); err != nil {
+			continue
+		}
+		values := fieldValues(reflect.ValueOf(tj).Field(index), omitEmpty)
+		if len(values) == 0 {
+			counts[NoValue]++
+		}
+		for _, v := range values {
+			counts[v]++
+		}
+	}
+
+	groups := make([]Group, 0, len(counts))
+	for v, n := range counts {
+		groups = append(groups, Group{Value: v, Count: n})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Value < groups[j].Value
+	})
+	return groups, nil
+}
+
+// groupable reports whether GroupBy can count values of a field type
+func groupable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.String, reflect.Int:
+		return true
+	case reflect.Slice:
+		return typ.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// fieldValues returns the values of a groupable field. Empty strings are
+// skipped, as is a zero integer the JSON output omits.
+func fieldValues(v reflect.Value, omitEmpty bool) []string {
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "" {
+			return nil
+		}
+		return []string{v.String()}
+	case reflect.Int:
+		if omitEmpty && v.Int() == 0 {
+			return nil
+		}
+		return []string{strconv.Itoa(int(v.Int()))}
+	}
+	var values []string
+	for i := 0; i < v.Len(); i++ {
+		if s := v.Index(i).String(); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// ticketJSONField returns the index of the TicketJSON field with the given
+// JSON name
+func ticketJSONField(name string) (int, bool) {
+	typ := reflect.TypeOf(TicketJSON{})
+	for i := 0; i < typ.NumField(); i++ {
+		if jsonName(typ.Field(i)) == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// ticketJSONFields lists the JSON names of the TicketJSON fields
+func ticketJSONFields() []string {
+	typ := reflect.TypeOf(TicketJSON{})
+	names := make([]string, typ.NumField())
+	for i := range names {
+		names[i] = jsonName(typ.Field(i))
+	}
+	return names
+}
+
+// jsonName returns the name a struct field is encoded under
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
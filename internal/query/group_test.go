@@ -0,0 +1,75 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestGroupBy(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{ID: "a", Status: ticket.StatusOpen, Assignee: "alice", Tags: []string{"backend", "urgent"}, Priority: 1, Estimate: 3},
+		{ID: "b", Status: ticket.StatusOpen, Tags: []string{"backend"}, Priority: 1, Estimate: 3},
+		{ID: "c", Status: ticket.StatusClosed, Assignee: "alice"},
+		{ID: "d", Status: ticket.StatusInProgress, Assignee: "bob"},
+		{ID: "e", Status: ticket.StatusOpen},
+	}
+	var lines []string
+	for _, tk := range tickets {
+		line, err := ToJSON(tk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+
+	tests := []struct {
+		field string
+		want  []Group
+	}{
+		{"status", []Group{{"open", 3}, {"closed", 1}, {"in_progress", 1}}},
+		{"assignee", []Group{{"(none)", 2}, {"alice", 2}, {"bob", 1}}},
+		{"tags", []Group{{"(none)", 3}, {"backend", 2}, {"urgent", 1}}},
+		{"priority_num", []Group{{"0", 3}, {"1", 2}}},
+		{"estimate", []Group{{"(none)", 3}, {"3", 2}}}, // Zero is unset
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, err := GroupBy(lines, tt.field)
+			if err != nil {
+				t.Fatalf("GroupBy() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupBy(%q) = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := GroupBy(lines, "title"); err == nil {
+		t.Error("GroupBy() should reject fields not in the JSON output")
+	}
+
+	// Every field either groups or is rejected with an error
+	for _, field := range ticketJSONFields() {
+		t.Run("field "+field, func(t *testing.T) {
+			groups, err := GroupBy(lines, field)
+			if field == "notes" {
+				if err == nil {
+					t.Error("GroupBy() should reject notes")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GroupBy() error = %v", err)
+			}
+			total := 0
+			for _, g := range groups {
+				total += g.Count
+			}
+			if total < len(lines) {
+				t.Errorf("GroupBy(%q) counted %d tickets, want at least %d", field, total, len(lines))
+			}
+		})
+	}
+}
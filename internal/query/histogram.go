@@ -0,0 +1,62 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Bucket is one time window of a histogram
+type Bucket struct {
+	Label string
+	Count int
+}
+
+// bucketLabels format a creation time as its bucket label; labels sort
+// chronologically
+var bucketLabels = map[string]func(time.Time) string{
+	"day": func(t time.Time) string { return t.Format("2006-01-02") },
+	"week": func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	},
+	"month": func(t time.Time) string { return t.Format("2006-01") },
+}
+
+// ValidBucket reports whether name is a supported histogram bucket
+func ValidBucket(name string) bool {
+	_, ok := bucketLabels[name]
+	return ok
+}
+
+// CreatedHistogram counts JSON tickets per creation-time bucket (day, week or
+// month), in chronological order. Weeks are ISO weeks.
+func CreatedHistogram(jsonLines []string, bucket string) ([]Bucket, error) {
+	label, ok := bucketLabels[bucket]
+	if !ok {
+		return nil, fmt.Errorf("invalid bucket %q (use day, week or month)", bucket)
+	}
+
+	counts := make(map[string]int)
+	for _, line := range jsonLines {
+		var tj TicketJSON
+		if err := json.Unmarshal([]byte(line), &tj); err != nil {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, tj.Created)
+		if err != nil {
+			continue
+		}
+		counts[label(created)]++
+	}
+
+	buckets := make([]Bucket, 0, len(counts))
+	for l, n := range counts {
+		buckets = append(buckets, Bucket{Label: l, Count: n})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Label < buckets[j].Label
+	})
+	return buckets, nil
+}
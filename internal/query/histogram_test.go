@@ -0,0 +1,57 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lo5/tk/internal/ticket"
+)
+
+func TestCreatedHistogram(t *testing.T) {
+	created := []string{
+		"2026-01-14T10:00:00Z", // Wednesday, week 3
+		"2026-01-05T09:00:00Z", // Monday, week 2
+		"2026-01-11T23:00:00Z", // Sunday, week 2
+		"2026-01-12T00:00:00Z", // Monday, week 3
+	}
+	var lines []string
+	for i, c := range created {
+		ts, _ := time.Parse(time.RFC3339, c)
+		line, err := ToJSON(&ticket.Ticket{ID: string(rune('a' + i)), Created: ts})
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+
+	tests := []struct {
+		bucket string
+		want   []Bucket
+	}{
+		{"week", []Bucket{{"2026-W02", 2}, {"2026-W03", 2}}},
+		{"month", []Bucket{{"2026-01", 4}}},
+		{"day", []Bucket{{"2026-01-05", 1}, {"2026-01-11", 1}, {"2026-01-12", 1}, {"2026-01-14", 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bucket, func(t *testing.T) {
+			got, err := CreatedHistogram(lines, tt.bucket)
+			if err != nil {
+				t.Fatalf("CreatedHistogram() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("CreatedHistogram() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("bucket %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("invalid bucket", func(t *testing.T) {
+		if _, err := CreatedHistogram(lines, "year"); err == nil {
+			t.Error("expected error for unsupported bucket")
+		}
+	})
+}
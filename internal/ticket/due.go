@@ -0,0 +1,27 @@
+package ticket
+
+import (
+	"fmt"
+	"time"
+)
+
+// DueLayout is the format of due dates given on the command line
+const DueLayout = "2006-01-02"
+
+// ParseDue parses a due date in YYYY-MM-DD form, or a full RFC3339
+// timestamp
+func ParseDue(value string) (time.Time, error) {
+	if due, err := time.Parse(time.RFC3339, value); err == nil {
+		return due.UTC(), nil
+	}
+	due, err := time.Parse(DueLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due date '%s'. Use YYYY-MM-DD", value)
+	}
+	return due, nil
+}
+
+// Overdue reports whether an unclosed ticket's due date is before now
+func (t *Ticket) Overdue(now time.Time) bool {
+	return t.Status != StatusClosed && !t.Due.IsZero() && t.Due.Before(now)
+}
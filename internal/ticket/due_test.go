@@ -0,0 +1,99 @@
+package ticket
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDueField(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2026-03-04T09:30:00Z", time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)},
+		{"2026-03-04", time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"soon", time.Time{}},
+	}
+	for _, tt := range tests {
+		content := "---\nid: d-1\nstatus: open\ndue: " + tt.value + "\n---\n# Due soon\n"
+		tk, err := Parse(strings.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !tk.Due.Equal(tt.want) {
+			t.Errorf("due: %s parsed as %v, want %v", tt.value, tk.Due, tt.want)
+		}
+		if _, ok := tk.Extra["due"]; ok {
+			t.Errorf("due: %s should not be kept as an extra key", tt.value)
+		}
+	}
+
+	if _, err := ParseDue("03/04/2026"); err == nil {
+		t.Error("ParseDue() should reject other formats")
+	}
+}
+
+func TestDueRoundTrip(t *testing.T) {
+	originalDue := time.Date(2025, 3, 1, 17, 15, 30, 0, time.UTC)
+
+	ticket := &Ticket{
+		ID:       "test-1234",
+		Status:   StatusOpen,
+		Deps:     []string{},
+		Links:    []string{},
+		Created:  time.Date(2025, 1, 11, 10, 30, 45, 0, time.UTC),
+		Type:     TypeTask,
+		Priority: 2,
+		Due:      originalDue,
+		Title:    "Test",
+	}
+
+	var buf bytes.Buffer
+	if err := Format(&buf, ticket); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "due: 2025-03-01T17:15:30Z\n") {
+		t.Errorf("due should be written as RFC3339, got:\n%s", buf.String())
+	}
+
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !parsed.Due.Equal(originalDue) {
+		t.Errorf("Round-trip failed: got %v, expected %v", parsed.Due, originalDue)
+	}
+
+	parsed.Due = time.Time{}
+	buf.Reset()
+	if err := Format(&buf, parsed); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "due:") {
+		t.Errorf("zero due date should be omitted, got:\n%s", buf.String())
+	}
+}
+
+func TestOverdue(t *testing.T) {
+	now := time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		tk   Ticket
+		want bool
+	}{
+		{"open past due", Ticket{Status: StatusOpen, Due: past}, true},
+		{"in progress past due", Ticket{Status: StatusInProgress, Due: past}, true},
+		{"closed past due", Ticket{Status: StatusClosed, Due: past}, false},
+		{"due later", Ticket{Status: StatusOpen, Due: now.Add(time.Hour)}, false},
+		{"no due date", Ticket{Status: StatusOpen}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.tk.Overdue(now); got != tt.want {
+			t.Errorf("%s: Overdue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
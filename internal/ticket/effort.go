@@ -0,0 +1,79 @@
+package ticket
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseEffort parses an amount of effort: a plain number of hours such as
+// "5" or "1.5", or a duration such as "90m" or "2h30m"
+func ParseEffort(value string) (time.Duration, error) {
+	if hours, err := strconv.ParseFloat(value, 64); err == nil && hours >= 0 {
+		return time.Duration(hours * float64(time.Hour)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid effort '%s'. Use hours (e.g. 5) or a duration (e.g. 90m)", value)
+	}
+	return d, nil
+}
+
+// ParseEstimate reads an estimate frontmatter value as whole hours. Older
+// tickets may hold fractional hours or a duration such as 90m; those are
+// rounded up to whole hours, so a small estimate isn't lost, and exact
+// reports false.
+func ParseEstimate(value string) (hours int, exact bool, err error) {
+	if value == "" {
+		return 0, true, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+		return n, true, nil
+	}
+	d, err := ParseEffort(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid estimate '%s'. Use whole hours (e.g. 5)", value)
+	}
+	return int(math.Ceil(d.Hours())), false, nil
+}
+
+// FormatEffort renders an amount of effort in hours, e.g. "2h" or "1.5h"
+func FormatEffort(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', -1, 64) + "h"
+}
+
+// Worklog sums the time logged in a body's ## Worklog section. Each list
+// item starts with an amount, optionally preceded by an RFC 3339 timestamp:
+//
+//   - 2026-10-17T09:00:00Z 2h fixed the parser
+//   - 30m review
+//
+// Items without a readable amount are skipped. Reports false when the body
+// has no ## Worklog section.
+func Worklog(body string) (time.Duration, bool) {
+	section, found := GetSection(body, WorklogSection)
+	if !found {
+		return 0, false
+	}
+
+	var total time.Duration
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimPrefix(line, "- "))
+		if len(fields) > 1 {
+			if _, err := time.Parse(time.RFC3339, fields[0]); err == nil {
+				fields = fields[1:]
+			}
+		}
+		if d, err := ParseEffort(fields[0]); err == nil {
+			total += d
+		}
+	}
+	return total, found
+}
@@ -0,0 +1,52 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEffort(t *testing.T) {
+	tests := map[string]time.Duration{
+		"5":     5 * time.Hour,
+		"1.5":   90 * time.Minute,
+		"90m":   90 * time.Minute,
+		"2h30m": 150 * time.Minute,
+	}
+	for value, want := range tests {
+		if got, err := ParseEffort(value); err != nil || got != want {
+			t.Errorf("ParseEffort(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "soon", "-2h", "-1"} {
+		if _, err := ParseEffort(value); err == nil {
+			t.Errorf("ParseEffort(%q) should fail", value)
+		}
+	}
+	if got := FormatEffort(90 * time.Minute); got != "1.5h" {
+		t.Errorf("FormatEffort(90m) = %q, want 1.5h", got)
+	}
+}
+
+func TestWorklog(t *testing.T) {
+	body := `Some description.
+
+## Worklog
+
+- 2026-10-17T09:00:00Z 1h investigated
+- 30m review
+- 2026-10-17T15:00:00Z 0.5 wrap up
+- forgot the amount
+
+## Notes
+
+- 8h not part of the worklog
+`
+	total, ok := Worklog(body)
+	if !ok || total != 2*time.Hour {
+		t.Errorf("Worklog() = %v, %v; want 2h, true", total, ok)
+	}
+
+	if _, ok := Worklog("No sections here.\n"); ok {
+		t.Error("Worklog() without a section should report false")
+	}
+}
@@ -21,23 +21,10 @@ func GenerateID(cwd string) string {
 	dirName := filepath.Base(cwd)
 
 	// Extract first letter of each segment (split by hyphen or underscore)
-	segments := strings.FieldsFunc(dirName, func(r rune) bool {
+	prefix := segmentPrefix(dirName, func(r rune) bool {
 		return r == '-' || r == '_'
 	})
 
-	var prefix string
-	for _, seg := range segments {
-		if len(seg) > 0 {
-			// Take first rune to handle unicode correctly
-			for _, r := range seg {
-				if unicode.IsLetter(r) || unicode.IsDigit(r) {
-					prefix += string(r)
-					break
-				}
-			}
-		}
-	}
-
 	// Fallback to first 3 chars if no segments produced a prefix
 	if prefix == "" {
 		runes := []rune(dirName)
@@ -48,13 +35,53 @@ func GenerateID(cwd string) string {
 		}
 	}
 
-	// 4-char nanoid with lowercase alphanumeric charset (a-z0-9)
+	return fmt.Sprintf("%s-%s", strings.ToLower(prefix), randomSuffix())
+}
+
+// GenerateTitleID generates a ticket ID like GenerateID, but with the prefix
+// taken from the words of a title rather than the directory name, e.g.
+// "Fix login redirect" gives flr-{4-char-alphanumeric}. It returns an empty
+// string if the title has no letters or digits.
+func GenerateTitleID(title string) string {
+	prefix := TitlePrefix(title)
+	if prefix == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s", prefix, randomSuffix())
+}
+
+// TitlePrefix returns the lowercase first letter or digit of each word of a
+// title, as used by GenerateTitleID
+func TitlePrefix(title string) string {
+	prefix := segmentPrefix(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.ToLower(prefix)
+}
+
+// segmentPrefix joins the first letter or digit of each segment of name
+func segmentPrefix(name string, isSep func(rune) bool) string {
+	var prefix string
+	for _, seg := range strings.FieldsFunc(name, isSep) {
+		// Take first rune to handle unicode correctly
+		for _, r := range seg {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				prefix += string(r)
+				break
+			}
+		}
+	}
+	return prefix
+}
+
+// randomSuffix returns a 4-char nanoid with lowercase alphanumeric charset
+// (a-z0-9)
+func randomSuffix() string {
 	alphabet := "abcdefghijklmnopqrstuvwxyz0123456789"
 	hashStr, err := gonanoid.Generate(alphabet, 4)
 	if err != nil {
 		// Fallback to timestamp-based (extremely unlikely)
 		hashStr = fmt.Sprintf("%04d", time.Now().UnixNano()%10000)
 	}
-
-	return fmt.Sprintf("%s-%s", strings.ToLower(prefix), hashStr)
+	return hashStr
 }
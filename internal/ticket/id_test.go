@@ -263,3 +263,30 @@ func TestPrefixLowercase(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateTitleID(t *testing.T) {
+	tests := []struct {
+		title  string
+		prefix string
+	}{
+		{"Fix login redirect", "flr"},
+		{"Add CSV export (v2)", "acev"},
+		{"  Écrire la doc  ", "éld"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := TitlePrefix(tt.title); got != tt.prefix {
+			t.Errorf("TitlePrefix(%q) = %q, want %q", tt.title, got, tt.prefix)
+		}
+		id := GenerateTitleID(tt.title)
+		if tt.prefix == "" {
+			if id != "" {
+				t.Errorf("GenerateTitleID(%q) = %q, want empty", tt.title, id)
+			}
+			continue
+		}
+		if !strings.HasPrefix(id, tt.prefix+"-") || len(id) != len(tt.prefix)+5 {
+			t.Errorf("GenerateTitleID(%q) = %q, want %s-xxxx", tt.title, id, tt.prefix)
+		}
+	}
+}
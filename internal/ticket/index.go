@@ -0,0 +1,165 @@
+package ticket
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IndexFile is the optional ticket index in the tickets directory, written
+// by Reindex. It lists every ticket file so that resolving a partial ID
+// doesn't have to read the directory, along with the modification times of
+// the directories it was built from. When those no longer match the index is
+// ignored and IDs are resolved by a full scan, so a stale index never changes
+// the result.
+const IndexFile = ".index"
+
+// indexHeader is the first line of the index, identifying its format
+const indexHeader = "tk-index 1"
+
+// indexPath returns the path of the index file
+func (s *FileStore) indexPath() string {
+	return filepath.Join(s.dir, IndexFile)
+}
+
+// Reindex rebuilds the ticket index, creating it if missing, and returns the
+// number of ticket files indexed
+func (s *FileStore) Reindex() (int, error) {
+	if err := s.EnsureDir(); err != nil {
+		return 0, fmt.Errorf("creating tickets directory: %w", err)
+	}
+
+	// Adding the index file changes the directory's modification time, so
+	// it must exist before the times are recorded
+	f, err := os.OpenFile(s.indexPath(), os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("creating index: %w", err)
+	}
+	f.Close()
+
+	dirs, err := s.indexDirs()
+	if err != nil {
+		return 0, err
+	}
+	files, err := s.files()
+	if err != nil {
+		return 0, err
+	}
+
+	var b strings.Builder
+	b.WriteString(indexHeader + "\n")
+	for _, dir := range dirs {
+		info, err := os.Stat(filepath.Join(s.dir, filepath.FromSlash(dir)))
+		if err != nil {
+			return 0, fmt.Errorf("reading index directory: %w", err)
+		}
+		fmt.Fprintf(&b, "dir %s %d\n", dir, info.ModTime().UnixNano())
+	}
+	for _, file := range files {
+		rel, err := filepath.Rel(s.dir, file.path)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Fprintf(&b, "file %s\n", filepath.ToSlash(rel))
+	}
+
+	// Rewritten in place, which leaves the directory's modification time alone
+	if err := os.WriteFile(s.indexPath(), []byte(b.String()), 0644); err != nil {
+		return 0, fmt.Errorf("writing index: %w", err)
+	}
+	return len(files), nil
+}
+
+// indexDirs returns the directories whose contents the index covers: the
+// tickets directory and, when sharded, each shard subdirectory
+func (s *FileStore) indexDirs() ([]string, error) {
+	dirs := []string{"."}
+	if !s.sharded {
+		return dirs, nil
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading tickets directory: %w", err)
+	}
+	for _, entry := range entries {
+		// Hidden directories such as the archive are never shards
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+	return dirs, nil
+}
+
+// syncIndex rebuilds the index after the store changes, if there is one.
+// Failures are ignored: the index is only a shortcut, and one left stale is
+// not used.
+func (s *FileStore) syncIndex() {
+	if _, err := os.Stat(s.indexPath()); err != nil {
+		return
+	}
+	s.Reindex()
+}
+
+// indexedIDs returns the ticket IDs listed in the index, in the order files
+// returns them, or false when the index is missing, malformed or stale
+func (s *FileStore) indexedIDs() ([]string, bool) {
+	info, err := os.Stat(s.indexPath())
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(s.indexPath())
+	if err != nil {
+		return nil, false
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if lines[0] != indexHeader {
+		return nil, false
+	}
+
+	checkedTop := false
+	var ids []string
+	for _, line := range lines[1:] {
+		kind, rest, _ := strings.Cut(line, " ")
+		switch kind {
+		case "dir":
+			dir, nanos, ok := strings.Cut(rest, " ")
+			recorded, err := strconv.ParseInt(nanos, 10, 64)
+			if !ok || err != nil || !s.dirUnchanged(dir, recorded, info.ModTime()) {
+				return nil, false
+			}
+			checkedTop = checkedTop || dir == "."
+		case "file":
+			ids = append(ids, strings.TrimSuffix(path.Base(rest), ".md"))
+		default:
+			return nil, false
+		}
+	}
+	return ids, checkedTop
+}
+
+// dirUnchanged reports whether an indexed directory still has the
+// modification time recorded for it. With whole-second timestamps a change
+// made in the same second the index was written could carry the recorded
+// time, so there a directory modified in that second is treated as changed.
+// Finer timestamps are trusted as they are, so the index written by a
+// Create, Update or Delete is used straight away.
+func (s *FileStore) dirUnchanged(dir string, recorded int64, indexed time.Time) bool {
+	info, err := os.Stat(filepath.Join(s.dir, filepath.FromSlash(dir)))
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	mtime := info.ModTime()
+	if mtime.UnixNano() != recorded {
+		return false
+	}
+	if mtime.Nanosecond() == 0 {
+		return mtime.Before(indexed.Truncate(time.Second))
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+package ticket
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// settleIndex backdates the tickets directories and rebuilds the index, so
+// that it counts as up to date rather than written in the same second as
+// the last change
+func settleIndex(t *testing.T, store *FileStore) {
+	t.Helper()
+	if _, err := store.Reindex(); err != nil {
+		t.Fatalf("Reindex() error = %v", err)
+	}
+	dirs, err := store.indexDirs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Minute)
+	for _, dir := range dirs {
+		if err := os.Chtimes(filepath.Join(store.Dir(), dir), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Reindex(); err != nil {
+		t.Fatalf("Reindex() error = %v", err)
+	}
+	if _, ok := store.indexedIDs(); !ok {
+		t.Fatal("index should be up to date after settling")
+	}
+}
+
+func TestReindex(t *testing.T) {
+	for _, sharded := range []bool{false, true} {
+		name := "flat"
+		if sharded {
+			name = "sharded"
+		}
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), ".tickets")
+			store := NewFileStore(dir)
+			if sharded {
+				store = NewShardedFileStore(dir)
+			}
+			for _, id := range []string{"abc-1111", "abc-2222", "xyz-3333"} {
+				if err := store.Create(createTestTicket(id)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			n, err := store.Reindex()
+			if err != nil {
+				t.Fatalf("Reindex() error = %v", err)
+			}
+			if n != 3 {
+				t.Errorf("Reindex() = %d, want 3", n)
+			}
+			settleIndex(t, store)
+
+			ids, _ := store.indexedIDs()
+			if got := strings.Join(ids, ","); got != "abc-1111,abc-2222,xyz-3333" {
+				t.Errorf("indexed IDs = %s", got)
+			}
+		})
+	}
+}
+
+// TestResolveIDWithIndex checks that resolution through the index gives the
+// same answers as a full scan
+func TestResolveIDWithIndex(t *testing.T) {
+	store, _ := newTestStore(t)
+	for _, id := range []string{"abc-1111", "abc-2222", "xyz-3333"} {
+		if err := store.Create(createTestTicket(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	partials := []string{"abc-1111", "2222", "xyz", "abc", "nope"}
+	type result struct {
+		id  string
+		err string
+	}
+	resolve := func() []result {
+		var results []result
+		for _, p := range partials {
+			id, err := store.ResolveID(p)
+			r := result{id: id}
+			if err != nil {
+				r.err = err.Error()
+				var ambiguous ErrAmbiguous
+				if errors.As(err, &ambiguous) {
+					r.err += " " + strings.Join(ambiguous.Matches, ",")
+				}
+			}
+			results = append(results, r)
+		}
+		return results
+	}
+
+	scanned := resolve()
+	settleIndex(t, store)
+	indexed := resolve()
+	for i := range partials {
+		if scanned[i] != indexed[i] {
+			t.Errorf("ResolveID(%q) = %+v with index, %+v without", partials[i], indexed[i], scanned[i])
+		}
+	}
+}
+
+func TestIndexUsedWhenFresh(t *testing.T) {
+	store, dir := newTestStore(t)
+	if err := store.Create(createTestTicket("abc-1111")); err != nil {
+		t.Fatal(err)
+	}
+	settleIndex(t, store)
+
+	// An index listing a ticket the directory lacks shows it is consulted
+	index := filepath.Join(dir, IndexFile)
+	data, _ := os.ReadFile(index)
+	if err := os.WriteFile(index, append(data, []byte("file ghost-9999.md\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := store.ResolveID("ghost"); err != nil || id != "ghost-9999" {
+		t.Errorf("ResolveID(ghost) = %q, %v; want the indexed ID", id, err)
+	}
+}
+
+func TestIndexUsedAfterCreate(t *testing.T) {
+	store, dir := newTestStore(t)
+	if err := store.Create(createTestTicket("abc-1111")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Reindex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(createTestTicket("abc-2222")); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dir); err != nil || info.ModTime().Nanosecond() == 0 {
+		t.Skip("filesystem has whole-second timestamps")
+	}
+
+	// Plant an entry, keeping the index's modification time, to show the
+	// index maintained by Create is consulted right away
+	index := filepath.Join(dir, IndexFile)
+	info, _ := os.Stat(index)
+	data, _ := os.ReadFile(index)
+	if err := os.WriteFile(index, append(data, []byte("file ghost-9999.md\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(index, info.ModTime(), info.ModTime())
+	if id, err := store.ResolveID("ghost"); err != nil || id != "ghost-9999" {
+		t.Errorf("ResolveID(ghost) = %q, %v; want the indexed ID", id, err)
+	}
+}
+
+func TestIndexStale(t *testing.T) {
+	t.Run("file added behind the store's back", func(t *testing.T) {
+		store, dir := newTestStore(t)
+		if err := store.Create(createTestTicket("abc-1111")); err != nil {
+			t.Fatal(err)
+		}
+		settleIndex(t, store)
+
+		content := "---\nid: abc-2222\nstatus: open\n---\n# Added by hand\n"
+		if err := os.WriteFile(filepath.Join(dir, "abc-2222.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := store.indexedIDs(); ok {
+			t.Error("index should be stale after the directory changed")
+		}
+		if _, err := store.ResolveID("2222"); err != nil {
+			t.Errorf("ResolveID should fall back to a scan: %v", err)
+		}
+		var ambiguous ErrAmbiguous
+		if _, err := store.ResolveID("abc"); !errors.As(err, &ambiguous) {
+			t.Errorf("ResolveID(abc) error = %v, want ErrAmbiguous", err)
+		}
+	})
+
+	t.Run("malformed index", func(t *testing.T) {
+		store, dir := newTestStore(t)
+		if err := store.Create(createTestTicket("abc-1111")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, IndexFile), []byte("garbage\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := store.indexedIDs(); ok {
+			t.Error("malformed index should not be used")
+		}
+		if id, err := store.ResolveID("1111"); err != nil || id != "abc-1111" {
+			t.Errorf("ResolveID(1111) = %q, %v", id, err)
+		}
+	})
+}
+
+func TestIndexMaintained(t *testing.T) {
+	store, dir := newTestStore(t)
+	if err := store.Create(createTestTicket("abc-1111")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Reindex(); err != nil {
+		t.Fatal(err)
+	}
+
+	listed := func() string {
+		data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var files []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if name, ok := strings.CutPrefix(line, "file "); ok {
+				files = append(files, name)
+			}
+		}
+		return strings.Join(files, ",")
+	}
+
+	if err := store.Create(createTestTicket("abc-2222")); err != nil {
+		t.Fatal(err)
+	}
+	if got := listed(); got != "abc-1111.md,abc-2222.md" {
+		t.Errorf("after Create, index lists %s", got)
+	}
+
+	if err := store.Delete("abc-1111"); err != nil {
+		t.Fatal(err)
+	}
+	if got := listed(); got != "abc-2222.md" {
+		t.Errorf("after Delete, index lists %s", got)
+	}
+}
+
+func TestNoIndexByDefault(t *testing.T) {
+	store, dir := newTestStore(t)
+	if err := store.Create(createTestTicket("abc-1111")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, IndexFile)); !os.IsNotExist(err) {
+		t.Errorf("index should only be created by Reindex, stat error = %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Link is an entry of a ticket's links array. Entries are stored either as a
+// plain ID or as "id:rel" when the relationship is typed.
+type Link struct {
+	ID  string
+	Rel string // Empty for an untyped link
+}
+
+// inverseRels maps directional relationship types to the type recorded on the
+// other ticket. Types not listed are symmetric.
+var inverseRels = map[string]string{
+	"duplicates":    "duplicated-by",
+	"duplicated-by": "duplicates",
+	"causes":        "caused-by",
+	"caused-by":     "causes",
+	"supersedes":    "superseded-by",
+	"superseded-by": "supersedes",
+}
+
+var relPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// ParseLink parses a links array entry
+func ParseLink(s string) Link {
+	id, rel, _ := strings.Cut(s, ":")
+	return Link{ID: id, Rel: rel}
+}
+
+// String formats the link as stored in the links array
+func (l Link) String() string {
+	if l.Rel == "" {
+		return l.ID
+	}
+	return l.ID + ":" + l.Rel
+}
+
+// ValidateRel checks that a relationship type is a lowercase word such as
+// "duplicates" or "relates-to"
+func ValidateRel(rel string) error {
+	if !relPattern.MatchString(rel) {
+		return fmt.Errorf("invalid relationship '%s'. Use lowercase letters, digits and dashes", rel)
+	}
+	return nil
+}
+
+// InverseRel returns the relationship type recorded on the other end of a
+// link of the given type
+func InverseRel(rel string) string {
+	if inverse, ok := inverseRels[rel]; ok {
+		return inverse
+	}
+	return rel
+}
+
+// LinkIDs returns the IDs of the linked tickets, without relationship types
+func (t *Ticket) LinkIDs() []string {
+	ids := make([]string, len(t.Links))
+	for i, l := range t.Links {
+		ids[i] = ParseLink(l).ID
+	}
+	return ids
+}
+
+// FindLink returns the link to the given ticket ID, if any
+func (t *Ticket) FindLink(id string) (Link, bool) {
+	for _, l := range t.Links {
+		if link := ParseLink(l); link.ID == id {
+			return link, true
+		}
+	}
+	return Link{}, false
+}
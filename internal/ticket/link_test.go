@@ -0,0 +1,80 @@
+package ticket
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Link
+	}{
+		{"abc-1234", Link{ID: "abc-1234"}},
+		{"abc-1234:duplicates", Link{ID: "abc-1234", Rel: "duplicates"}},
+	}
+
+	for _, tt := range tests {
+		got := ParseLink(tt.in)
+		if got != tt.want {
+			t.Errorf("ParseLink(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.in {
+			t.Errorf("String() = %q, want %q", got.String(), tt.in)
+		}
+	}
+}
+
+func TestInverseRel(t *testing.T) {
+	if got := InverseRel("duplicates"); got != "duplicated-by" {
+		t.Errorf("InverseRel(duplicates) = %q", got)
+	}
+	if got := InverseRel("relates-to"); got != "relates-to" {
+		t.Errorf("InverseRel(relates-to) = %q, want symmetric", got)
+	}
+}
+
+func TestValidateRel(t *testing.T) {
+	for _, rel := range []string{"duplicates", "relates-to", "v2"} {
+		if err := ValidateRel(rel); err != nil {
+			t.Errorf("ValidateRel(%q) error = %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"", "Dup", "a b", "a:b", "a,b"} {
+		if err := ValidateRel(rel); err == nil {
+			t.Errorf("ValidateRel(%q) expected error", rel)
+		}
+	}
+}
+
+func TestTypedLinkRoundTrip(t *testing.T) {
+	original := &Ticket{
+		ID:     "rt-1234",
+		Status: StatusOpen,
+		Deps:   []string{},
+		Links:  []string{"a-1111:duplicates", "b-2222"},
+		Type:   TypeTask,
+		Title:  "Typed links",
+	}
+
+	var buf bytes.Buffer
+	if err := Format(&buf, original); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(parsed.Links) != 2 || parsed.Links[0] != "a-1111:duplicates" || parsed.Links[1] != "b-2222" {
+		t.Errorf("Links = %v, want typed and plain entries preserved", parsed.Links)
+	}
+
+	ids := parsed.LinkIDs()
+	if ids[0] != "a-1111" || ids[1] != "b-2222" {
+		t.Errorf("LinkIDs() = %v", ids)
+	}
+	if l, ok := parsed.FindLink("a-1111"); !ok || l.Rel != "duplicates" {
+		t.Errorf("FindLink() = %+v, %v", l, ok)
+	}
+}
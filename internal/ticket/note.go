@@ -0,0 +1,67 @@
+package ticket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Note is a timestamped entry in a ticket's Notes section, as added by
+// tk note
+type Note struct {
+	Time time.Time
+	Text string
+}
+
+// FormatNote renders a Notes section entry: the UTC time in bold on a line
+// of its own, a blank line, then the text. ParseNotes reads entries back.
+func FormatNote(at time.Time, text string) string {
+	return fmt.Sprintf("**%s**\n\n%s", at.UTC().Format(time.RFC3339), strings.Trim(text, "\n"))
+}
+
+// ParseNotes returns the entries of the Notes section of a body, oldest
+// first. Each entry starts at a line holding only a bold RFC 3339 time and
+// runs until the next one; text before the first entry is not a note.
+func ParseNotes(body string) []Note {
+	section, ok := GetSection(body, NotesSection)
+	if !ok {
+		return nil
+	}
+
+	var notes []Note
+	var text []string
+	flush := func() {
+		if len(notes) > 0 {
+			notes[len(notes)-1].Text = strings.Join(trimBlankLines(text), "\n")
+		}
+		text = nil
+	}
+	for _, line := range strings.Split(section, "\n") {
+		if at, ok := noteHeading(line); ok {
+			flush()
+			notes = append(notes, Note{Time: at})
+			continue
+		}
+		text = append(text, line)
+	}
+	flush()
+	return notes
+}
+
+// noteHeading parses a "**<RFC 3339 time>**" entry heading
+func noteHeading(line string) (time.Time, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "**") || !strings.HasSuffix(line, "**") || len(line) < 4 {
+		return time.Time{}, false
+	}
+	at, err := time.Parse(time.RFC3339, line[2:len(line)-2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// Notes returns the entries of the ticket's Notes section
+func (t *Ticket) Notes() []Note {
+	return ParseNotes(t.Body)
+}
@@ -0,0 +1,51 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNotes(t *testing.T) {
+	first := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	second := time.Date(2026, 3, 2, 17, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	body := "Original description.\n\n## Design\n\nKeep it simple.\n"
+	body = AppendToSection(body, NotesSection, FormatNote(first, "First note"))
+	body = AppendToSection(body, NotesSection, "\n"+FormatNote(second, "Second note\nspans lines\n"))
+
+	notes := ParseNotes(body)
+	if len(notes) != 2 {
+		t.Fatalf("ParseNotes() returned %d notes, want 2:\n%s", len(notes), body)
+	}
+	if !notes[0].Time.Equal(first) || notes[0].Text != "First note" {
+		t.Errorf("notes[0] = %+v", notes[0])
+	}
+	if !notes[1].Time.Equal(second) || notes[1].Text != "Second note\nspans lines" {
+		t.Errorf("notes[1] = %+v", notes[1])
+	}
+
+	// The rest of the body is untouched
+	if content, _ := GetSection(body, "Design"); content != "Keep it simple." {
+		t.Errorf("Design section = %q", content)
+	}
+	if body[:len("Original description.")] != "Original description." {
+		t.Errorf("description changed:\n%s", body)
+	}
+
+	// The ticket reports the same notes
+	tk := &Ticket{ID: "n-1", Status: StatusOpen, Type: TypeTask, Title: "Notes", Body: body}
+	if got := tk.Notes(); len(got) != 2 || got[1].Text != notes[1].Text {
+		t.Errorf("Ticket.Notes() = %+v", got)
+	}
+}
+
+func TestParseNotesIgnoresFreeText(t *testing.T) {
+	body := "## Notes\n\nSome context before any entry.\n\n**not a time**\n\n**2026-03-01T09:30:00Z**\n\nReal note\n"
+	notes := ParseNotes(body)
+	if len(notes) != 1 || notes[0].Text != "Real note" {
+		t.Errorf("ParseNotes() = %+v, want only the timestamped entry", notes)
+	}
+	if notes := ParseNotes("No notes here\n"); notes != nil {
+		t.Errorf("ParseNotes() without a section = %+v, want nil", notes)
+	}
+}
@@ -1,11 +1,11 @@
 package ticket
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,90 +19,266 @@ type frontmatter struct {
 	Status      Status   `yaml:"status"`
 	Deps        []string `yaml:"deps,flow"`
 	Links       []string `yaml:"links,flow"`
+	Tags        []string `yaml:"tags,flow,omitempty"`
 	Created     string   `yaml:"created"`
 	Type        Type     `yaml:"type"`
 	Priority    int      `yaml:"priority"`
 	Assignee    string   `yaml:"assignee,omitempty"`
 	ExternalRef string   `yaml:"external-ref,omitempty"`
 	Parent      string   `yaml:"parent,omitempty"`
+	CreatedBy   string   `yaml:"created-by,omitempty"`
+	Estimate    string   `yaml:"estimate,omitempty"`
+	Due         string   `yaml:"due,omitempty"`
 }
 
-// Parse reads a ticket from a reader and returns the parsed Ticket
-func Parse(r io.Reader) (*Ticket, error) {
-	scanner := bufio.NewScanner(r)
+// knownKeys lists the frontmatter keys mapped to Ticket fields
+var knownKeys = map[string]bool{
+	"id": true, "status": true, "deps": true, "links": true, "tags": true, "created": true,
+	"type": true, "priority": true, "assignee": true, "external-ref": true,
+	"parent": true, "created-by": true, "estimate": true, "due": true,
+}
+
+// extraKeys collects unknown scalar keys from the frontmatter so they survive
+// a Parse/Format round trip. Non-scalar unknown values are not preserved.
+func extraKeys(yamlContent string) map[string]string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlContent), &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var extra map[string]string
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if knownKeys[key.Value] || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]string)
+		}
+		extra[key.Value] = value.Value
+	}
+	return extra
+}
+
+// Canonicalize parses ticket content and re-renders it with Format. It
+// fails rather than return content that would lose information: tickets
+// without an ID or a readable created date, with an estimate that isn't
+// whole hours, or with non-scalar unknown frontmatter keys, which Format
+// cannot reproduce.
+func Canonicalize(content string) (string, error) {
+	t, err := Parse(strings.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	if t.ID == "" {
+		return "", fmt.Errorf("missing id")
+	}
+	if t.Created.IsZero() {
+		return "", fmt.Errorf("missing or unreadable created date")
+	}
+
+	yamlContent, _, _ := SplitFrontmatter(content)
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlContent), &doc); err == nil && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		mapping := doc.Content[0]
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			key, value := mapping.Content[i], mapping.Content[i+1]
+			if key.Value == "estimate" {
+				hours, exact, err := ParseEstimate(value.Value)
+				if err != nil {
+					return "", err
+				}
+				if !exact {
+					return "", fmt.Errorf("estimate '%s' is not whole hours (read as %d); write it as a number of hours", value.Value, hours)
+				}
+			}
+			if !knownKeys[key.Value] && value.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("frontmatter key %q is not a scalar", key.Value)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Format(&buf, t); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatScalar renders a string as a YAML scalar, leaving it unquoted when it
+// reads back unchanged
+func formatScalar(value string) string {
+	var check map[string]string
+	if err := yaml.Unmarshal([]byte("k: "+value), &check); err == nil && check["k"] == value {
+		return value
+	}
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return value
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SortedExtraKeys returns the keys of a ticket's extra frontmatter in order
+func SortedExtraKeys(extra map[string]string) []string {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SplitFrontmatter splits raw ticket content into the YAML frontmatter text
+// (without delimiters) and the body that follows the closing delimiter.
+// bodyLineOffset is the number of lines preceding the body, so body line i
+// (0-based) is line bodyLineOffset+i+1 of the file. If the frontmatter is
+// never closed, the body is empty and the offset is the total line count.
+func SplitFrontmatter(content string) (fmText, body string, bodyLineOffset int) {
+	lines := splitLines(content)
+
 	var frontmatterLines []string
-	var bodyLines []string
 	inFrontmatter := false
-	frontmatterDone := false
 	foundFirstDelim := false
 
-	for scanner.Scan() {
-		line := scanner.Text()
-
+	for i, line := range lines {
 		if line == "---" {
 			if !foundFirstDelim {
 				foundFirstDelim = true
 				inFrontmatter = true
 				continue
 			} else if inFrontmatter {
-				inFrontmatter = false
-				frontmatterDone = true
-				continue
+				return strings.Join(frontmatterLines, "\n"), strings.Join(lines[i+1:], "\n"), i + 1
 			}
 		}
 
 		if inFrontmatter {
 			frontmatterLines = append(frontmatterLines, line)
-		} else if frontmatterDone {
-			bodyLines = append(bodyLines, line)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
+	return strings.Join(frontmatterLines, "\n"), "", len(lines)
+}
+
+// NoNewlineSeparator separates two tickets like a "---" line, and records
+// that the ticket before it has no final newline. The newline ending that
+// ticket in the stream belongs to the separator.
+const NoNewlineSeparator = "--- no newline at end of ticket"
+
+// SplitDocuments splits a stream of concatenated ticket files into one
+// string per ticket, each exactly as it appeared in the stream. A "---" line
+// after a ticket's frontmatter starts the next ticket when it opens
+// frontmatter holding an id; a "---" line directly before such an opening is
+// a separator and is dropped. A NoNewlineSeparator line in the same place is
+// dropped too, along with the newline ending the ticket before it. Any other
+// "---" is a horizontal rule in the body. Text before the first frontmatter
+// is ignored.
+func SplitDocuments(content string) []string {
+	raw := strings.SplitAfter(content, "\n")
+	if raw[len(raw)-1] == "" {
+		raw = raw[:len(raw)-1]
+	}
+	lines := make([]string, len(raw))
+	for i, line := range raw {
+		lines[i] = strings.TrimRight(line, "\r\n")
+	}
+
+	var docs []string
+	start := -1
+	inFrontmatter := false
+	flush := func(end int) {
+		if start >= 0 {
+			docs = append(docs, strings.Join(raw[start:end], ""))
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		if lines[i] != "---" && lines[i] != NoNewlineSeparator {
+			continue
+		}
+		switch {
+		case lines[i] == NoNewlineSeparator:
+			if start >= 0 && !inFrontmatter && opensTicket(lines, i+1) {
+				flush(i)
+				docs[len(docs)-1] = strings.TrimSuffix(docs[len(docs)-1], "\n")
+				start = -1
+			}
+		case inFrontmatter:
+			inFrontmatter = false
+		case opensTicket(lines, i):
+			flush(i)
+			start, inFrontmatter = i, true
+		case start >= 0 && opensTicket(lines, i+1):
+			flush(i)
+			start = -1
+		}
+	}
+	flush(len(lines))
+	return docs
+}
+
+// opensTicket reports whether line i is a "---" opening frontmatter that is
+// closed by a later "---" and sets an id
+func opensTicket(lines []string, i int) bool {
+	if i >= len(lines) || lines[i] != "---" {
+		return false
+	}
+	hasID := false
+	for _, line := range lines[i+1:] {
+		if line == "---" {
+			return hasID
+		}
+		hasID = hasID || strings.HasPrefix(line, "id:")
+	}
+	return false
+}
+
+// splitLines splits content into lines, dropping the final newline and
+// trailing carriage returns
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// Parse reads a ticket from a reader and returns the parsed Ticket
+func Parse(r io.Reader) (*Ticket, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
 		return nil, fmt.Errorf("reading ticket: %w", err)
 	}
 
+	yamlContent, bodyContent, _ := SplitFrontmatter(string(content))
+
 	// Parse YAML frontmatter
 	var fm frontmatter
-	yamlContent := strings.Join(frontmatterLines, "\n")
 	if err := yaml.Unmarshal([]byte(yamlContent), &fm); err != nil {
 		return nil, fmt.Errorf("parsing frontmatter: %w", err)
 	}
 
-	// Parse created time
-	var created time.Time
-	if fm.Created != "" {
-		var err error
-		created, err = time.Parse(time.RFC3339, fm.Created)
-		if err != nil {
-			// Try alternate formats
-			created, err = time.Parse("2006-01-02T15:04:05Z", fm.Created)
-			if err != nil {
-				created = time.Time{}
-			}
-		}
-	}
+	created := parseTimestamp(fm.Created)
 
-	// Extract title from first # heading
-	title := ""
-	bodyStart := 0
-	for i, line := range bodyLines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "# ") {
-			title = strings.TrimPrefix(trimmed, "# ")
-			bodyStart = i + 1
-			break
-		} else if trimmed != "" {
-			// Non-empty, non-heading line means no title
-			break
+	// Due dates are timestamps like created; bare dates are also accepted
+	due := parseTimestamp(fm.Due)
+	if due.IsZero() && fm.Due != "" {
+		if d, err := time.Parse(DueLayout, fm.Due); err == nil {
+			due = d
 		}
 	}
 
-	// Rest is body
-	body := ""
-	if bodyStart < len(bodyLines) {
-		body = strings.TrimSpace(strings.Join(bodyLines[bodyStart:], "\n"))
-	}
+	// Older tickets may give the estimate as a duration or fractional hours;
+	// those are rounded up rather than failing the whole ticket
+	estimate, _, _ := ParseEstimate(fm.Estimate)
+
+	title, body := SplitTitle(bodyContent)
 
 	// Ensure deps and links are non-nil
 	deps := fm.Deps
@@ -119,17 +295,68 @@ func Parse(r io.Reader) (*Ticket, error) {
 		Status:      fm.Status,
 		Deps:        deps,
 		Links:       links,
+		Tags:        fm.Tags,
 		Created:     created,
 		Type:        fm.Type,
 		Priority:    fm.Priority,
 		Assignee:    fm.Assignee,
 		ExternalRef: fm.ExternalRef,
 		Parent:      fm.Parent,
+		CreatedBy:   fm.CreatedBy,
+		Estimate:    estimate,
+		Due:         due,
+		Extra:       extraKeys(yamlContent),
 		Title:       title,
 		Body:        body,
 	}, nil
 }
 
+// parseTimestamp parses a frontmatter timestamp, returning the zero time if
+// the value is empty or malformed
+func parseTimestamp(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// Try alternate formats
+		ts, err = time.Parse("2006-01-02T15:04:05Z", value)
+		if err != nil {
+			return time.Time{}
+		}
+	}
+	return ts
+}
+
+// SplitTitle extracts the title from the first "# " heading of a markdown
+// document and returns it with the trimmed remainder. The heading must come
+// before any other non-blank line; otherwise the title is empty.
+func SplitTitle(markdown string) (title, body string) {
+	if markdown == "" {
+		return "", ""
+	}
+	lines := strings.Split(markdown, "\n")
+
+	bodyStart := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "# ") {
+			title = strings.TrimPrefix(trimmed, "# ")
+			bodyStart = i + 1
+			break
+		} else if trimmed != "" {
+			// Non-empty, non-heading line means no title
+			break
+		}
+	}
+
+	// Rest is body
+	if bodyStart < len(lines) {
+		body = strings.TrimSpace(strings.Join(lines[bodyStart:], "\n"))
+	}
+	return title, body
+}
+
 // Format writes a ticket to a writer in the standard markdown format
 func Format(w io.Writer, t *Ticket) error {
 	var buf bytes.Buffer
@@ -141,6 +368,9 @@ func Format(w io.Writer, t *Ticket) error {
 	buf.WriteString(fmt.Sprintf("status: %s\n", t.Status))
 	buf.WriteString(fmt.Sprintf("deps: %s\n", formatArray(t.Deps)))
 	buf.WriteString(fmt.Sprintf("links: %s\n", formatArray(t.Links)))
+	if len(t.Tags) > 0 {
+		buf.WriteString(fmt.Sprintf("tags: %s\n", formatArray(t.Tags)))
+	}
 	buf.WriteString(fmt.Sprintf("created: %s\n", t.Created.UTC().Format(time.RFC3339)))
 	buf.WriteString(fmt.Sprintf("type: %s\n", t.Type))
 	buf.WriteString(fmt.Sprintf("priority: %d\n", t.Priority))
@@ -153,6 +383,18 @@ func Format(w io.Writer, t *Ticket) error {
 	if t.Parent != "" {
 		buf.WriteString(fmt.Sprintf("parent: %s\n", t.Parent))
 	}
+	if t.CreatedBy != "" {
+		buf.WriteString(fmt.Sprintf("created-by: %s\n", t.CreatedBy))
+	}
+	if t.Estimate != 0 {
+		buf.WriteString(fmt.Sprintf("estimate: %d\n", t.Estimate))
+	}
+	if !t.Due.IsZero() {
+		buf.WriteString(fmt.Sprintf("due: %s\n", t.Due.UTC().Format(time.RFC3339)))
+	}
+	for _, k := range SortedExtraKeys(t.Extra) {
+		buf.WriteString(fmt.Sprintf("%s: %s\n", k, formatScalar(t.Extra[k])))
+	}
 
 	buf.WriteString("---\n")
 	buf.WriteString(fmt.Sprintf("# %s\n", t.Title))
@@ -201,3 +443,33 @@ func UpdateField(content, field, value string) string {
 	}
 	return strings.Join(result, "\n")
 }
+
+// UpdateTitle replaces the title heading in ticket file content, preserving
+// the rest of the file. A heading is inserted if the body has none.
+func UpdateTitle(content, title string) string {
+	_, _, bodyStart := SplitFrontmatter(content)
+	lines := strings.Split(content, "\n")
+	if bodyStart > len(lines) {
+		bodyStart = len(lines)
+	}
+
+	heading := "# " + title
+	for i := bodyStart; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "# ") {
+			lines[i] = heading
+			return strings.Join(lines, "\n")
+		} else if trimmed != "" {
+			break
+		}
+	}
+
+	lines = append(lines[:bodyStart], append([]string{heading}, lines[bodyStart:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+// RemoveField removes a field line from the frontmatter, if present
+func RemoveField(content, field string) string {
+	pattern := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(field) + `:.*\n?`)
+	return pattern.ReplaceAllString(content, "")
+}
@@ -2,6 +2,7 @@ package ticket
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -488,6 +489,261 @@ external-ref: EXT-123
 	}
 }
 
+// TestCreatedByRoundTrip tests that the created-by field survives parse/format
+func TestCreatedByRoundTrip(t *testing.T) {
+	content := `---
+id: test-1234
+status: open
+deps: []
+links: []
+created: 2025-01-11T10:00:00Z
+type: task
+priority: 2
+assignee: bob
+created-by: alice
+---
+# Test
+`
+	parsed, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed.CreatedBy != "alice" {
+		t.Errorf("CreatedBy = %q, want %q", parsed.CreatedBy, "alice")
+	}
+
+	var buf bytes.Buffer
+	if err := Format(&buf, parsed); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if buf.String() != content {
+		t.Errorf("round trip mismatch\ngot:\n%s\nwant:\n%s", buf.String(), content)
+	}
+
+	// Omitted when empty
+	parsed.CreatedBy = ""
+	buf.Reset()
+	Format(&buf, parsed)
+	if strings.Contains(buf.String(), "created-by") {
+		t.Error("created-by should be omitted when empty")
+	}
+}
+
+// TestEstimateRoundTrip tests that the estimate survives Parse, Format and
+// Parse again, and is omitted when zero
+func TestEstimateRoundTrip(t *testing.T) {
+	content := `---
+id: test-1234
+status: open
+deps: []
+links: []
+created: 2025-01-11T10:00:00Z
+type: task
+priority: 2
+estimate: 5
+---
+# Test
+`
+	parsed, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed.Estimate != 5 {
+		t.Errorf("Estimate = %d, want 5", parsed.Estimate)
+	}
+	if _, ok := parsed.Extra["estimate"]; ok {
+		t.Error("estimate should be a known field, not an extra key")
+	}
+
+	var buf bytes.Buffer
+	if err := Format(&buf, parsed); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if buf.String() != content {
+		t.Errorf("round trip mismatch\ngot:\n%s\nwant:\n%s", buf.String(), content)
+	}
+	reparsed, err := Parse(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Parse of formatted ticket failed: %v", err)
+	}
+	if reparsed.Estimate != 5 {
+		t.Errorf("Estimate after round trip = %d, want 5", reparsed.Estimate)
+	}
+
+	// Omitted when zero
+	parsed.Estimate = 0
+	buf.Reset()
+	Format(&buf, parsed)
+	if strings.Contains(buf.String(), "estimate") {
+		t.Error("estimate should be omitted when zero")
+	}
+}
+
+// TestLegacyEstimate tests estimates written before they were whole hours
+func TestLegacyEstimate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+		valid bool
+	}{
+		{"90m", 2, true},
+		{"1.5", 2, true},
+		{"2h", 2, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			content := "---\nid: test-1234\nstatus: open\ndeps: []\nlinks: []\ncreated: 2025-01-11T10:00:00Z\ntype: task\npriority: 2\nestimate: " + tt.value + "\n---\n# Test\n"
+
+			parsed, err := Parse(strings.NewReader(content))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if parsed.Estimate != tt.want {
+				t.Errorf("Estimate = %d, want %d", parsed.Estimate, tt.want)
+			}
+
+			// Validation flags the value rather than rewriting it
+			_, err = Canonicalize(content)
+			if err == nil {
+				t.Fatal("Canonicalize should reject an estimate that isn't whole hours")
+			}
+			if tt.valid && !strings.Contains(err.Error(), fmt.Sprintf("read as %d", tt.want)) {
+				t.Errorf("error = %v, want it to say how the value is read", err)
+			}
+		})
+	}
+}
+
+// TestSplitFrontmatter tests the frontmatter/body boundary detection
+func TestSplitFrontmatter(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantFM     string
+		wantBody   string
+		wantOffset int
+	}{
+		{
+			name:       "minimal frontmatter",
+			content:    "---\nid: a-1\n---\n# Title\n",
+			wantFM:     "id: a-1",
+			wantBody:   "# Title",
+			wantOffset: 3,
+		},
+		{
+			name:       "larger frontmatter",
+			content:    "---\nid: a-1\nstatus: open\ndeps: []\nlinks: []\ntype: task\n---\n# Title\n\nBody\n",
+			wantFM:     "id: a-1\nstatus: open\ndeps: []\nlinks: []\ntype: task",
+			wantBody:   "# Title\n\nBody",
+			wantOffset: 7,
+		},
+		{
+			name:       "horizontal rule in body stays in body",
+			content:    "---\nid: a-1\n---\n# Title\n---\nAfter rule\n",
+			wantFM:     "id: a-1",
+			wantBody:   "# Title\n---\nAfter rule",
+			wantOffset: 3,
+		},
+		{
+			name:       "CRLF line endings",
+			content:    "---\r\nid: a-1\r\n---\r\n# Title\r\n",
+			wantFM:     "id: a-1",
+			wantBody:   "# Title",
+			wantOffset: 3,
+		},
+		{
+			name:       "unclosed frontmatter",
+			content:    "---\nid: a-1\n# Title\n",
+			wantFM:     "id: a-1\n# Title",
+			wantBody:   "",
+			wantOffset: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, offset := SplitFrontmatter(tt.content)
+			if fm != tt.wantFM {
+				t.Errorf("frontmatter = %q, want %q", fm, tt.wantFM)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if offset != tt.wantOffset {
+				t.Errorf("offset = %d, want %d", offset, tt.wantOffset)
+			}
+		})
+	}
+}
+
+// TestSplitDocuments tests splitting concatenated ticket files
+func TestSplitDocuments(t *testing.T) {
+	one := "---\nid: a-1\n---\n# One\n"
+	two := "---\nid: b-2\nstatus: open\n---\n# Two\n\nBody\n"
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "single ticket",
+			content: one,
+			want:    []string{one},
+		},
+		{
+			name:    "concatenated tickets",
+			content: one + two,
+			want:    []string{one, two},
+		},
+		{
+			name:    "separator line between tickets",
+			content: one + "---\n" + two,
+			want:    []string{one, two},
+		},
+		{
+			name:    "horizontal rule in body stays in body",
+			content: "---\nid: a-1\n---\n# One\n---\nAfter rule\n" + two,
+			want:    []string{"---\nid: a-1\n---\n# One\n---\nAfter rule\n", two},
+		},
+		{
+			name:    "CRLF line endings are kept",
+			content: strings.ReplaceAll(one+two, "\n", "\r\n"),
+			want:    []string{strings.ReplaceAll(one, "\n", "\r\n"), strings.ReplaceAll(two, "\n", "\r\n")},
+		},
+		{
+			name:    "missing final newline is kept",
+			content: one + strings.TrimSuffix(two, "\n"),
+			want:    []string{one, strings.TrimSuffix(two, "\n")},
+		},
+		{
+			name:    "separator for a ticket without final newline",
+			content: one + "Last line\n" + NoNewlineSeparator + "\n" + two,
+			want:    []string{one + "Last line", two},
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Just a title\n",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitDocuments(tt.content)
+			if len(got) != len(tt.want) {
+				t.Fatalf("SplitDocuments() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("document %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 // TestInvalidFrontmatter tests error handling for invalid input
 func TestInvalidFrontmatter(t *testing.T) {
 	tests := []struct {
@@ -726,3 +982,169 @@ This is the body.
 		t.Errorf("Round-trip Title mismatch")
 	}
 }
+
+func TestTagsRoundTrip(t *testing.T) {
+	original := `---
+id: test-1234
+status: open
+deps: []
+links: []
+tags: [backend, urgent]
+created: 2025-01-11T10:00:00Z
+type: task
+priority: 2
+---
+# Tagged
+`
+
+	ticket, err := Parse(strings.NewReader(original))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(ticket.Tags) != 2 || ticket.Tags[0] != "backend" || ticket.Tags[1] != "urgent" {
+		t.Errorf("Tags = %v, want [backend urgent]", ticket.Tags)
+	}
+	if _, ok := ticket.Extra["tags"]; ok {
+		t.Error("tags should not be kept as an extra key")
+	}
+
+	var buf bytes.Buffer
+	if err := Format(&buf, ticket); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if buf.String() != original {
+		t.Errorf("Round trip changed the file:\n%s", buf.String())
+	}
+
+	ticket.Tags = nil
+	buf.Reset()
+	if err := Format(&buf, ticket); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "tags:") {
+		t.Errorf("Untagged ticket should omit tags, got:\n%s", buf.String())
+	}
+}
+
+func TestSplitTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		markdown  string
+		wantTitle string
+		wantBody  string
+	}{
+		{"title and body", "# Title\n\nBody text\n", "Title", "Body text"},
+		{"leading blank lines", "\n\n# Title\nBody", "Title", "Body"},
+		{"no heading", "Just text\n# Later", "", "Just text\n# Later"},
+		{"title only", "# Title\n\n   \n", "Title", ""},
+		{"empty", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := SplitTitle(tt.markdown)
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("SplitTitle() = (%q, %q), want (%q, %q)", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestExtraKeysRoundTrip(t *testing.T) {
+	content := `---
+id: test-1234
+status: open
+deps: []
+links: []
+created: 2025-01-11T10:00:00Z
+type: task
+priority: 2
+component: 'api: v2'
+sprint: s1
+---
+# Test
+`
+	parsed, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed.Extra["sprint"] != "s1" || parsed.Extra["component"] != "api: v2" {
+		t.Errorf("Extra = %v", parsed.Extra)
+	}
+
+	var buf bytes.Buffer
+	if err := Format(&buf, parsed); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if buf.String() != content {
+		t.Errorf("round trip mismatch\ngot:\n%s\nwant:\n%s", buf.String(), content)
+	}
+
+	t.Run("non-scalar values are skipped", func(t *testing.T) {
+		withList := strings.Replace(content, "sprint: s1\n", "sprint: s1\nlabels: [a, b]\n", 1)
+		parsed, err := Parse(strings.NewReader(withList))
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if _, ok := parsed.Extra["labels"]; ok {
+			t.Errorf("non-scalar key should not be in Extra: %v", parsed.Extra)
+		}
+	})
+}
+
+func TestCanonicalize(t *testing.T) {
+	t.Run("rejects content that would be lost", func(t *testing.T) {
+		tests := map[string]string{
+			"no id":          "---\nstatus: open\ncreated: 2026-01-01T00:00:00Z\n---\n# T\n",
+			"bad created":    "---\nid: a-1\ncreated: yesterday\n---\n# T\n",
+			"non-scalar key": "---\nid: a-1\ncreated: 2026-01-01T00:00:00Z\nlabels:\n  - x\n---\n# T\n",
+		}
+		for name, content := range tests {
+			if _, err := Canonicalize(content); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+
+	t.Run("canonical content is unchanged", func(t *testing.T) {
+		content := "---\nid: a-1\nstatus: open\ndeps: [b-2]\nlinks: []\ncreated: 2026-01-01T00:00:00Z\ntype: task\npriority: 2\n---\n# T\n\nBody\n"
+		got, err := Canonicalize(content)
+		if err != nil {
+			t.Fatalf("Canonicalize() error = %v", err)
+		}
+		if got != content {
+			t.Errorf("Canonicalize() =\n%s\nwant unchanged", got)
+		}
+	})
+}
+
+func TestUpdateTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "replaces heading",
+			content: "---\nid: a\n---\n# Old\n\nBody\n# Not a title\n",
+			want:    "---\nid: a\n---\n# New\n\nBody\n# Not a title\n",
+		},
+		{
+			name:    "skips blank lines before heading",
+			content: "---\nid: a\n---\n\n# Old\n",
+			want:    "---\nid: a\n---\n\n# New\n",
+		},
+		{
+			name:    "inserts missing heading",
+			content: "---\nid: a\n---\nBody\n",
+			want:    "---\nid: a\n---\n# New\nBody\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UpdateTitle(tt.content, "New"); got != tt.want {
+				t.Errorf("UpdateTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
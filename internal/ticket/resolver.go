@@ -2,8 +2,7 @@ package ticket
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -22,43 +21,37 @@ type ErrAmbiguous struct {
 	Matches []string
 }
 
+// Error lists the candidates so the user can pick a longer ID, e.g.
+// ambiguous id "12": abc-1234, def-1299
 func (e ErrAmbiguous) Error() string {
-	return fmt.Sprintf("ambiguous ID '%s' matches multiple tickets", e.ID)
+	return fmt.Sprintf("ambiguous id %q: %s", e.ID, strings.Join(e.Matches, ", "))
 }
 
-// ResolveID resolves a partial ID to a full ticket ID
-// It first tries exact match, then partial match
+// ResolveID resolves a partial ID to a full ticket ID in a flat tickets
+// directory. It first tries exact match, then partial match
 func ResolveID(ticketsDir, partial string) (string, error) {
-	// Try exact match first
-	exactPath := filepath.Join(ticketsDir, partial+".md")
-	if _, err := os.Stat(exactPath); err == nil {
-		return partial, nil
-	}
+	return NewFileStore(ticketsDir).ResolveID(partial)
+}
 
-	// Try partial match
-	entries, err := os.ReadDir(ticketsDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return "", ErrNotFound{ID: partial}
-		}
-		return "", fmt.Errorf("reading tickets directory: %w", err)
+// ResolveIDs resolves multiple partial IDs in a flat tickets directory
+func ResolveIDs(ticketsDir string, partials []string) ([]string, error) {
+	return NewFileStore(ticketsDir).ResolveIDs(partials)
+}
+
+// ResolveIn resolves a partial ID against already loaded tickets, such as
+// those from ListParsed, with the same rules as FileStore.ResolveID
+func ResolveIn(tickets map[string]*Ticket, partial string) (string, error) {
+	if _, ok := tickets[partial]; ok {
+		return partial, nil
 	}
 
 	var matches []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if !strings.HasSuffix(name, ".md") {
-			continue
-		}
-
-		id := strings.TrimSuffix(name, ".md")
+	for id := range tickets {
 		if strings.Contains(id, partial) {
 			matches = append(matches, id)
 		}
 	}
+	sort.Strings(matches)
 
 	switch len(matches) {
 	case 0:
@@ -70,15 +63,26 @@ func ResolveID(ticketsDir, partial string) (string, error) {
 	}
 }
 
-// ResolveIDs resolves multiple partial IDs
-func ResolveIDs(ticketsDir string, partials []string) ([]string, error) {
-	result := make([]string, len(partials))
-	for i, partial := range partials {
-		id, err := ResolveID(ticketsDir, partial)
-		if err != nil {
-			return nil, err
+// ShortIDs maps each ID to its shortest suffix that resolves unambiguously
+// with ResolveID, i.e. that no other ID contains
+func ShortIDs(ids []string) map[string]string {
+	short := make(map[string]string, len(ids))
+	for _, id := range ids {
+		short[id] = id
+		for n := 1; n < len(id); n++ {
+			suffix := id[len(id)-n:]
+			unique := true
+			for _, other := range ids {
+				if other != id && strings.Contains(other, suffix) {
+					unique = false
+					break
+				}
+			}
+			if unique {
+				short[id] = suffix
+				break
+			}
 		}
-		result[i] = id
 	}
-	return result, nil
+	return short
 }
@@ -398,6 +398,9 @@ func TestErrAmbiguous_Error(t *testing.T) {
 	if !strings.Contains(msg, "ambiguous") {
 		t.Errorf("Error message should contain 'ambiguous': %s", msg)
 	}
+	if want := `ambiguous id "test": test-123, test-456`; msg != want {
+		t.Errorf("Error() = %q, want %q", msg, want)
+	}
 }
 
 // TestResolveID_SubstringMatch tests that substring matching works correctly
@@ -427,3 +430,70 @@ func TestResolveID_SubstringMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestShortIDs(t *testing.T) {
+	t.Run("distinct suffixes", func(t *testing.T) {
+		short := ShortIDs([]string{"tk-a1b2", "tk-c3d4"})
+		if short["tk-a1b2"] != "2" || short["tk-c3d4"] != "4" {
+			t.Errorf("ShortIDs() = %v, want single-character suffixes", short)
+		}
+	})
+
+	t.Run("collision lengthens", func(t *testing.T) {
+		short := ShortIDs([]string{"tk-a1b2", "tk-c3b2", "tk-x9z8"})
+		if short["tk-a1b2"] != "1b2" || short["tk-c3b2"] != "3b2" {
+			t.Errorf("ShortIDs() = %v, want 1b2 and 3b2", short)
+		}
+		if short["tk-x9z8"] != "8" {
+			t.Errorf("ShortIDs()[tk-x9z8] = %q, want 8", short["tk-x9z8"])
+		}
+	})
+
+	t.Run("suffix must not appear elsewhere in another ID", func(t *testing.T) {
+		// "2" appears inside tk-2abc, so tk-xyz2 needs a longer suffix
+		short := ShortIDs([]string{"tk-xyz2", "tk-2abc"})
+		if short["tk-xyz2"] != "z2" {
+			t.Errorf("ShortIDs()[tk-xyz2] = %q, want z2", short["tk-xyz2"])
+		}
+	})
+}
+
+func TestResolveIn(t *testing.T) {
+	tickets := map[string]*Ticket{
+		"abc-1234": {ID: "abc-1234"},
+		"abc-5678": {ID: "abc-5678"},
+		"xyz-9999": {ID: "xyz-9999"},
+	}
+
+	tests := []struct {
+		partial string
+		want    string
+		wantErr error
+	}{
+		{"abc-1234", "abc-1234", nil},
+		{"9999", "xyz-9999", nil},
+		{"nope", "", ErrNotFound{}},
+		{"abc", "", ErrAmbiguous{}},
+	}
+	for _, tt := range tests {
+		got, err := ResolveIn(tickets, tt.partial)
+		switch tt.wantErr.(type) {
+		case nil:
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveIn(%q) = %q, %v; want %q", tt.partial, got, err, tt.want)
+			}
+		case ErrNotFound:
+			var notFound ErrNotFound
+			if !errors.As(err, &notFound) {
+				t.Errorf("ResolveIn(%q) error = %v, want ErrNotFound", tt.partial, err)
+			}
+		case ErrAmbiguous:
+			var ambiguous ErrAmbiguous
+			if !errors.As(err, &ambiguous) {
+				t.Errorf("ResolveIn(%q) error = %v, want ErrAmbiguous", tt.partial, err)
+			} else if strings.Join(ambiguous.Matches, ",") != "abc-1234,abc-5678" {
+				t.Errorf("ResolveIn(%q) matches = %v, want sorted abc IDs", tt.partial, ambiguous.Matches)
+			}
+		}
+	}
+}
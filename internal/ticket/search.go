@@ -0,0 +1,109 @@
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SearchOptions controls how Search matches ticket files
+type SearchOptions struct {
+	CaseSensitive bool // Match case exactly
+	Regex         bool // Treat the pattern as a Go regular expression
+	HeadersOnly   bool // Search only the frontmatter and title heading
+}
+
+// SearchResult is a line of a ticket file matching a search
+type SearchResult struct {
+	ID         string
+	Line       string // The matching line, trimmed of surrounding whitespace
+	LineNumber int    // 1-based line number in the ticket file
+}
+
+// Search returns every line of the ticket files matching the pattern, one
+// line at a time, ordered by ticket ID then line number. Frontmatter lines
+// match on their values only, so searching for a key name such as deps
+// doesn't match every ticket, and the title matches on its text. Unreadable
+// files are skipped, as in Walk.
+func (s *FileStore) Search(pattern string, opts SearchOptions) ([]SearchResult, error) {
+	match, err := lineMatcher(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	visit := func(id, content string) error {
+		lines := strings.Split(content, "\n")
+		for i, text := range searchText(lines) {
+			if line := lines[i]; match(text) {
+				results = append(results, SearchResult{ID: id, Line: strings.TrimSpace(line), LineNumber: i + 1})
+			}
+		}
+		return nil
+	}
+
+	if opts.HeadersOnly {
+		err = s.WalkHeaders(visit)
+	} else {
+		err = s.Walk(visit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// searchText returns the text of each line that a search matches against:
+// the value of a frontmatter field or list item, the title without its #
+// marker, and body lines whole. Frontmatter delimiters match nothing.
+func searchText(lines []string) []string {
+	texts := make([]string, len(lines))
+	inFrontmatter, seenTitle := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "---" && i == 0:
+			inFrontmatter = true
+		case trimmed == "---" && inFrontmatter:
+			inFrontmatter = false
+		case inFrontmatter:
+			if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+				texts[i] = item
+			} else if _, value, ok := strings.Cut(trimmed, ":"); ok {
+				texts[i] = strings.TrimSpace(value)
+			}
+		case !seenTitle && strings.HasPrefix(trimmed, "# "):
+			texts[i] = strings.TrimPrefix(trimmed, "# ")
+			seenTitle = true
+		default:
+			texts[i] = line
+		}
+	}
+	return texts
+}
+
+// lineMatcher builds the line predicate for a search pattern
+func lineMatcher(pattern string, opts SearchOptions) (func(line string) bool, error) {
+	if opts.Regex {
+		expr := pattern
+		if !opts.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		return re.MatchString, nil
+	}
+
+	if opts.CaseSensitive {
+		return func(line string) bool { return strings.Contains(line, pattern) }, nil
+	}
+	needle := strings.ToLower(pattern)
+	return func(line string) bool { return strings.Contains(strings.ToLower(line), needle) }, nil
+}
@@ -0,0 +1,101 @@
+package ticket
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFileStore_Search tests the Search method
+func TestFileStore_Search(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	widget := createTestTicket("search-b")
+	widget.Title = "Fix Widget rendering"
+	widget.Body = "The widget crashes on startup.\n\nAnother widget line."
+	other := createTestTicket("search-a")
+	other.Title = "Timer drift"
+	other.Body = "Requests time out after 30s."
+	for _, tk := range []*Ticket{widget, other} {
+		if err := store.Create(tk); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	t.Run("case-insensitive by default", func(t *testing.T) {
+		results, err := store.Search("WIDGET", SearchOptions{})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("Search() = %v, want the title and two body lines", results)
+		}
+		for i, r := range results {
+			if r.ID != "search-b" {
+				t.Errorf("result %d ID = %s, want search-b", i, r.ID)
+			}
+			if i > 0 && r.LineNumber <= results[i-1].LineNumber {
+				t.Errorf("results should be in line order: %v", results)
+			}
+		}
+		if results[0].Line != "# Fix Widget rendering" {
+			t.Errorf("first match = %q, want the title heading", results[0].Line)
+		}
+
+		_, content, _ := store.ReadRaw("search-b")
+		lines := strings.Split(content, "\n")
+		if got := strings.TrimSpace(lines[results[1].LineNumber-1]); got != results[1].Line {
+			t.Errorf("LineNumber %d points at %q, want %q", results[1].LineNumber, got, results[1].Line)
+		}
+	})
+
+	t.Run("case sensitive", func(t *testing.T) {
+		results, err := store.Search("Widget", SearchOptions{CaseSensitive: true})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != 1 || results[0].Line != "# Fix Widget rendering" {
+			t.Errorf("Search() = %v, want only the title", results)
+		}
+	})
+
+	t.Run("regex", func(t *testing.T) {
+		results, err := store.Search(`time ?out|crash`, SearchOptions{Regex: true})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != 2 || results[0].ID != "search-a" || results[1].ID != "search-b" {
+			t.Errorf("Search() = %v, want one match in each ticket, ordered by ID", results)
+		}
+
+		if _, err := store.Search("(unclosed", SearchOptions{Regex: true}); err == nil {
+			t.Error("Search() should reject an invalid regex")
+		}
+	})
+
+	t.Run("key names don't match", func(t *testing.T) {
+		for _, key := range []string{"deps", "status", "priority:"} {
+			results, err := store.Search(key, SearchOptions{})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(results) != 0 {
+				t.Errorf("Search(%q) = %v, want no matches", key, results)
+			}
+		}
+
+		results, _ := store.Search("open", SearchOptions{HeadersOnly: true})
+		if len(results) != 2 || results[0].Line != "status: open" {
+			t.Errorf("Search() = %v, want the status value of both tickets", results)
+		}
+	})
+
+	t.Run("headers only", func(t *testing.T) {
+		results, err := store.Search("widget", SearchOptions{HeadersOnly: true})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != 1 {
+			t.Errorf("Search() = %v, want only the title", results)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package ticket
+
+import "strings"
+
+// Names of the body sections maintained by tk
+const (
+	NotesSection      = "Notes"
+	WorklogSection    = "Worklog"
+	HistorySection    = "History"
+	ResolutionSection = "Resolution"
+)
+
+// GetSection returns the content of the "## name" section of a markdown
+// body, without surrounding blank lines. The section runs until the next
+// "## " or "# " heading; deeper headings belong to it.
+func GetSection(body, name string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	heading, end, ok := sectionBounds(lines, name)
+	if !ok {
+		return "", false
+	}
+	return strings.Join(trimBlankLines(lines[heading+1:end]), "\n"), true
+}
+
+// UpsertSection replaces the content of the "## name" section of a markdown
+// body, or appends the section if the body has none. Other sections keep
+// their order. A trailing newline on the body is preserved.
+func UpsertSection(body, name, content string) string {
+	trailing := strings.HasSuffix(body, "\n")
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	section := append([]string{"## " + name, ""}, strings.Split(strings.Trim(content, "\n"), "\n")...)
+
+	heading, end, ok := sectionBounds(lines, name)
+	if ok {
+		rest := lines[end:]
+		if len(rest) > 0 {
+			section = append(section, "")
+		}
+		lines = append(append(lines[:heading:heading], section...), rest...)
+	} else {
+		lines = trimBlankLines(lines)
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, section...)
+	}
+
+	result := strings.Join(lines, "\n")
+	if trailing {
+		result += "\n"
+	}
+	return result
+}
+
+// AppendToSection adds a line at the end of the "## name" section of a
+// markdown body, creating the section if needed
+func AppendToSection(body, name, line string) string {
+	if content, ok := GetSection(body, name); ok && content != "" {
+		return UpsertSection(body, name, content+"\n"+line)
+	}
+	return UpsertSection(body, name, line)
+}
+
+// UpdateBody rewrites the markdown after the frontmatter of raw ticket file
+// content, leaving the frontmatter untouched
+func UpdateBody(content string, fn func(body string) string) string {
+	_, _, bodyStart := SplitFrontmatter(content)
+	lines := strings.SplitAfter(content, "\n")
+	if bodyStart > len(lines) {
+		bodyStart = len(lines)
+	}
+	head := strings.Join(lines[:bodyStart], "")
+	return head + fn(strings.Join(lines[bodyStart:], ""))
+}
+
+// GetSection returns the content of a section of the ticket's body
+func (t *Ticket) GetSection(name string) (string, bool) {
+	return GetSection(t.Body, name)
+}
+
+// UpsertSection replaces or adds a section of the ticket's body
+func (t *Ticket) UpsertSection(name, content string) {
+	t.Body = UpsertSection(t.Body, name, content)
+}
+
+// AppendToSection adds a line to a section of the ticket's body
+func (t *Ticket) AppendToSection(name, line string) {
+	t.Body = AppendToSection(t.Body, name, line)
+}
+
+// sectionBounds finds the heading line of the "## name" section and the
+// index of the line ending it
+func sectionBounds(lines []string, name string) (heading, end int, ok bool) {
+	want := "## " + name
+	heading = -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if heading < 0 {
+			if trimmed == want {
+				heading = i
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") || strings.HasPrefix(trimmed, "# ") {
+			return heading, i, true
+		}
+	}
+	if heading < 0 {
+		return 0, 0, false
+	}
+	return heading, len(lines), true
+}
+
+// trimBlankLines drops leading and trailing blank lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
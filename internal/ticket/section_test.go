@@ -0,0 +1,119 @@
+package ticket
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSections(t *testing.T) {
+	body := `Intro paragraph.
+
+## Notes
+
+First note
+
+## Worklog
+
+- 1h setup
+
+### Details
+
+Nested heading stays in Worklog
+
+## History
+
+- 2026-01-02T03:04:05Z open -> in_progress
+`
+
+	t.Run("get", func(t *testing.T) {
+		tests := []struct {
+			name string
+			want string
+			ok   bool
+		}{
+			{NotesSection, "First note", true},
+			{WorklogSection, "- 1h setup\n\n### Details\n\nNested heading stays in Worklog", true},
+			{HistorySection, "- 2026-01-02T03:04:05Z open -> in_progress", true},
+			{ResolutionSection, "", false},
+		}
+		for _, tt := range tests {
+			got, ok := GetSection(body, tt.name)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("GetSection(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+			}
+		}
+	})
+
+	t.Run("upsert and append keep order", func(t *testing.T) {
+		updated := UpsertSection(body, NotesSection, "Replaced note")
+		updated = AppendToSection(updated, WorklogSection, "- 30m review")
+		updated = AppendToSection(updated, HistorySection, "- 2026-01-03T00:00:00Z in_progress -> closed")
+		updated = UpsertSection(updated, ResolutionSection, "Fixed upstream.")
+
+		want := `Intro paragraph.
+
+## Notes
+
+Replaced note
+
+## Worklog
+
+- 1h setup
+
+### Details
+
+Nested heading stays in Worklog
+- 30m review
+
+## History
+
+- 2026-01-02T03:04:05Z open -> in_progress
+- 2026-01-03T00:00:00Z in_progress -> closed
+
+## Resolution
+
+Fixed upstream.
+`
+		if updated != want {
+			t.Errorf("unexpected body:\n%s\nwant:\n%s", updated, want)
+		}
+
+		// Reading back gives what was written
+		if got, _ := GetSection(updated, ResolutionSection); got != "Fixed upstream." {
+			t.Errorf("GetSection(Resolution) = %q", got)
+		}
+		if got, _ := GetSection(updated, NotesSection); got != "Replaced note" {
+			t.Errorf("GetSection(Notes) = %q", got)
+		}
+	})
+
+	t.Run("append creates a missing section", func(t *testing.T) {
+		got := AppendToSection("", NotesSection, "Only note")
+		if got != "## Notes\n\nOnly note" {
+			t.Errorf("AppendToSection() on empty body = %q", got)
+		}
+	})
+
+	t.Run("ticket methods", func(t *testing.T) {
+		tk := &Ticket{Body: "Description"}
+		tk.AppendToSection(NotesSection, "one")
+		tk.AppendToSection(NotesSection, "two")
+		if got, ok := tk.GetSection(NotesSection); !ok || got != "one\ntwo" {
+			t.Errorf("GetSection() = %q, %v", got, ok)
+		}
+		if !strings.HasPrefix(tk.Body, "Description\n\n## Notes") {
+			t.Errorf("unexpected body: %q", tk.Body)
+		}
+	})
+}
+
+func TestUpdateBody(t *testing.T) {
+	content := "---\nid: a-1\nstatus: open\n---\n# Title\n\nBody\n"
+	got := UpdateBody(content, func(body string) string {
+		return AppendToSection(body, NotesSection, "note")
+	})
+	want := "---\nid: a-1\nstatus: open\n---\n# Title\n\nBody\n\n## Notes\n\nnote\n"
+	if got != want {
+		t.Errorf("UpdateBody() = %q, want %q", got, want)
+	}
+}
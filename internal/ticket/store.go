@@ -1,27 +1,100 @@
 package ticket
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
-// Store defines the interface for ticket storage operations
+// Store defines the interface for ticket storage operations. Methods taking
+// a partial ID resolve it like ResolveID.
 type Store interface {
+	Dir() string
+	EnsureDir() error
 	Create(t *Ticket) error
-	Get(id string) (*Ticket, error)
+	Get(partial string) (*Ticket, error)
 	List() ([]*Ticket, error)
+	ListParsed() (map[string]*Ticket, error)
+	ListByModTime(limit int) ([]*Ticket, error)
+	ModTimes() (map[string]time.Time, error)
+	FileStats() (map[string]FileStat, error)
 	Update(t *Ticket) error
-	Delete(id string) error
-	Path(id string) (string, error)
-	Dir() string
+	UpdateField(partial, field, value string) (string, error)
+	UpdateFields(partial string, fields map[string]string) (string, error)
+	ReadRaw(partial string) (string, string, error)
+	WriteRaw(id, content string) error
+	AppendToFile(partial, content string) (string, error)
+	FileContains(partial, search string) (bool, string, error)
+	Search(pattern string, opts SearchOptions) ([]SearchResult, error)
+	Path(partial string) (string, error)
+	Delete(partial string) error
+	ResolveID(partial string) (string, error)
+	ResolveIDs(partials []string) ([]string, error)
+	Walk(fn func(id, content string) error) error
+	WalkHeaders(fn func(id, header string) error) error
+}
+
+// Archiver is implemented by stores that can move tickets out of sight
+// without deleting them
+type Archiver interface {
+	Archive(partial string) (string, error)
+}
+
+// Trasher is implemented by stores that can keep deleted tickets for
+// restoring later
+type Trasher interface {
+	Trash(partial string, at time.Time) (string, error)
+	ListTrash() ([]TrashEntry, error)
+	Restore(entry TrashEntry) error
+	EmptyTrash() (int, error)
+}
+
+// Indexer is implemented by stores that can keep an index of ticket IDs to
+// speed up partial-ID resolution
+type Indexer interface {
+	Reindex() (int, error)
+}
+
+// UnreadableReporter is implemented by stores that skip ticket files they
+// cannot read, such as files without read permission, and can report them
+type UnreadableReporter interface {
+	Unreadable() []UnreadableFile
+}
+
+var (
+	_ Store              = (*FileStore)(nil)
+	_ Archiver           = (*FileStore)(nil)
+	_ Trasher            = (*FileStore)(nil)
+	_ Indexer            = (*FileStore)(nil)
+	_ UnreadableReporter = (*FileStore)(nil)
+)
+
+// UnreadableFile is a ticket file that could not be read, with the reason
+type UnreadableFile struct {
+	Path string
+	Err  error
+}
+
+func (u UnreadableFile) Error() string {
+	return fmt.Sprintf("%s: %v", u.Path, u.Err)
 }
 
 // FileStore implements Store using the filesystem
 type FileStore struct {
 	dir string
+	// sharded keeps each ticket in a subdirectory named after its prefix
+	sharded bool
+
+	mu sync.Mutex
+	// unreadable records the files skipped by List and Walk, by path
+	unreadable map[string]error
 }
 
 // NewFileStore creates a new FileStore with the given directory
@@ -29,6 +102,100 @@ func NewFileStore(dir string) *FileStore {
 	return &FileStore{dir: dir}
 }
 
+// NewShardedFileStore creates a FileStore that keeps each ticket in a
+// subdirectory named after its ID prefix, e.g. ab/ab-1234.md. Tickets left
+// at the top level from before sharding are still found.
+func NewShardedFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir, sharded: true}
+}
+
+// Sharded reports whether tickets are kept in per-prefix subdirectories
+func (s *FileStore) Sharded() bool {
+	return s.sharded
+}
+
+// ShardDir returns the subdirectory holding a ticket in a sharded store:
+// the ID prefix before the final dash, or "_" for IDs without one
+func ShardDir(id string) string {
+	if i := strings.LastIndex(id, "-"); i > 0 {
+		return id[:i]
+	}
+	return "_"
+}
+
+// newPath returns where a new ticket file with the given ID is written
+func (s *FileStore) newPath(id string) string {
+	if s.sharded {
+		return filepath.Join(s.dir, ShardDir(id), id+".md")
+	}
+	return filepath.Join(s.dir, id+".md")
+}
+
+// path returns the file path of an existing ticket. A sharded store falls
+// back to the top level for tickets created before sharding was enabled.
+func (s *FileStore) path(id string) string {
+	path := s.newPath(id)
+	if s.sharded {
+		if _, err := os.Stat(path); err != nil {
+			flat := filepath.Join(s.dir, id+".md")
+			if _, err := os.Stat(flat); err == nil {
+				return flat
+			}
+		}
+	}
+	return path
+}
+
+// ResolveID resolves a partial ID to a full ticket ID, searching shard
+// subdirectories when sharded. It first tries exact match, then partial
+// match, taking the ticket IDs from the index when it is up to date
+func (s *FileStore) ResolveID(partial string) (string, error) {
+	if _, err := os.Stat(s.path(partial)); err == nil {
+		return partial, nil
+	}
+
+	ids, ok := s.indexedIDs()
+	if !ok {
+		files, err := s.files()
+		if err != nil {
+			return "", err
+		}
+		ids = make([]string, len(files))
+		for i, file := range files {
+			ids[i] = file.id
+		}
+	}
+
+	var matches []string
+	for _, id := range ids {
+		if strings.Contains(id, partial) {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", ErrNotFound{ID: partial}
+	case 1:
+		return matches[0], nil
+	default:
+		return "", ErrAmbiguous{ID: partial, Matches: matches}
+	}
+}
+
+// ResolveIDs resolves multiple partial IDs
+func (s *FileStore) ResolveIDs(partials []string) ([]string, error) {
+	result := make([]string, len(partials))
+	for i, partial := range partials {
+		id, err := s.ResolveID(partial)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = id
+	}
+	return result, nil
+}
+
 // Dir returns the tickets directory
 func (s *FileStore) Dir() string {
 	return s.dir
@@ -45,7 +212,10 @@ func (s *FileStore) Create(t *Ticket) error {
 		return fmt.Errorf("creating tickets directory: %w", err)
 	}
 
-	path := filepath.Join(s.dir, t.ID+".md")
+	path := s.newPath(t.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating shard directory: %w", err)
+	}
 	f, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("creating ticket file: %w", err)
@@ -56,65 +226,180 @@ func (s *FileStore) Create(t *Ticket) error {
 		return fmt.Errorf("writing ticket: %w", err)
 	}
 
+	s.syncIndex()
 	return nil
 }
 
+// CreateNew saves a new ticket in s and returns its ID, as both the new
+// command and the library API do. When t.ID is empty a fresh ID is
+// generated, prefixed from the name of dir; otherwise no ticket may already
+// have that ID. A zero Created is set to now, an empty Status to open, an
+// empty Type to task and nil Deps and Links to empty lists.
+func CreateNew(s Store, t *Ticket, dir string) (string, error) {
+	if t.ID == "" {
+		id, err := newID(s, dir)
+		if err != nil {
+			return "", err
+		}
+		t.ID = id
+	} else if hasID(s, t.ID) {
+		return "", fmt.Errorf("ticket %s already exists", t.ID)
+	}
+
+	if t.Created.IsZero() {
+		t.Created = time.Now().UTC()
+	}
+	if t.Status == "" {
+		t.Status = StatusOpen
+	}
+	if t.Type == "" {
+		t.Type = TypeTask
+	}
+	if t.Deps == nil {
+		t.Deps = []string{}
+	}
+	if t.Links == nil {
+		t.Links = []string{}
+	}
+
+	if err := s.Create(t); err != nil {
+		return "", err
+	}
+	return t.ID, nil
+}
+
+// newID generates an ID prefixed from the name of dir that no ticket in s
+// uses, retrying on collisions
+func newID(s Store, dir string) (string, error) {
+	const maxRetries = 10
+	for i := 0; i < maxRetries; i++ {
+		id := GenerateID(dir)
+		if !hasID(s, id) {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate unique ticket ID after %d attempts", maxRetries)
+}
+
+// hasID reports whether a ticket in s has exactly this ID
+func hasID(s Store, id string) bool {
+	resolved, err := s.ResolveID(id)
+	return err == nil && resolved == id
+}
+
 // Get retrieves a ticket by ID (supports partial matching)
 func (s *FileStore) Get(partial string) (*Ticket, error) {
-	id, err := ResolveID(s.dir, partial)
+	id, err := s.ResolveID(partial)
 	if err != nil {
 		return nil, err
 	}
 
-	path := filepath.Join(s.dir, id+".md")
+	path := s.path(id)
 	return s.readTicket(path)
 }
 
 // Path returns the file path for a ticket (supports partial matching)
 func (s *FileStore) Path(partial string) (string, error) {
-	id, err := ResolveID(s.dir, partial)
+	id, err := s.ResolveID(partial)
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(s.dir, id+".md"), nil
+	return s.path(id), nil
 }
 
-// List returns all tickets
+// List returns all tickets. Malformed tickets are skipped, as are files that
+// cannot be read; the latter are recorded for Unreadable.
 func (s *FileStore) List() ([]*Ticket, error) {
-	entries, err := os.ReadDir(s.dir)
+	tickets, _, err := s.ListResilient()
+	return tickets, err
+}
+
+// ListParsed reads and parses every ticket file once and returns the
+// tickets keyed by ID, so that graph-walking callers can look tickets up
+// without going back to disk. Files are skipped as in List.
+func (s *FileStore) ListParsed() (map[string]*Ticket, error) {
+	tickets, err := s.List()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("reading tickets directory: %w", err)
+		return nil, err
 	}
 
-	var tickets []*Ticket
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
-			continue
-		}
+	byID := make(map[string]*Ticket, len(tickets))
+	for _, t := range tickets {
+		byID[t.ID] = t
+	}
+	return byID, nil
+}
 
-		path := filepath.Join(s.dir, entry.Name())
-		t, err := s.readTicket(path)
+// ListResilient returns all readable tickets along with the ticket files
+// that could not be read, e.g. for lack of permission. Malformed tickets are
+// skipped as in List.
+func (s *FileStore) ListResilient() ([]*Ticket, []UnreadableFile, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tickets []*Ticket
+	var unreadable []UnreadableFile
+	for _, file := range files {
+		t, err := s.readTicket(file.path)
 		if err != nil {
+			if reason, ok := readFailure(err); ok {
+				s.noteUnreadable(file.path, reason)
+				unreadable = append(unreadable, UnreadableFile{Path: file.path, Err: reason})
+			}
 			// Skip malformed tickets
 			continue
 		}
 		tickets = append(tickets, t)
 	}
 
-	return tickets, nil
+	return tickets, unreadable, nil
+}
+
+// Unreadable returns the ticket files skipped so far because they could not
+// be read, sorted by path
+func (s *FileStore) Unreadable() []UnreadableFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var files []UnreadableFile
+	for path, reason := range s.unreadable {
+		files = append(files, UnreadableFile{Path: path, Err: reason})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// noteUnreadable records a ticket file that could not be read
+func (s *FileStore) noteUnreadable(path string, reason error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.unreadable == nil {
+		s.unreadable = make(map[string]error)
+	}
+	s.unreadable[path] = reason
+}
+
+// readFailure reports whether err is a failure to open or read a file, as
+// opposed to a malformed ticket, and returns the underlying reason (such as
+// "permission denied")
+func readFailure(err error) (error, bool) {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) {
+		return nil, false
+	}
+	return pathErr.Err, true
 }
 
 // ListByModTime returns tickets sorted by modification time (most recent first)
 func (s *FileStore) ListByModTime(limit int) ([]*Ticket, error) {
-	entries, err := os.ReadDir(s.dir)
+	entries, err := s.files()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("reading tickets directory: %w", err)
+		return nil, err
 	}
 
 	type fileInfo struct {
@@ -124,15 +409,11 @@ func (s *FileStore) ListByModTime(limit int) ([]*Ticket, error) {
 
 	var files []fileInfo
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
-			continue
-		}
-		path := filepath.Join(s.dir, entry.Name())
-		info, err := entry.Info()
+		info, err := entry.entry.Info()
 		if err != nil {
 			continue
 		}
-		files = append(files, fileInfo{path: path, modTime: info.ModTime().UnixNano()})
+		files = append(files, fileInfo{path: entry.path, modTime: info.ModTime().UnixNano()})
 	}
 
 	// Sort by modTime descending
@@ -155,9 +436,62 @@ func (s *FileStore) ListByModTime(limit int) ([]*Ticket, error) {
 	return tickets, nil
 }
 
+// ModTimes returns the modification time of every ticket file keyed by ID
+func (s *FileStore) ModTimes() (map[string]time.Time, error) {
+	stats, err := s.FileStats()
+	if err != nil {
+		return nil, err
+	}
+
+	modTimes := make(map[string]time.Time, len(stats))
+	for id, stat := range stats {
+		modTimes[id] = stat.ModTime
+	}
+	return modTimes, nil
+}
+
+// FileStat is the modification time and size of a ticket file
+type FileStat struct {
+	ModTime time.Time `json:"mtime"`
+	Size    int64     `json:"size"`
+}
+
+// FileStats returns the modification time and size of every ticket file
+// keyed by ID
+func (s *FileStore) FileStats() (map[string]FileStat, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]FileStat)
+	for _, file := range files {
+		info, err := file.entry.Info()
+		if err != nil {
+			continue
+		}
+		stats[file.id] = FileStat{ModTime: info.ModTime(), Size: info.Size()}
+	}
+	return stats, nil
+}
+
+// SetModTime resolves a (partial) ticket ID and sets its file's access and
+// modification times
+func (s *FileStore) SetModTime(id string, t time.Time) error {
+	resolved, err := s.ResolveID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chtimes(s.path(resolved), t, t); err != nil {
+		return fmt.Errorf("setting modification time: %w", err)
+	}
+	return nil
+}
+
 // Update updates an existing ticket
 func (s *FileStore) Update(t *Ticket) error {
-	path := filepath.Join(s.dir, t.ID+".md")
+	path := s.path(t.ID)
 
 	// Write to temp file first for atomicity
 	tmpPath := path + ".tmp"
@@ -183,17 +517,18 @@ func (s *FileStore) Update(t *Ticket) error {
 		return fmt.Errorf("renaming temp file: %w", err)
 	}
 
+	s.syncIndex()
 	return nil
 }
 
 // UpdateField updates a single field in a ticket file, preserving original formatting
 func (s *FileStore) UpdateField(partial, field, value string) (string, error) {
-	id, err := ResolveID(s.dir, partial)
+	id, err := s.ResolveID(partial)
 	if err != nil {
 		return "", err
 	}
 
-	path := filepath.Join(s.dir, id+".md")
+	path := s.path(id)
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return "", fmt.Errorf("reading ticket: %w", err)
@@ -213,24 +548,311 @@ func (s *FileStore) UpdateField(partial, field, value string) (string, error) {
 		return "", fmt.Errorf("renaming temp file: %w", err)
 	}
 
+	s.syncIndex()
+	return id, nil
+}
+
+// UpdateFields updates several fields in a ticket file in one write,
+// preserving original formatting. An empty value removes the field.
+func (s *FileStore) UpdateFields(partial string, fields map[string]string) (string, error) {
+	id, content, err := s.ReadRaw(partial)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if fields[name] == "" {
+			content = RemoveField(content, name)
+		} else {
+			content = UpdateField(content, name, fields[name])
+		}
+	}
+
+	if err := s.WriteRaw(id, content); err != nil {
+		return "", err
+	}
 	return id, nil
 }
 
 // Delete removes a ticket
 func (s *FileStore) Delete(partial string) error {
-	id, err := ResolveID(s.dir, partial)
+	id, err := s.ResolveID(partial)
 	if err != nil {
 		return err
 	}
 
-	path := filepath.Join(s.dir, id+".md")
+	path := s.path(id)
 	if err := os.Remove(path); err != nil {
 		return fmt.Errorf("deleting ticket: %w", err)
 	}
 
+	s.syncIndex()
+	return nil
+}
+
+// Archive moves a ticket into the archive subdirectory, hiding it from List.
+// It fails if a ticket with the same ID was already archived.
+func (s *FileStore) Archive(partial string) (string, error) {
+	id, err := s.ResolveID(partial)
+	if err != nil {
+		return "", err
+	}
+
+	archiveDir := filepath.Join(s.dir, ArchiveDir)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return "", fmt.Errorf("creating archive directory: %w", err)
+	}
+
+	// A ticket archived earlier under the same ID must not be overwritten
+	src := s.path(id)
+	dst := filepath.Join(archiveDir, id+".md")
+	if _, err := os.Lstat(dst); err == nil {
+		return "", fmt.Errorf("ticket %s is already in the archive", id)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return "", fmt.Errorf("archiving ticket: %w", err)
+	}
+
+	s.syncIndex()
+	return id, nil
+}
+
+// trashTimeFormat names trashed files so they sort by deletion time
+const trashTimeFormat = "20060102T150405.000000000"
+
+// TrashEntry is a deleted ticket file held in the trash
+type TrashEntry struct {
+	ID      string
+	Path    string
+	Trashed time.Time
+}
+
+// Trash moves a ticket into the trash subdirectory, stamped with the
+// deletion time. Tickets trashed together share a time so they can be
+// restored together.
+func (s *FileStore) Trash(partial string, at time.Time) (string, error) {
+	id, err := s.ResolveID(partial)
+	if err != nil {
+		return "", err
+	}
+
+	trashDir := filepath.Join(s.dir, TrashDir)
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return "", fmt.Errorf("creating trash directory: %w", err)
+	}
+
+	name := at.UTC().Format(trashTimeFormat) + "_" + id + ".md"
+	if err := os.Rename(s.path(id), filepath.Join(trashDir, name)); err != nil {
+		return "", fmt.Errorf("trashing ticket: %w", err)
+	}
+
+	s.syncIndex()
+	return id, nil
+}
+
+// ListTrash returns the trashed tickets, most recently deleted first
+func (s *FileStore) ListTrash() ([]TrashEntry, error) {
+	trashDir := filepath.Join(s.dir, TrashDir)
+	entries, err := os.ReadDir(trashDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading trash directory: %w", err)
+	}
+
+	var trash []TrashEntry
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".md")
+		stamp, id, ok := strings.Cut(name, "_")
+		if entry.IsDir() || name == entry.Name() || !ok {
+			continue
+		}
+		at, err := time.Parse(trashTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		trash = append(trash, TrashEntry{ID: id, Path: filepath.Join(trashDir, entry.Name()), Trashed: at})
+	}
+
+	sort.SliceStable(trash, func(i, j int) bool {
+		return trash[i].Trashed.After(trash[j].Trashed)
+	})
+	return trash, nil
+}
+
+// Restore moves a trashed ticket back into the store. It refuses to
+// overwrite a ticket that has since been created with the same ID.
+func (s *FileStore) Restore(entry TrashEntry) error {
+	if _, err := os.Stat(s.path(entry.ID)); err == nil {
+		return fmt.Errorf("cannot restore %s: a ticket with that ID exists", entry.ID)
+	}
+
+	path := s.newPath(entry.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating shard directory: %w", err)
+	}
+	if err := os.Rename(entry.Path, path); err != nil {
+		return fmt.Errorf("restoring ticket: %w", err)
+	}
+	s.syncIndex()
 	return nil
 }
 
+// EmptyTrash permanently removes every trashed ticket and returns how many
+// were removed
+func (s *FileStore) EmptyTrash() (int, error) {
+	trash, err := s.ListTrash()
+	if err != nil {
+		return 0, err
+	}
+	for i, entry := range trash {
+		if err := os.Remove(entry.Path); err != nil {
+			return i, fmt.Errorf("emptying trash: %w", err)
+		}
+	}
+	return len(trash), nil
+}
+
+// Walk calls fn with the ID and full content of every ticket file.
+// Iteration stops at the first error returned by fn.
+func (s *FileStore) Walk(fn func(id, content string) error) error {
+	return s.walkFiles(func(id, path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// Skip unreadable tickets
+			if reason, ok := readFailure(err); ok {
+				s.noteUnreadable(path, reason)
+			}
+			return nil
+		}
+		return fn(id, string(data))
+	})
+}
+
+// WalkHeaders calls fn with the ID and header of every ticket file: the
+// frontmatter and title heading, without reading the rest of the body.
+func (s *FileStore) WalkHeaders(fn func(id, header string) error) error {
+	return s.walkFiles(func(id, path string) error {
+		header, err := readHeader(path)
+		if err != nil {
+			// Skip unreadable tickets
+			if reason, ok := readFailure(err); ok {
+				s.noteUnreadable(path, reason)
+			}
+			return nil
+		}
+		return fn(id, header)
+	})
+}
+
+// walkFiles calls fn for each ticket file in the directory
+func (s *FileStore) walkFiles(fn func(id, path string) error) error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := fn(file.id, file.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ticketFile is a ticket file found in the tickets directory
+type ticketFile struct {
+	id    string
+	path  string
+	entry os.DirEntry
+}
+
+// files lists the ticket files in the store, descending into shard
+// subdirectories when sharded. A missing directory yields no files.
+func (s *FileStore) files() ([]ticketFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading tickets directory: %w", err)
+	}
+
+	var files []ticketFile
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			if strings.HasSuffix(entry.Name(), ".md") {
+				files = append(files, ticketFile{
+					id:    strings.TrimSuffix(entry.Name(), ".md"),
+					path:  filepath.Join(s.dir, entry.Name()),
+					entry: entry,
+				})
+			}
+			continue
+		}
+
+		// Hidden directories such as the archive are never shards
+		if !s.sharded || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		shardPath := filepath.Join(s.dir, entry.Name())
+		shard, err := os.ReadDir(shardPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading shard directory: %w", err)
+		}
+		for _, e := range shard {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+				continue
+			}
+			files = append(files, ticketFile{
+				id:    strings.TrimSuffix(e.Name(), ".md"),
+				path:  filepath.Join(shardPath, e.Name()),
+				entry: e,
+			})
+		}
+	}
+	return files, nil
+}
+
+// readHeader reads a ticket file up to and including its title heading
+func readHeader(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var header strings.Builder
+	delimiters := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		header.WriteString(line)
+		header.WriteString("\n")
+
+		trimmed := strings.TrimSpace(line)
+		if delimiters < 2 {
+			if trimmed == "---" {
+				delimiters++
+			}
+			continue
+		}
+		// The title is the first non-blank line after the frontmatter
+		if trimmed != "" {
+			break
+		}
+	}
+
+	return header.String(), scanner.Err()
+}
+
 // readTicket reads and parses a ticket from a file path
 func (s *FileStore) readTicket(path string) (*Ticket, error) {
 	f, err := os.Open(path)
@@ -244,12 +866,12 @@ func (s *FileStore) readTicket(path string) (*Ticket, error) {
 
 // ReadRaw reads the raw content of a ticket file
 func (s *FileStore) ReadRaw(partial string) (string, string, error) {
-	id, err := ResolveID(s.dir, partial)
+	id, err := s.ResolveID(partial)
 	if err != nil {
 		return "", "", err
 	}
 
-	path := filepath.Join(s.dir, id+".md")
+	path := s.path(id)
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return "", "", fmt.Errorf("reading ticket: %w", err)
@@ -258,9 +880,21 @@ func (s *FileStore) ReadRaw(partial string) (string, string, error) {
 	return id, string(content), nil
 }
 
+// RawLines returns the raw lines of a ticket file along with the number of
+// lines preceding the body, so body line numbers can be reported in file terms
+func (s *FileStore) RawLines(partial string) (string, []string, int, error) {
+	id, content, err := s.ReadRaw(partial)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	_, _, bodyOffset := SplitFrontmatter(content)
+	return id, splitLines(content), bodyOffset, nil
+}
+
 // WriteRaw writes raw content to a ticket file
 func (s *FileStore) WriteRaw(id, content string) error {
-	path := filepath.Join(s.dir, id+".md")
+	path := s.path(id)
 
 	// Write to temp file first for atomicity
 	tmpPath := path + ".tmp"
@@ -274,17 +908,18 @@ func (s *FileStore) WriteRaw(id, content string) error {
 		return fmt.Errorf("renaming temp file: %w", err)
 	}
 
+	s.syncIndex()
 	return nil
 }
 
 // AppendToFile appends content to a ticket file
 func (s *FileStore) AppendToFile(partial, content string) (string, error) {
-	id, err := ResolveID(s.dir, partial)
+	id, err := s.ResolveID(partial)
 	if err != nil {
 		return "", err
 	}
 
-	path := filepath.Join(s.dir, id+".md")
+	path := s.path(id)
 	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return "", fmt.Errorf("opening ticket: %w", err)
@@ -309,12 +944,12 @@ func (s *FileStore) FileContains(partial, search string) (bool, string, error) {
 
 // GetRawContent gets the raw content and ID of a ticket
 func (s *FileStore) GetRawContent(partial string) (string, string, string, error) {
-	id, err := ResolveID(s.dir, partial)
+	id, err := s.ResolveID(partial)
 	if err != nil {
 		return "", "", "", err
 	}
 
-	path := filepath.Join(s.dir, id+".md")
+	path := s.path(id)
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return "", "", "", fmt.Errorf("reading ticket: %w", err)
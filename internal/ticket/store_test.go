@@ -1,8 +1,11 @@
 package ticket
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -32,6 +35,56 @@ func createTestTicket(id string) *Ticket {
 }
 
 // TestFileStore_Create tests the Create method
+// TestCreateNew tests the ID generation and defaults shared by the new
+// command and the library API
+func TestCreateNew(t *testing.T) {
+	t.Run("generates an ID and fills defaults", func(t *testing.T) {
+		store, _ := newTestStore(t)
+		tk := &Ticket{Title: "Fresh"}
+
+		id, err := CreateNew(store, tk, "/work/my-project")
+		if err != nil {
+			t.Fatalf("CreateNew() error = %v", err)
+		}
+		if !strings.HasPrefix(id, "mp-") || tk.ID != id {
+			t.Errorf("CreateNew() = %q, ticket ID %q; want an mp- prefix", id, tk.ID)
+		}
+
+		got, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got.Status != StatusOpen || got.Type != TypeTask || got.Created.IsZero() {
+			t.Errorf("defaults not applied: status %q, type %q, created %v", got.Status, got.Type, got.Created)
+		}
+		if got.Deps == nil || got.Links == nil {
+			t.Error("deps and links should be empty lists")
+		}
+	})
+
+	t.Run("keeps given fields", func(t *testing.T) {
+		store, _ := newTestStore(t)
+		tk := &Ticket{ID: "abc-1234", Status: StatusInProgress, Type: TypeBug, Title: "Given"}
+		if _, err := CreateNew(store, tk, "/work/x"); err != nil {
+			t.Fatalf("CreateNew() error = %v", err)
+		}
+		got, _ := store.Get("abc-1234")
+		if got.Status != StatusInProgress || got.Type != TypeBug {
+			t.Errorf("given fields overwritten: status %q, type %q", got.Status, got.Type)
+		}
+	})
+
+	t.Run("refuses an existing ID", func(t *testing.T) {
+		store, _ := newTestStore(t)
+		if err := store.Create(createTestTicket("abc-1234")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := CreateNew(store, &Ticket{ID: "abc-1234"}, "/work/x"); err == nil {
+			t.Error("CreateNew() should fail for an existing ID")
+		}
+	})
+}
+
 func TestFileStore_Create(t *testing.T) {
 	t.Run("create valid ticket", func(t *testing.T) {
 		store, dir := newTestStore(t)
@@ -319,6 +372,176 @@ func TestFileStore_List(t *testing.T) {
 	})
 }
 
+// TestFileStore_ListParsed tests loading every ticket keyed by ID
+func TestFileStore_ListParsed(t *testing.T) {
+	t.Run("keys tickets by ID", func(t *testing.T) {
+		store, _ := newTestStore(t)
+		for i := 1; i <= 3; i++ {
+			if err := store.Create(createTestTicket(sprintf("test-%d", i))); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		tickets, err := store.ListParsed()
+		if err != nil {
+			t.Fatalf("ListParsed() error = %v", err)
+		}
+		if len(tickets) != 3 {
+			t.Errorf("ListParsed() returned %d tickets, want 3", len(tickets))
+		}
+		for i := 1; i <= 3; i++ {
+			id := sprintf("test-%d", i)
+			if tk, ok := tickets[id]; !ok || tk.ID != id {
+				t.Errorf("ListParsed()[%q] = %v, want ticket %s", id, tk, id)
+			}
+		}
+	})
+
+	t.Run("missing directory returns empty map", func(t *testing.T) {
+		store, _ := newTestStore(t)
+
+		tickets, err := store.ListParsed()
+		if err != nil {
+			t.Fatalf("ListParsed() error = %v", err)
+		}
+		if len(tickets) != 0 {
+			t.Errorf("ListParsed() returned %d tickets, want 0", len(tickets))
+		}
+	})
+}
+
+// benchmarkStore creates a store holding n tickets, each depending on the
+// one before it
+func benchmarkStore(b *testing.B, n int) *FileStore {
+	b.Helper()
+	store := NewFileStore(filepath.Join(b.TempDir(), ".tickets"))
+	for i := 0; i < n; i++ {
+		tk := createTestTicket(sprintf("bench-%d", i))
+		if i > 0 {
+			tk.Deps = []string{sprintf("bench-%d", i-1)}
+		}
+		if err := store.Create(tk); err != nil {
+			b.Fatalf("Create() error = %v", err)
+		}
+	}
+	return store
+}
+
+// BenchmarkListThenGet looks up every dependency with Get after List, the
+// pattern ListParsed replaces
+func BenchmarkListThenGet(b *testing.B) {
+	store := benchmarkStore(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tickets, err := store.List()
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, t := range tickets {
+			for _, dep := range t.Deps {
+				if _, err := store.Get(dep); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkListParsed looks up every dependency in the map from ListParsed
+func BenchmarkListParsed(b *testing.B) {
+	store := benchmarkStore(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tickets, err := store.ListParsed()
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, t := range tickets {
+			for _, dep := range t.Deps {
+				if _, ok := tickets[dep]; !ok {
+					b.Fatalf("dependency %s not loaded", dep)
+				}
+			}
+		}
+	}
+}
+
+// TestFileStore_ListResilient tests that unreadable files are skipped and
+// reported rather than aborting the listing
+func TestFileStore_ListResilient(t *testing.T) {
+	t.Run("file without read permission", func(t *testing.T) {
+		store, dir := newTestStore(t)
+		for _, id := range []string{"ok-1", "ok-2", "locked-1"} {
+			if err := store.Create(createTestTicket(id)); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+		locked := filepath.Join(dir, "locked-1.md")
+		if err := os.Chmod(locked, 0); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Chmod(locked, 0644) })
+		if _, err := os.ReadFile(locked); err == nil {
+			t.Skip("file permissions are not enforced for this user")
+		}
+
+		tickets, unreadable, err := store.ListResilient()
+		if err != nil {
+			t.Fatalf("ListResilient() error = %v", err)
+		}
+		if len(tickets) != 2 {
+			t.Errorf("ListResilient() returned %d tickets, want 2", len(tickets))
+		}
+		if len(unreadable) != 1 || unreadable[0].Path != locked {
+			t.Fatalf("unreadable = %v, want %s", unreadable, locked)
+		}
+		if !errors.Is(unreadable[0].Err, fs.ErrPermission) {
+			t.Errorf("reason = %v, want permission denied", unreadable[0].Err)
+		}
+
+		// List skips the file too, and it stays reported
+		if tickets, err := store.List(); err != nil || len(tickets) != 2 {
+			t.Errorf("List() = %d tickets, %v; want 2", len(tickets), err)
+		}
+		if got := store.Unreadable(); len(got) != 1 || got[0].Path != locked {
+			t.Errorf("Unreadable() = %v, want %s", got, locked)
+		}
+	})
+
+	t.Run("dangling symlink", func(t *testing.T) {
+		store, dir := newTestStore(t)
+		if err := store.Create(createTestTicket("ok-1")); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		broken := filepath.Join(dir, "gone-1.md")
+		if err := os.Symlink(filepath.Join(dir, "missing"), broken); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+
+		tickets, unreadable, err := store.ListResilient()
+		if err != nil {
+			t.Fatalf("ListResilient() error = %v", err)
+		}
+		if len(tickets) != 1 || len(unreadable) != 1 || unreadable[0].Path != broken {
+			t.Errorf("ListResilient() = %d tickets, unreadable %v", len(tickets), unreadable)
+		}
+	})
+
+	t.Run("malformed tickets are not reported", func(t *testing.T) {
+		store, dir := newTestStore(t)
+		store.EnsureDir()
+		os.WriteFile(filepath.Join(dir, "bad-1.md"), []byte("---\nid: [\n---\n# Broken\n"), 0644)
+
+		_, unreadable, err := store.ListResilient()
+		if err != nil {
+			t.Fatalf("ListResilient() error = %v", err)
+		}
+		if len(unreadable) != 0 {
+			t.Errorf("unreadable = %v, want none", unreadable)
+		}
+	})
+}
+
 // TestFileStore_Update tests the Update method
 func TestFileStore_Update(t *testing.T) {
 	t.Run("update existing ticket", func(t *testing.T) {
@@ -440,7 +663,7 @@ func TestFileStore_Delete(t *testing.T) {
 // TestFileStore_ListByModTime tests the ListByModTime method
 func TestFileStore_ListByModTime(t *testing.T) {
 	t.Run("sorted by modification time descending", func(t *testing.T) {
-		store, dir := newTestStore(t)
+		store, _ := newTestStore(t)
 
 		// Create tickets with different mod times
 		ticket1 := createTestTicket("old-1111")
@@ -458,7 +681,7 @@ func TestFileStore_ListByModTime(t *testing.T) {
 
 		// Manually set older mod time on first ticket
 		oldTime := time.Now().Add(-1 * time.Hour)
-		os.Chtimes(filepath.Join(dir, "old-1111.md"), oldTime, oldTime)
+		store.SetModTime("old-1111", oldTime)
 
 		tickets, err := store.ListByModTime(10)
 		if err != nil {
@@ -539,6 +762,48 @@ func TestFileStore_ListByModTime(t *testing.T) {
 	})
 }
 
+// TestFileStore_ModTimes tests the ModTimes method
+func TestFileStore_ModTimes(t *testing.T) {
+	t.Run("returns mod time per ticket", func(t *testing.T) {
+		store, dir := newTestStore(t)
+
+		for _, id := range []string{"mt-1111", "mt-2222"} {
+			if err := store.Create(createTestTicket(id)); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+		os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644)
+
+		oldTime := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+		store.SetModTime("mt-1111", oldTime)
+
+		modTimes, err := store.ModTimes()
+		if err != nil {
+			t.Fatalf("ModTimes() error = %v", err)
+		}
+		if len(modTimes) != 2 {
+			t.Fatalf("ModTimes() returned %d entries, want 2", len(modTimes))
+		}
+		if !modTimes["mt-1111"].Equal(oldTime) {
+			t.Errorf("mt-1111 mod time = %v, want %v", modTimes["mt-1111"], oldTime)
+		}
+		if !modTimes["mt-2222"].After(oldTime) {
+			t.Errorf("mt-2222 mod time = %v, want after %v", modTimes["mt-2222"], oldTime)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		store := NewFileStore(filepath.Join(t.TempDir(), "missing"))
+		modTimes, err := store.ModTimes()
+		if err != nil {
+			t.Fatalf("ModTimes() error = %v", err)
+		}
+		if len(modTimes) != 0 {
+			t.Errorf("ModTimes() returned %d entries, want 0", len(modTimes))
+		}
+	})
+}
+
 // TestFileStore_Path tests the Path method
 func TestFileStore_Path(t *testing.T) {
 	t.Run("returns correct path for existing ticket", func(t *testing.T) {
@@ -769,6 +1034,41 @@ func TestFileStore_ReadRaw(t *testing.T) {
 	})
 }
 
+// TestFileStore_RawLines tests the RawLines method
+func TestFileStore_RawLines(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	tk := createTestTicket("raw-1234")
+	tk.Assignee = "someone"
+	tk.Body = "first body line\nsecond body line"
+	if err := store.Create(tk); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	id, lines, offset, err := store.RawLines("1234")
+	if err != nil {
+		t.Fatalf("RawLines() error = %v", err)
+	}
+	if id != "raw-1234" {
+		t.Errorf("id = %v, want raw-1234", id)
+	}
+
+	// Body starts right after the closing delimiter
+	if lines[offset-1] != "---" {
+		t.Errorf("line before body = %q, want ---", lines[offset-1])
+	}
+	if lines[offset] != "# "+tk.Title {
+		t.Errorf("first body line = %q, want title heading", lines[offset])
+	}
+	if got := lines[len(lines)-1]; got != "second body line" {
+		t.Errorf("last line = %q, want %q", got, "second body line")
+	}
+
+	if _, _, _, err := store.RawLines("nonexistent"); err == nil {
+		t.Error("RawLines() should error for missing ticket")
+	}
+}
+
 // TestFileStore_WriteRaw tests the WriteRaw method
 func TestFileStore_WriteRaw(t *testing.T) {
 	t.Run("write raw content", func(t *testing.T) {
@@ -1168,3 +1468,301 @@ func TestFileStore_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestFileStore_Archive(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	if err := store.Create(createTestTicket("arch-1234")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	id, err := store.Archive("1234")
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if id != "arch-1234" {
+		t.Errorf("id = %v, want arch-1234", id)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ArchiveDir, "arch-1234.md")); err != nil {
+		t.Errorf("archived file missing: %v", err)
+	}
+
+	tickets, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(tickets) != 0 {
+		t.Errorf("List() returned %d tickets, want 0", len(tickets))
+	}
+
+	// A new ticket reusing the ID must not replace the archived one
+	reused := createTestTicket("arch-1234")
+	reused.Title = "Reused"
+	if err := store.Create(reused); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := store.Archive("arch-1234"); err == nil {
+		t.Error("Archive() expected error when the ID is already archived")
+	}
+	archived, err := os.ReadFile(filepath.Join(dir, ArchiveDir, "arch-1234.md"))
+	if err != nil || strings.Contains(string(archived), "Reused") {
+		t.Errorf("archived file was replaced: %v", err)
+	}
+	if _, err := store.Get("arch-1234"); err != nil {
+		t.Errorf("ticket should stay in place: %v", err)
+	}
+}
+
+func TestFileStore_SetModTime(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	if err := store.Create(createTestTicket("touch-1234")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	want := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.SetModTime("1234", want); err != nil {
+		t.Fatalf("SetModTime() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "touch-1234.md"))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if !info.ModTime().Equal(want) {
+		t.Errorf("ModTime = %v, want %v", info.ModTime(), want)
+	}
+
+	if err := store.SetModTime("nope", want); err == nil {
+		t.Error("SetModTime() expected error for unknown ID")
+	}
+}
+
+func TestFileStore_WalkHeaders(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	tk := createTestTicket("hdr-1234")
+	tk.Body = "Body mentions secret"
+	if err := store.Create(tk); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var header string
+	if err := store.WalkHeaders(func(id, h string) error {
+		header = h
+		return nil
+	}); err != nil {
+		t.Fatalf("WalkHeaders() error = %v", err)
+	}
+	if !strings.Contains(header, "id: hdr-1234") || !strings.Contains(header, "# "+tk.Title) {
+		t.Errorf("header missing frontmatter or title: %q", header)
+	}
+	if strings.Contains(header, "secret") {
+		t.Errorf("header should not include the body: %q", header)
+	}
+
+	var content string
+	if err := store.Walk(func(id, c string) error {
+		content = c
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	if !strings.Contains(content, "secret") {
+		t.Errorf("Walk() content should include the body: %q", content)
+	}
+}
+
+func TestFileStore_UpdateFields(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	tk := createTestTicket("multi-1234")
+	tk.Assignee = "alice"
+	if err := store.Create(tk); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	id, err := store.UpdateFields("1234", map[string]string{"status": "closed", "assignee": ""})
+	if err != nil {
+		t.Fatalf("UpdateFields() error = %v", err)
+	}
+	if id != "multi-1234" {
+		t.Errorf("UpdateFields() returned id = %v, want multi-1234", id)
+	}
+
+	updated, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if updated.Status != StatusClosed {
+		t.Errorf("Status = %v, want %v", updated.Status, StatusClosed)
+	}
+	if updated.Assignee != "" {
+		t.Errorf("Assignee = %q, want it removed", updated.Assignee)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(dir, id+".md"))
+	if strings.Contains(string(content), "assignee:") {
+		t.Errorf("assignee line should be removed, got:\n%s", content)
+	}
+}
+
+// TestFileStore_Sharded tests that a sharded store keeps tickets under
+// per-prefix subdirectories and still resolves partial IDs across them
+func TestFileStore_Sharded(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".tickets")
+	store := NewShardedFileStore(dir)
+
+	for _, id := range []string{"ab-1234", "ab-5678", "cd-9abc"} {
+		if err := store.Create(createTestTicket(id)); err != nil {
+			t.Fatalf("Create(%s) error = %v", id, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "ab", "ab-1234.md")); err != nil {
+		t.Errorf("ticket not stored in shard: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ab-1234.md")); !os.IsNotExist(err) {
+		t.Errorf("ticket also stored at top level: %v", err)
+	}
+
+	got, err := store.Get("ab-1234")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.ID != "ab-1234" || got.Title != "Test Ticket" {
+		t.Errorf("Get() = %s %q", got.ID, got.Title)
+	}
+
+	tickets, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(tickets) != 3 {
+		t.Errorf("List() returned %d tickets, want 3", len(tickets))
+	}
+
+	// Partial IDs resolve across shards
+	if id, err := store.ResolveID("9ab"); err != nil || id != "cd-9abc" {
+		t.Errorf("ResolveID(9ab) = %q, %v", id, err)
+	}
+	if _, err := store.ResolveID("ab-"); err == nil {
+		t.Error("ResolveID(ab-) should be ambiguous")
+	}
+	path, err := store.Path("567")
+	if err != nil {
+		t.Fatalf("Path() error = %v", err)
+	}
+	if want := filepath.Join(dir, "ab", "ab-5678.md"); path != want {
+		t.Errorf("Path() = %s, want %s", path, want)
+	}
+
+	// Tickets written before sharding was enabled are still found
+	if err := NewFileStore(dir).Create(createTestTicket("ef-0000")); err != nil {
+		t.Fatalf("flat Create() error = %v", err)
+	}
+	if _, err := store.Get("ef-0"); err != nil {
+		t.Errorf("Get() of flat ticket error = %v", err)
+	}
+
+	if err := store.Delete("1234"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get("ab-1234"); err == nil {
+		t.Error("Get() after Delete() should fail")
+	}
+	tickets, err = store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(tickets) != 3 {
+		t.Errorf("List() after Delete() returned %d tickets, want 3", len(tickets))
+	}
+
+	// The archive is not mistaken for a shard
+	if _, err := store.Archive("cd-9abc"); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if _, err := store.Get("cd-9abc"); err == nil {
+		t.Error("Get() of archived ticket should fail")
+	}
+}
+
+func TestShardDir(t *testing.T) {
+	tests := map[string]string{
+		"ab-1234":     "ab",
+		"my-app-1234": "my-app",
+		"plain":       "_",
+	}
+	for id, want := range tests {
+		if got := ShardDir(id); got != want {
+			t.Errorf("ShardDir(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+// TestFileStore_Trash tests trashing, listing and restoring tickets
+func TestFileStore_Trash(t *testing.T) {
+	store, dir := newTestStore(t)
+	for _, id := range []string{"tr-1111", "tr-2222", "tr-3333"} {
+		if err := store.Create(createTestTicket(id)); err != nil {
+			t.Fatalf("Create(%s) error = %v", id, err)
+		}
+	}
+
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := store.Trash("1111", first); err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+	later := first.Add(time.Minute)
+	for _, id := range []string{"tr-2222", "tr-3333"} {
+		if _, err := store.Trash(id, later); err != nil {
+			t.Fatalf("Trash(%s) error = %v", id, err)
+		}
+	}
+
+	tickets, _ := store.List()
+	if len(tickets) != 0 {
+		t.Errorf("List() after Trash() returned %d tickets, want 0", len(tickets))
+	}
+
+	trash, err := store.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash() error = %v", err)
+	}
+	if len(trash) != 3 || !trash[0].Trashed.Equal(later) || trash[2].ID != "tr-1111" {
+		t.Fatalf("ListTrash() = %+v, want newest first", trash)
+	}
+
+	if err := store.Restore(trash[2]); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	tickets, _ = store.List()
+	if len(tickets) != 1 || tickets[0].ID != "tr-1111" {
+		t.Errorf("List() after Restore() = %v, want tr-1111", tickets)
+	}
+
+	// A ticket recreated with the same ID is not overwritten
+	if err := store.Create(createTestTicket("tr-2222")); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range trash {
+		if entry.ID == "tr-2222" {
+			if err := store.Restore(entry); err == nil {
+				t.Error("Restore() over an existing ticket should fail")
+			}
+		}
+	}
+
+	n, err := store.EmptyTrash()
+	if err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	if n == 0 {
+		t.Error("EmptyTrash() removed nothing")
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, TrashDir)); len(entries) != 0 {
+		t.Errorf("trash not empty: %d entries", len(entries))
+	}
+}
@@ -52,19 +52,31 @@ func (t Type) IsValid() bool {
 
 // Ticket represents a ticket with all its metadata and content
 type Ticket struct {
-	ID          string    `yaml:"id"`
-	Status      Status    `yaml:"status"`
-	Deps        []string  `yaml:"deps,flow"`
-	Links       []string  `yaml:"links,flow"`
-	Created     time.Time `yaml:"created"`
-	Type        Type      `yaml:"type"`
-	Priority    int       `yaml:"priority"`
-	Assignee    string    `yaml:"assignee,omitempty"`
-	ExternalRef string    `yaml:"external-ref,omitempty"`
-	Parent      string    `yaml:"parent,omitempty"`
-	Title       string    `yaml:"-"` // From # heading
-	Body        string    `yaml:"-"` // Markdown content after title
+	ID          string            `yaml:"id"`
+	Status      Status            `yaml:"status"`
+	Deps        []string          `yaml:"deps,flow"`
+	Links       []string          `yaml:"links,flow"`
+	Tags        []string          `yaml:"tags,flow,omitempty"`
+	Created     time.Time         `yaml:"created"`
+	Type        Type              `yaml:"type"`
+	Priority    int               `yaml:"priority"`
+	Assignee    string            `yaml:"assignee,omitempty"`
+	ExternalRef string            `yaml:"external-ref,omitempty"`
+	Parent      string            `yaml:"parent,omitempty"`
+	CreatedBy   string            `yaml:"created-by,omitempty"`
+	Estimate    int               `yaml:"estimate,omitempty"` // Story points or hours; zero when unset
+	Due         time.Time         `yaml:"-"`                  // Zero when the ticket has no due date
+	Extra       map[string]string `yaml:"-"`                  // Unknown scalar frontmatter keys
+	Title       string            `yaml:"-"`                  // From # heading
+	Body        string            `yaml:"-"`                  // Markdown content after title
 }
 
 // DefaultTicketsDir is the default directory for storing tickets
 const DefaultTicketsDir = ".tickets"
+
+// ArchiveDir is the subdirectory of the tickets directory holding archived tickets
+const ArchiveDir = ".archive"
+
+// TrashDir is the subdirectory of the tickets directory holding deleted
+// tickets when the trash is enabled
+const TrashDir = ".trash"
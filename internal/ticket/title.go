@@ -0,0 +1,15 @@
+package ticket
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeTitle reduces a title to a comparison key for spotting duplicates:
+// lowercased, with punctuation dropped and whitespace runs collapsed
+func NormalizeTitle(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,18 @@
+package ticket
+
+import "testing"
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := map[string]string{
+		"Fix login bug":        "fix login bug",
+		"  fix   LOGIN bug!  ": "fix login bug",
+		"Fix: login-bug.":      "fix login bug",
+		"Upgrade to v2.1":      "upgrade to v2 1",
+		"":                     "",
+	}
+	for in, want := range tests {
+		if got := NormalizeTitle(in); got != want {
+			t.Errorf("NormalizeTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
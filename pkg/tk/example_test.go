@@ -0,0 +1,60 @@
+package tk_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/lo5/tk/pkg/tk"
+)
+
+func Example() {
+	root, err := os.MkdirTemp("", "tk-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	store, err := tk.Open(filepath.Join(root, tk.DefaultDir))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	design, err := store.Create(&tk.Ticket{Title: "Write the design doc", Priority: 1})
+	if err != nil {
+		log.Fatal(err)
+	}
+	build, err := store.Create(&tk.Ticket{Title: "Build it", Deps: []string{design}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ready, err := store.Ready()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, t := range ready {
+		fmt.Println("ready:", t.Title)
+	}
+
+	// Closing the design unblocks the build
+	t, err := store.Get(design)
+	if err != nil {
+		log.Fatal(err)
+	}
+	t.Status = tk.StatusClosed
+	if err := store.Update(t); err != nil {
+		log.Fatal(err)
+	}
+
+	blockers, err := store.Blockers(build)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("blockers of build:", len(blockers))
+
+	// Output:
+	// ready: Write the design doc
+	// blockers of build: 0
+}
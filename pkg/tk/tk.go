@@ -0,0 +1,179 @@
+// Package tk is the Go API for tk ticket directories, for programs that
+// manage tickets without shelling out to the tk command. It shares its
+// implementation with the command, so both read and write the same files
+// and honor the same config (such as sharded storage).
+//
+// Functions that accept a ticket ID also accept a partial ID, resolved the
+// same way as on the command line; they fail with ErrNotFound or
+// ErrAmbiguous when it doesn't identify exactly one ticket.
+package tk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/lo5/tk/internal/config"
+	"github.com/lo5/tk/internal/deptree"
+	"github.com/lo5/tk/internal/ticket"
+)
+
+// Ticket is a ticket with its frontmatter, title and body
+type Ticket = ticket.Ticket
+
+// Status is a ticket status
+type Status = ticket.Status
+
+// Type is a ticket type
+type Type = ticket.Type
+
+// ErrNotFound reports an ID that matches no ticket
+type ErrNotFound = ticket.ErrNotFound
+
+// ErrAmbiguous reports a partial ID that matches several tickets
+type ErrAmbiguous = ticket.ErrAmbiguous
+
+// Ticket statuses
+const (
+	StatusOpen       = ticket.StatusOpen
+	StatusInProgress = ticket.StatusInProgress
+	StatusClosed     = ticket.StatusClosed
+)
+
+// Ticket types
+const (
+	TypeBug     = ticket.TypeBug
+	TypeFeature = ticket.TypeFeature
+	TypeTask    = ticket.TypeTask
+	TypeEpic    = ticket.TypeEpic
+	TypeChore   = ticket.TypeChore
+)
+
+// DefaultDir is the tickets directory the command uses by default
+const DefaultDir = ticket.DefaultTicketsDir
+
+// Store reads and writes the tickets in one tickets directory
+type Store struct {
+	files *ticket.FileStore
+}
+
+// Open returns a Store for a tickets directory, such as ".tickets". The
+// directory is created on the first Create if it doesn't exist.
+func Open(dir string) (*Store, error) {
+	files, err := config.OpenStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{files: files}, nil
+}
+
+// Dir returns the tickets directory
+func (s *Store) Dir() string {
+	return s.files.Dir()
+}
+
+// List returns every ticket. Files that fail to parse are skipped.
+func (s *Store) List() ([]*Ticket, error) {
+	return s.files.List()
+}
+
+// Get returns the ticket with the given (partial) ID
+func (s *Store) Get(id string) (*Ticket, error) {
+	return s.files.Get(id)
+}
+
+// Resolve returns the full ID of the ticket matching a partial ID
+func (s *Store) Resolve(partial string) (string, error) {
+	return s.files.ResolveID(partial)
+}
+
+// Create saves a new ticket and returns its ID. When t.ID is empty a fresh
+// ID is generated, prefixed from the name of the directory containing the
+// tickets directory. A zero Created is set to now, an empty Status to open
+// and an empty Type to task.
+func (s *Store) Create(t *Ticket) (string, error) {
+	abs, err := filepath.Abs(s.files.Dir())
+	if err != nil {
+		return "", fmt.Errorf("resolving tickets directory: %w", err)
+	}
+	return ticket.CreateNew(s.files, t, filepath.Dir(abs))
+}
+
+// Update rewrites an existing ticket from t, identified by t.ID
+func (s *Store) Update(t *Ticket) error {
+	if !s.exists(t.ID) {
+		return ErrNotFound{ID: t.ID}
+	}
+	return s.files.Update(t)
+}
+
+// exists reports whether a ticket has exactly this ID
+func (s *Store) exists(id string) bool {
+	resolved, err := s.files.ResolveID(id)
+	return err == nil && resolved == id
+}
+
+// Delete removes the ticket with the given (partial) ID. Unlike the rm
+// command it does not check or clean up references from other tickets.
+func (s *Store) Delete(id string) error {
+	return s.files.Delete(id)
+}
+
+// graph builds the dependency graph of every ticket
+func (s *Store) graph() (*deptree.DependencyGraph, error) {
+	tickets, err := s.files.List()
+	if err != nil {
+		return nil, err
+	}
+	return deptree.NewGraph(tickets), nil
+}
+
+// Ready returns the open or in-progress tickets whose dependencies are all
+// closed
+func (s *Store) Ready() ([]*Ticket, error) {
+	g, err := s.graph()
+	if err != nil {
+		return nil, err
+	}
+	return g.Ready(), nil
+}
+
+// Blockers returns the IDs of the unclosed or missing dependencies of a
+// ticket
+func (s *Store) Blockers(id string) ([]string, error) {
+	g, resolved, err := s.graphFor(id)
+	if err != nil {
+		return nil, err
+	}
+	return g.Blockers(resolved), nil
+}
+
+// Dependants returns the tickets that list a ticket as a dependency
+func (s *Store) Dependants(id string) ([]*Ticket, error) {
+	g, resolved, err := s.graphFor(id)
+	if err != nil {
+		return nil, err
+	}
+	return g.Dependants(resolved), nil
+}
+
+// Children returns the tickets whose parent is a ticket
+func (s *Store) Children(id string) ([]*Ticket, error) {
+	g, resolved, err := s.graphFor(id)
+	if err != nil {
+		return nil, err
+	}
+	return g.Children(resolved), nil
+}
+
+// graphFor resolves a (partial) ID and builds the dependency graph
+func (s *Store) graphFor(partial string) (*deptree.DependencyGraph, string, error) {
+	id, err := s.files.ResolveID(partial)
+	if err != nil {
+		return nil, "", err
+	}
+	g, err := s.graph()
+	if err != nil {
+		return nil, "", err
+	}
+	return g, id, nil
+}
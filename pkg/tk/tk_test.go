@@ -0,0 +1,50 @@
+package tk
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), DefaultDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	epic, err := store.Create(&Ticket{Title: "Epic", Type: TypeEpic})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	child, err := store.Create(&Ticket{ID: "x-0001", Title: "Child", Parent: epic})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := store.Create(&Ticket{ID: "x-0001", Title: "Again"}); err == nil {
+		t.Error("Create() with an existing ID should fail")
+	}
+	// An ID contained in another ticket's ID is not a collision
+	if _, err := store.Create(&Ticket{ID: "x-000", Title: "Prefix"}); err != nil {
+		t.Errorf("Create(x-000) error = %v", err)
+	}
+
+	got, err := store.Get("0001")
+	if err != nil || got.Title != "Child" || got.Status != StatusOpen || got.Type != TypeTask {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+	children, err := store.Children(epic)
+	if err != nil || len(children) != 1 || children[0].ID != child {
+		t.Errorf("Children() = %v, %v", children, err)
+	}
+
+	if err := store.Update(&Ticket{ID: "missing", Title: "Nope"}); !errors.As(err, &ErrNotFound{}) {
+		t.Errorf("Update() of a missing ticket error = %v", err)
+	}
+
+	if err := store.Delete(child); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(child); !errors.As(err, &ErrNotFound{}) {
+		t.Errorf("Get() after Delete() error = %v", err)
+	}
+}